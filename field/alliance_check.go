@@ -0,0 +1,60 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Detection of teams placed on the opposite alliance from the one they were scheduled for, which usually points to a
+// substitution or lineup edit that swapped teams between the alliances.
+
+package field
+
+import "log"
+
+// A team placed in a station of the opposite alliance from the one it is scheduled for in the match record.
+type AllianceMismatch struct {
+	Station          string
+	TeamId           int
+	ScheduledStation string
+}
+
+// Compares the alliance of each team in the current match against the lineup it was scheduled with and logs a warning
+// for each team found on the other alliance. Matches whose lineup hasn't been changed since it was scheduled have
+// nothing to compare against and are skipped.
+func (arena *Arena) checkScheduledAlliances() {
+	arena.allianceMismatches = nil
+	match := arena.CurrentMatch
+	if len(match.ScheduledTeams) != len(stationKeys) {
+		return
+	}
+
+	scheduledStations := make(map[int]string)
+	for i, station := range stationKeys {
+		if teamId := match.ScheduledTeams[i]; teamId != 0 {
+			scheduledStations[teamId] = station
+		}
+	}
+	for _, station := range stationKeys {
+		teamId := *stationMatchTeamFields[station](match)
+		scheduledStation, ok := scheduledStations[teamId]
+		if teamId == 0 || !ok || scheduledStation[0] == station[0] {
+			continue
+		}
+		arena.allianceMismatches = append(arena.allianceMismatches, AllianceMismatch{station, teamId, scheduledStation})
+		log.Printf(
+			"WARNING: Team %d is in station %s of match %s but is scheduled for the %s alliance in station %s.",
+			teamId, station, match.DisplayName, allianceName(scheduledStation), scheduledStation,
+		)
+	}
+}
+
+// Returns the teams of the current match placed on the opposite alliance from their schedule, in station order.
+func (arena *Arena) AllianceMismatches() []AllianceMismatch {
+	allianceMismatches := make([]AllianceMismatch, len(arena.allianceMismatches))
+	copy(allianceMismatches, arena.allianceMismatches)
+	return allianceMismatches
+}
+
+// Returns the lowercase name of the alliance that the given station belongs to.
+func allianceName(station string) string {
+	if station[0] == 'R' {
+		return "red"
+	}
+	return "blue"
+}
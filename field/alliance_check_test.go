@@ -0,0 +1,63 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScheduledAllianceMismatches(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 101, Red2: 102, Red3: 103, Blue1: 104,
+		Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Empty(t, arena.AllianceMismatches())
+
+	// Reordering teams within their own alliance isn't flagged.
+	reorderedMatch := match
+	reorderedMatch.Red1, reorderedMatch.Red3 = match.Red3, match.Red1
+	assert.Nil(t, arena.LoadMatch(&reorderedMatch))
+	assert.Empty(t, arena.AllianceMismatches())
+
+	// A lineup with teams moved across alliances from its recorded schedule is flagged without failing to load.
+	swappedMatch := match
+	swappedMatch.ScheduledTeams = []int{101, 102, 103, 104, 105, 106}
+	swappedMatch.Red1, swappedMatch.Red2, swappedMatch.Red3 = match.Blue1, match.Blue2, match.Blue3
+	swappedMatch.Blue1, swappedMatch.Blue2, swappedMatch.Blue3 = match.Red1, match.Red2, match.Red3
+	assert.Nil(t, arena.LoadMatch(&swappedMatch))
+	mismatches := arena.AllianceMismatches()
+	if assert.Equal(t, 6, len(mismatches)) {
+		assert.Equal(t, AllianceMismatch{"R1", 104, "B1"}, mismatches[0])
+		assert.Equal(t, AllianceMismatch{"B3", 103, "R3"}, mismatches[5])
+	}
+	assert.Equal(t, 104, arena.AllianceStations["R1"].Team.Id)
+	getStatus := func() map[string]interface{} {
+		var status map[string]interface{}
+		data, _ := json.Marshal(arena.generateArenaStatusMessage())
+		assert.Nil(t, json.Unmarshal(data, &status))
+		return status
+	}
+	assert.Equal(t, 6, len(getStatus()["AllianceMismatches"].([]interface{})))
+
+	// Substituting a team across alliances is flagged, and moving it back clears the warning.
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(0, "B1"))
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(104, "R2"))
+	assert.Equal(t, []AllianceMismatch{{"R2", 104, "B1"}}, arena.AllianceMismatches())
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(102, "R2"))
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(104, "B1"))
+	assert.Empty(t, arena.AllianceMismatches())
+	assert.Empty(t, getStatus()["AllianceMismatches"])
+
+	// Loading another match clears the warning.
+	arena.LoadTestMatch()
+	assert.Empty(t, arena.AllianceMismatches())
+}
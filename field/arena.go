@@ -14,11 +14,16 @@ import (
 	"github.com/Team254/cheesy-arena-lite/partner"
 	"github.com/Team254/cheesy-arena-lite/plc"
 	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	arenaLoopPeriodMs        = 10
+	defaultArenaLoopPeriodMs = 10
 	dsPacketPeriodMs         = 250
 	periodicTaskPeriodSec    = 30
 	matchEndScoreDwellSec    = 3
@@ -26,6 +31,30 @@ const (
 	preLoadNextMatchDelaySec = 5
 	earlyLateThresholdMin    = 2.5
 	MaxMatchGapMin           = 20
+	defaultRedAllianceLabel  = "Red"
+	defaultBlueAllianceLabel = "Blue"
+	// Caps the configurable spacing between driver station packets so that spreading all six uses at most half of the
+	// packet period.
+	maxDsPacketSpacingMs = dsPacketPeriodMs / 10
+)
+
+// Alliance station keys in canonical display order.
+var stationKeys = []string{"R1", "R2", "R3", "B1", "B2", "B3"}
+
+// Field of the match record holding the team assigned to each alliance station.
+var stationMatchTeamFields = map[string]func(match *model.Match) *int{
+	"R1": func(match *model.Match) *int { return &match.Red1 },
+	"R2": func(match *model.Match) *int { return &match.Red2 },
+	"R3": func(match *model.Match) *int { return &match.Red3 },
+	"B1": func(match *model.Match) *int { return &match.Blue1 },
+	"B2": func(match *model.Match) *int { return &match.Blue2 },
+	"B3": func(match *model.Match) *int { return &match.Blue3 },
+}
+
+// Identifiers for methods that operate on a single alliance's stations.
+const (
+	RedAlliance = iota
+	BlueAlliance
 )
 
 // Progression of match states.
@@ -45,6 +74,7 @@ const (
 
 type Arena struct {
 	Database         *model.Database
+	Datastore        Datastore
 	EventSettings    *model.EventSettings
 	accessPoint      network.AccessPoint
 	accessPoint2     network.AccessPoint
@@ -56,11 +86,18 @@ type Arena struct {
 	ArenaNotifiers
 	MatchState
 	lastMatchState             MatchState
+	MatchTiming                game.Timing
+	MatchSounds                []*game.MatchSound
 	CurrentMatch               *model.Match
 	MatchStartTime             time.Time
 	LastMatchTimeSec           float64
+	matchTimeMutex             sync.Mutex
+	matchTimeStartRef          time.Time
+	maxMatchTimeSec            float64
 	RedScore                   *game.Score
 	BlueScore                  *game.Score
+	scoreMutex                 sync.Mutex
+	startMatchMutex            sync.Mutex
 	lastDsPacketTime           time.Time
 	lastPeriodicTaskTime       time.Time
 	EventStatus                EventStatus
@@ -76,41 +113,172 @@ type Arena struct {
 	LowerThird                 *model.LowerThird
 	ShowLowerThird             bool
 	MuteMatchSounds            bool
+	RedAllianceLabel           string
+	BlueAllianceLabel          string
+	Notes                      string
+	FieldTestMode              bool
+	fieldTestLinkedStations    map[string]bool
 	matchAborted               bool
+	matchLoadedTime            time.Time
+	autoBypassApplied          bool
+	teleopAdjustmentSec        int
+	endgameStarted             bool
+	autoEnablePending          bool
+	loopOverrunMonitor         loopOverrunMonitor
+	totalLossSince             time.Time
+	statusVersion              statusVersionTracker
+	sentStatusVersion          uint64
+	lastPolledStatus           polledStatus
 	soundsPlayed               map[*game.MatchSound]struct{}
+
+	// Function used to decide the winner of a match from the two alliance score summaries. Defaults to a simple
+	// numeric comparison of the total score but can be replaced to implement game-specific tiebreakers.
+	MatchStatusDeterminer func(redScoreSummary, blueScoreSummary *game.ScoreSummary) game.MatchStatus
+
+	// Optional function allowing external systems (e.g. a "field ready" button) to block the match from starting. It
+	// is checked after all other start conditions and a non-nil error is returned to the caller of StartMatch. It is
+	// also consulted on every arena status update, so it should return quickly.
+	StartVeto func() error
+
+	// Whether a robot stopped during the autonomous period stays e-stopped for the rest of the match. By default the
+	// stop is cleared at the end of autonomous so that the robot re-enables for teleop. Set from the event settings.
+	AstopLatchesThroughMatch bool
+
+	// Optional function used to sound an external horn. It is called once with HornMatchStart when a match starts and
+	// once with either HornMatchEnd when it runs to completion or HornMatchAbort when it is aborted, but not for
+	// timeouts. It is called synchronously from the match flow, so it should return quickly.
+	OnHorn func(event string)
+
+	// Address and ports on which the driver station listeners are opened. They default to the field network's server
+	// address and the standard FMS ports, and can be changed before Run so that several arenas can share a host.
+	DsListenIpAddress string
+	DsTcpListenPort   int
+	DsUdpListenPort   int
+
+	// Guards the team, driver station connection, bypass and stops of each alliance station against concurrent changes
+	// by operators, the driver station listener and the arena loop.
+	allianceStationsMutex sync.Mutex
+
+	// Teams registered for the event, checked when assigning teams if roster enforcement is enabled. Guarded by
+	// allianceStationsMutex.
+	eventRoster map[int]struct{}
+
+	// Robot enabled outside of a match with DiagnosticEnable, or nil if there is none. When both are needed,
+	// allianceStationsMutex is locked first.
+	diagnosticEnableMutex sync.Mutex
+	diagnosticEnable      *diagnosticEnable
+
+	// Stations of the teams in the match being pre-warmed, keyed by team ID, and the connections accepted from their
+	// driver stations ahead of it being loaded. Guarded by allianceStationsMutex.
+	prewarmStations  map[int]string
+	prewarmedDsConns map[int]*DriverStationConnection
+
+	// Keys of the extra non-scoring stations registered with AddNeutralStation.
+	neutralStationKeys []string
+
+	// Copies of the teams in the lineup as of its last change, keyed by station, for the match load message.
+	lineupTeams map[string]*model.Team
+
+	// Stations of the current match whose team differs from the scheduled one.
+	lineupChanges []LineupChange
+
+	// Teams of the current match placed on the opposite alliance from the stored record of the match.
+	allianceMismatches []AllianceMismatch
+
+	// One-time token that must be given to StartMatchWithToken to start the loaded match, or blank if none is
+	// required. Guarded by startMatchMutex.
+	startToken string
+
+	// Whether the current match was loaded with LoadReplay, allowing it to be started again once complete.
+	replayLoaded bool
+
+	// Whether the FTA has confirmed the physical field reset since the last match ended.
+	fieldResetConfirmed bool
+
+	// Whether the match has ended, after which live scoring inputs can no longer change the scores. Guarded by
+	// scoreMutex.
+	liveScoringClosed bool
+
+	// Whether the scores of the current match have been finalized in manual scoring mode, and the result decided when
+	// they were. Guarded by scoreMutex.
+	scoresFinalized bool
+	finalizedStatus game.MatchStatus
+
+	packetTraceMutex sync.Mutex
+	packetTracer     *packetTracer
+
+	faultsMutex sync.Mutex
+	faults      []Fault
+
+	// Set to 1 if the driver station listener failed to start; accessed atomically since it is written by the listener.
+	dsNetworkUnavailable int32
+
+	// Milliseconds the arena loop sleeps between iterations; accessed atomically since it may be changed at runtime.
+	loopPeriodMs int32
+
+	// Source of the current time for the match clock; replaceable in tests to simulate wall-clock jumps.
+	now func() time.Time
 }
 
 type AllianceStation struct {
-	DsConn   *DriverStationConnection
-	Ethernet bool
-	Astop    bool
-	Estop    bool
-	Bypass   bool
-	Team     *model.Team
+	DsConn              *DriverStationConnection
+	Ethernet            bool
+	Astop               bool
+	Estop               bool
+	Bypass              bool
+	Neutral             bool
+	Team                *model.Team
+	Surrogate           bool
+	LinkedSince         time.Time
+	LastDroppedAt       time.Time
+	EnabledFault        bool
+	Card                int
+	EnabledPacketCount  int
+	DisabledPacketCount int
+	ReenabledAfterAstop bool
+	astopCleared        bool
+	stopsRestored       bool
+	wasRobotLinked      bool
+	statusHistory       *dsStatusHistory
+	pendingTcpConn      net.Conn
+
+	// Time the robot has spent unlinked while commanded enabled during the current match, and when that was last
+	// checked. Guarded by the arena's allianceStationsMutex.
+	downtimeSec            float64
+	lastDowntimeSampleTime time.Time
+
+	// Exponential moving average of the robot's battery voltage, or zero if it isn't linked. Guarded by the arena's
+	// allianceStationsMutex.
+	batteryEma float64
 }
 
 // Creates the arena and sets it to its initial state.
 func NewArena(dbPath string) (*Arena, error) {
 	arena := new(Arena)
 	arena.configureNotifiers()
-
-	var err error
-	arena.Database, err = model.OpenDatabase(dbPath)
+	arena.MatchStatusDeterminer = game.DetermineMatchStatus
+	arena.MatchTiming = game.DefaultMatchTiming
+	arena.DsListenIpAddress = network.ServerIpAddress
+	arena.DsTcpListenPort = driverStationTcpListenPort
+	arena.DsUdpListenPort = driverStationUdpReceivePort
+	arena.now = time.Now
+	arena.loopPeriodMs = defaultArenaLoopPeriodMs
+
+	database, err := model.OpenDatabase(dbPath)
 	if err != nil {
 		return nil, err
 	}
+	arena.SetDatabase(database)
 	err = arena.LoadSettings()
 	if err != nil {
 		return nil, err
 	}
 
 	arena.AllianceStations = make(map[string]*AllianceStation)
-	arena.AllianceStations["R1"] = new(AllianceStation)
-	arena.AllianceStations["R2"] = new(AllianceStation)
-	arena.AllianceStations["R3"] = new(AllianceStation)
-	arena.AllianceStations["B1"] = new(AllianceStation)
-	arena.AllianceStations["B2"] = new(AllianceStation)
-	arena.AllianceStations["B3"] = new(AllianceStation)
+	for _, station := range arena.StationKeys() {
+		arena.AllianceStations[station] = new(AllianceStation)
+	}
+	arena.SetDsStatusHistorySize(defaultDsStatusHistorySize)
 
 	arena.Displays = make(map[string]*Display)
 
@@ -131,7 +299,7 @@ func NewArena(dbPath string) (*Arena, error) {
 
 // Loads or reloads the event settings upon initial setup or change.
 func (arena *Arena) LoadSettings() error {
-	settings, err := arena.Database.GetEventSettings()
+	settings, err := arena.Datastore.GetEventSettings()
 	if err != nil {
 		return err
 	}
@@ -145,6 +313,8 @@ func (arena *Arena) LoadSettings() error {
 	arena.networkSwitch = network.NewSwitch(settings.SwitchAddress, settings.SwitchPassword)
 	arena.Plc.SetAddress(settings.PlcAddress)
 	arena.TbaClient = partner.NewTbaClient(settings.TbaEventCode, settings.TbaSecretId, settings.TbaSecret)
+	arena.SetEventRoster(settings.EventRoster)
+	arena.AstopLatchesThroughMatch = settings.AutoEstopLatchesThroughMatch
 
 	if arena.EventSettings.NetworkSecurityEnabled && arena.MatchState == PreMatch {
 		if err = arena.accessPoint.ConfigureAdminWifi(); err != nil {
@@ -155,12 +325,12 @@ func (arena *Arena) LoadSettings() error {
 		}
 	}
 
-	game.MatchTiming.WarmupDurationSec = settings.WarmupDurationSec
-	game.MatchTiming.AutoDurationSec = settings.AutoDurationSec
-	game.MatchTiming.PauseDurationSec = settings.PauseDurationSec
-	game.MatchTiming.TeleopDurationSec = settings.TeleopDurationSec
-	game.MatchTiming.WarningRemainingDurationSec = settings.WarningRemainingDurationSec
-	game.UpdateMatchSounds()
+	arena.MatchTiming.WarmupDurationSec = settings.WarmupDurationSec
+	arena.MatchTiming.AutoDurationSec = settings.AutoDurationSec
+	arena.MatchTiming.PauseDurationSec = settings.PauseDurationSec
+	arena.MatchTiming.TeleopDurationSec = settings.TeleopDurationSec
+	arena.MatchTiming.WarningRemainingDurationSec = settings.WarningRemainingDurationSec
+	arena.MatchSounds = game.NewMatchSounds(arena.MatchTiming)
 	arena.MatchTimingNotifier.Notify()
 
 	// Reconstruct the playoff bracket in memory.
@@ -183,7 +353,7 @@ func (arena *Arena) CreatePlayoffBracket() error {
 	case "double":
 		arena.PlayoffBracket, err = bracket.NewDoubleEliminationBracket(arena.EventSettings.NumElimAlliances)
 	default:
-		err = fmt.Errorf("Invalid playoff type: %v", arena.EventSettings.ElimType)
+		err = newArenaError(InvalidSettingError, "Invalid playoff type: %v", arena.EventSettings.ElimType)
 	}
 	return err
 }
@@ -191,7 +361,7 @@ func (arena *Arena) CreatePlayoffBracket() error {
 // Traverses the in-memory playoff bracket to populate alliances, create matches, and assess winners. Does nothing if
 // the bracket has not been created.are
 func (arena *Arena) UpdatePlayoffBracket(startTime *time.Time) error {
-	alliances, err := arena.Database.GetAllAlliances()
+	alliances, err := arena.Datastore.GetAllAlliances()
 	if err != nil {
 		return err
 	}
@@ -204,33 +374,25 @@ func (arena *Arena) UpdatePlayoffBracket(startTime *time.Time) error {
 // Sets up the arena for the given match.
 func (arena *Arena) LoadMatch(match *model.Match) error {
 	if arena.MatchState != PreMatch {
-		return fmt.Errorf("Cannot load match while there is a match still in progress or with results pending.")
+		return newArenaError(
+			InvalidStateError, "Cannot load match while there is a match still in progress or with results pending.",
+		)
 	}
 
-	arena.CurrentMatch = match
-	err := arena.assignTeam(match.Red1, "R1")
-	if err != nil {
+	// Validate the whole lineup up front so that a bad match fails without any stations having been changed.
+	if err := arena.validateMatchTeams(match); err != nil {
 		return err
 	}
-	err = arena.assignTeam(match.Red2, "R2")
-	if err != nil {
-		return err
-	}
-	err = arena.assignTeam(match.Red3, "R3")
-	if err != nil {
-		return err
-	}
-	err = arena.assignTeam(match.Blue1, "B1")
-	if err != nil {
-		return err
-	}
-	err = arena.assignTeam(match.Blue2, "B2")
-	if err != nil {
+
+	if err := arena.assignMatchTeams(match); err != nil {
 		return err
 	}
-	err = arena.assignTeam(match.Blue3, "B3")
-	if err != nil {
-		return err
+	arena.endPrewarm()
+	arena.CurrentMatch = match
+	surrogates := []bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate, match.Blue1IsSurrogate,
+		match.Blue2IsSurrogate, match.Blue3IsSurrogate}
+	for i, station := range arena.StationKeys() {
+		arena.AllianceStations[station].Surrogate = surrogates[i]
 	}
 
 	arena.setupNetwork([6]*model.Team{arena.AllianceStations["R1"].Team, arena.AllianceStations["R2"].Team,
@@ -239,13 +401,41 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 
 	// Reset the arena state and game scores.
 	arena.soundsPlayed = make(map[*game.MatchSound]struct{})
+	arena.scoreMutex.Lock()
 	arena.RedScore = new(game.Score)
 	arena.BlueScore = new(game.Score)
+	arena.liveScoringClosed = false
+	arena.resetFinalizedScores()
+	arena.scoreMutex.Unlock()
 	arena.FieldVolunteers = false
 	arena.FieldReset = false
+	arena.RedAllianceLabel = defaultRedAllianceLabel
+	arena.BlueAllianceLabel = defaultBlueAllianceLabel
+	arena.Notes = ""
+	arena.matchLoadedTime = arena.now()
+	arena.autoBypassApplied = false
+	arena.replayLoaded = false
+	arena.startMatchMutex.Lock()
+	arena.startToken = ""
+	arena.startMatchMutex.Unlock()
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.EnabledFault = false
+		allianceStation.Card = game.NoCard
+		allianceStation.ReenabledAfterAstop = false
+		allianceStation.astopCleared = false
+		allianceStation.stopsRestored = false
+		allianceStation.resetDowntime()
+		allianceStation.EnabledPacketCount = 0
+		allianceStation.DisabledPacketCount = 0
+		allianceStation.batteryEma = 0
+	}
 	arena.Plc.ResetMatch()
 
 	// Notify any listeners about the new match.
+	arena.markStatusChanged()
+	arena.checkLineupChanges()
+	arena.checkScheduledAlliances()
+	arena.resolveLineup()
 	arena.MatchLoadNotifier.Notify()
 	arena.RealtimeScoreNotifier.Notify()
 	arena.AllianceStationDisplayMode = "match"
@@ -254,11 +444,33 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 	return nil
 }
 
+// Loads the given match in order to play it again, even if it is already complete. Only needed when the event settings
+// require replays to be explicit.
+func (arena *Arena) LoadReplay(match *model.Match) error {
+	if err := arena.LoadMatch(match); err != nil {
+		return err
+	}
+	arena.replayLoaded = true
+	return nil
+}
+
 // Sets a new test match containing no teams as the current match.
 func (arena *Arena) LoadTestMatch() error {
 	return arena.LoadMatch(&model.Match{Type: "test", DisplayName: "Test Match"})
 }
 
+// Abandons the currently loaded match before it has started, closing any driver station connections that were opened
+// for it and leaving an empty test match in its place. Station bypass settings are preserved.
+func (arena *Arena) UnloadMatch() error {
+	if arena.MatchState != PreMatch {
+		return newArenaError(
+			InvalidStateError, "Cannot unload match while there is a match still in progress or with results pending.",
+		)
+	}
+	log.Printf("Unloading match %s.", arena.CurrentMatch.DisplayName)
+	return arena.LoadTestMatch()
+}
+
 // Loads the first unplayed match of the current match type.
 func (arena *Arena) LoadNextMatch() error {
 	nextMatch, err := arena.getNextMatch(false)
@@ -271,48 +483,112 @@ func (arena *Arena) LoadNextMatch() error {
 	return arena.LoadMatch(nextMatch)
 }
 
+// Loads the match of the given type having the given schedule number (e.g. qualification match 42). The type is
+// required since numbering restarts for each match type.
+func (arena *Arena) LoadMatchByNumber(matchType string, number int) error {
+	match, err := arena.Datastore.GetMatchByName(matchType, strconv.Itoa(number))
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		return newArenaError(MatchNotFoundError, "No %s match with number %d exists.", matchType, number)
+	}
+	return arena.LoadMatch(match)
+}
+
+// Returns the number of matches of the given type that have yet to be played.
+func (arena *Arena) RemainingMatchCount(matchType string) (int, error) {
+	return arena.countMatches(matchType, false)
+}
+
+// Returns the number of matches of the given type that have already been played.
+func (arena *Arena) CompletedMatchCount(matchType string) (int, error) {
+	return arena.countMatches(matchType, true)
+}
+
 // Assigns the given team to the given station, also substituting it into the match record.
 func (arena *Arena) SubstituteTeam(teamId int, station string) error {
 	if !arena.CurrentMatch.ShouldAllowSubstitution() {
-		return fmt.Errorf("Can't substitute teams for qualification matches.")
+		return newArenaError(SubstitutionNotAllowedError, "Can't substitute teams for qualification matches.")
+	}
+	return arena.substituteTeam(teamId, station)
+}
+
+// Assigns the given team to the given station regardless of match type, for use when a backup or surrogate swap in
+// a qualification or playoff match has been authorized. The match is flagged as having had a substitution so that
+// it can be reported on.
+func (arena *Arena) AuthorizedSubstituteTeam(teamId int, station string) error {
+	if teamId != 0 {
+		team, err := arena.Datastore.GetTeamById(teamId)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return newArenaError(TeamNotFoundError, "Cannot substitute nonexistent team %d.", teamId)
+		}
+	}
+	if err := arena.substituteTeam(teamId, station); err != nil {
+		return err
+	}
+	if arena.CurrentMatch.Type != "test" && !arena.CurrentMatch.TeamsSubstituted {
+		arena.CurrentMatch.TeamsSubstituted = true
+		return arena.Datastore.UpdateMatch(arena.CurrentMatch)
+	}
+	return nil
+}
+
+func (arena *Arena) substituteTeam(teamId int, station string) error {
+	// Check the station up front so that the match record can't fall out of sync with the assigned team.
+	matchTeamField, ok := stationMatchTeamFields[station]
+	if !ok {
+		return newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
 	}
 	err := arena.assignTeam(teamId, station)
 	if err != nil {
 		return err
 	}
-	switch station {
-	case "R1":
-		arena.CurrentMatch.Red1 = teamId
-	case "R2":
-		arena.CurrentMatch.Red2 = teamId
-	case "R3":
-		arena.CurrentMatch.Red3 = teamId
-	case "B1":
-		arena.CurrentMatch.Blue1 = teamId
-	case "B2":
-		arena.CurrentMatch.Blue2 = teamId
-	case "B3":
-		arena.CurrentMatch.Blue3 = teamId
-	}
+	arena.recordScheduledLineup()
+	*matchTeamField(arena.CurrentMatch) = teamId
+	arena.checkLineupChanges()
+	arena.checkScheduledAlliances()
 	arena.setupNetwork([6]*model.Team{arena.AllianceStations["R1"].Team, arena.AllianceStations["R2"].Team,
 		arena.AllianceStations["R3"].Team, arena.AllianceStations["B1"].Team, arena.AllianceStations["B2"].Team,
 		arena.AllianceStations["B3"].Team})
+	arena.resolveLineup()
 	arena.MatchLoadNotifier.Notify()
 
 	if arena.CurrentMatch.Type != "test" {
-		arena.Database.UpdateMatch(arena.CurrentMatch)
+		arena.Datastore.UpdateMatch(arena.CurrentMatch)
 	}
 	return nil
 }
 
-// Starts the match if all conditions are met.
+// Starts the match if all conditions are met. Fails if a start confirmation token has been set for the match.
 func (arena *Arena) StartMatch() error {
+	return arena.StartMatchWithToken("")
+}
+
+// Starts the match if all conditions are met and the given token matches the one set with SetStartToken, if any.
+func (arena *Arena) StartMatchWithToken(token string) error {
+	// Serialize start requests so that a rapid double-click can't pass the pre-match check twice before the state
+	// advances; a second caller will see the START_MATCH state and be rejected.
+	arena.startMatchMutex.Lock()
+	defer arena.startMatchMutex.Unlock()
+
+	if arena.startToken != "" && token != arena.startToken {
+		if token == "" {
+			return newArenaError(NotReadyError, "Cannot start match without its start confirmation token.")
+		}
+		return newArenaError(NotReadyError, "Cannot start match with an incorrect start confirmation token.")
+	}
 	err := arena.checkCanStartMatch()
 	if err == nil {
+		arena.startToken = ""
+
 		// Save the match start time and game-specifc data to the database for posterity.
 		arena.CurrentMatch.StartedAt = time.Now()
 		if arena.CurrentMatch.Type != "test" {
-			arena.Database.UpdateMatch(arena.CurrentMatch)
+			arena.Datastore.UpdateMatch(arena.CurrentMatch)
 		}
 		arena.updateCycleTime(arena.CurrentMatch.StartedAt)
 
@@ -329,7 +605,7 @@ func (arena *Arena) StartMatch() error {
 			if allianceStation.Team != nil && !allianceStation.Team.HasConnected && allianceStation.DsConn != nil &&
 				allianceStation.DsConn.RobotLinked {
 				allianceStation.Team.HasConnected = true
-				arena.Database.UpdateTeam(allianceStation.Team)
+				arena.Datastore.UpdateTeam(allianceStation.Team)
 			}
 		}
 
@@ -338,23 +614,62 @@ func (arena *Arena) StartMatch() error {
 	return err
 }
 
+// Requires the given token to be supplied to StartMatchWithToken before the loaded match can be started, as a
+// deliberate second step for high-stakes matches. The token is used up once the match starts and is cleared when
+// another match is loaded; setting a blank token removes the requirement.
+func (arena *Arena) SetStartToken(token string) error {
+	arena.startMatchMutex.Lock()
+	if arena.MatchState != PreMatch {
+		arena.startMatchMutex.Unlock()
+		return newArenaError(InvalidStateError, "Cannot set a start confirmation token once the match has started.")
+	}
+	arena.startToken = strings.TrimSpace(token)
+	arena.startMatchMutex.Unlock()
+
+	arena.notifyStatusChanged()
+	return nil
+}
+
+// Returns true if the loaded match can only be started with a start confirmation token.
+func (arena *Arena) StartTokenRequired() bool {
+	arena.startMatchMutex.Lock()
+	defer arena.startMatchMutex.Unlock()
+	return arena.startToken != ""
+}
+
+// Moves the arena into the given match state. Takes startMatchMutex so that StartMatchWithToken and SetStartToken,
+// which check the state under that lock, never see it change partway through.
+func (arena *Arena) setMatchState(state MatchState) {
+	arena.startMatchMutex.Lock()
+	arena.MatchState = state
+	arena.startMatchMutex.Unlock()
+	arena.markStatusChanged()
+}
+
 // Kills the current match or timeout if it is underway.
 func (arena *Arena) AbortMatch() error {
-	if arena.MatchState == PreMatch || arena.MatchState == PostMatch || arena.MatchState == PostTimeout {
-		return fmt.Errorf("Cannot abort match when it is not in progress.")
+	if !arena.MatchInProgress() && arena.MatchState != TimeoutActive {
+		return newArenaError(InvalidStateError, "Cannot abort match when it is not in progress.")
 	}
 
 	if arena.MatchState == TimeoutActive {
 		// Handle by advancing the timeout clock to the end and letting the regular logic deal with it.
-		arena.MatchStartTime = time.Now().Add(-time.Second * time.Duration(game.MatchTiming.TimeoutDurationSec))
+		arena.MatchStartTime = arena.now().Add(-time.Second * time.Duration(arena.MatchTiming.TimeoutDurationSec))
 		return nil
 	}
 
 	if arena.MatchState != WarmupPeriod {
 		arena.playSound("abort")
 	}
-	arena.MatchState = PostMatch
+	arena.setMatchState(PostMatch)
 	arena.matchAborted = true
+	arena.closeLiveScoring()
+	arena.soundHorn(HornMatchAbort)
+	arena.fieldResetConfirmed = false
+	arena.saveMatchNotes()
+
+	// Disable the robots immediately rather than waiting for the next periodic packet to go out.
+	arena.sendDsPacket(false, false)
 	arena.AudienceDisplayMode = "blank"
 	arena.AudienceDisplayModeNotifier.Notify()
 	arena.AllianceStationDisplayMode = "logo"
@@ -362,35 +677,166 @@ func (arena *Arena) AbortMatch() error {
 	return nil
 }
 
+// Returns the keys of all alliance stations in canonical order (R1, R2, R3, B1, B2, B3).
+func (arena *Arena) StationKeys() []string {
+	return append([]string(nil), stationKeys...)
+}
+
+// Summarizes how many of the given alliance's robots are linked, for display on audience and queueing screens.
+// Bypassed stations count towards neither the connected nor the total figure, since they aren't expected to connect;
+// as a result allLinked is also true if every station of the alliance is bypassed. Unknown alliances return zeroes.
+func (arena *Arena) AllianceConnectionStatus(alliance int) (connected int, total int, allLinked bool) {
+	stations := allianceStationKeys(alliance)
+	if stations == nil {
+		return 0, 0, false
+	}
+
+	for _, station := range stations {
+		allianceStation := arena.AllianceStations[station]
+		if allianceStation.Bypass {
+			continue
+		}
+		total++
+		if allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked {
+			connected++
+		}
+	}
+	return connected, total, connected == total
+}
+
+// Returns the keys of the given alliance's stations, or nil if the alliance is invalid.
+func allianceStationKeys(alliance int) []string {
+	switch alliance {
+	case RedAlliance:
+		return stationKeys[:3]
+	case BlueAlliance:
+		return stationKeys[3:]
+	default:
+		return nil
+	}
+}
+
+// Sets or clears the bypass of the given station. A station can be bypassed at any time, which disables its robot, but
+// its bypass can't be cleared while a match is in progress since that would enable the robot mid-match.
+func (arena *Arena) SetBypass(station string, bypass bool) error {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	arena.allianceStationsMutex.Lock()
+	wasBypassed := allianceStation.Bypass
+	arena.allianceStationsMutex.Unlock()
+	if !bypass && wasBypassed && arena.MatchInProgress() {
+		return newArenaError(
+			InvalidStateError, "Cannot clear the bypass for station %s while a match is in progress.", station,
+		)
+	}
+	arena.setStationBypass(allianceStation, bypass)
+	arena.markStatusChanged()
+	return nil
+}
+
+// Sets the bypass of the given station and, if it changed, sends the lineup in the match load message out again since
+// it shows the bypasses.
+func (arena *Arena) setStationBypass(allianceStation *AllianceStation, bypass bool) {
+	arena.allianceStationsMutex.Lock()
+	changed := allianceStation.Bypass != bypass
+	allianceStation.Bypass = bypass
+	arena.allianceStationsMutex.Unlock()
+	if changed {
+		arena.MatchLoadNotifier.Notify()
+	}
+}
+
+// Sets or clears the bypass of all of the given alliance's stations at once, e.g. for a no-show alliance or a
+// one-alliance demo. The same restrictions apply as for SetBypass.
+func (arena *Arena) BypassAlliance(alliance int, bypass bool) error {
+	stations := allianceStationKeys(alliance)
+	if stations == nil {
+		return newArenaError(InvalidStationError, "Invalid alliance %d.", alliance)
+	}
+	for _, station := range stations {
+		if err := arena.SetBypass(station, bypass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sets or clears the bypass of whichever station the given team is currently assigned to. The same restrictions apply
+// as for SetBypass.
+func (arena *Arena) BypassTeam(teamId int, bypass bool) error {
+	station, ok := arena.FindTeamStation(teamId)
+	if !ok {
+		return newArenaError(TeamNotFoundError, "Team %d is not on the field.", teamId)
+	}
+	return arena.SetBypass(station, bypass)
+}
+
+// Returns true if a match has been started and hasn't yet ended or been aborted. Timeouts don't count as matches.
+func (arena *Arena) MatchInProgress() bool {
+	switch arena.MatchState {
+	case StartMatch, WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns true if a timeout is running or its timer is still being displayed after it has ended.
+func (arena *Arena) timeoutInProgress() bool {
+	return arena.MatchState == TimeoutActive || arena.MatchState == PostTimeout
+}
+
 // Clears out the match and resets the arena state unless there is a match underway.
 func (arena *Arena) ResetMatch() error {
-	if arena.MatchState != PostMatch && arena.MatchState != PreMatch {
-		return fmt.Errorf("Cannot reset match while it is in progress.")
+	if arena.hasUncommittedResults() {
+		return newArenaError(
+			InvalidStateError, "Cannot reset match %s until its results have been committed.",
+			arena.CurrentMatch.DisplayName,
+		)
 	}
-	arena.MatchState = PreMatch
+	return arena.ForceReset()
+}
+
+// Like ResetMatch, but discards the results of a match that has ended without them having been committed.
+func (arena *Arena) ForceReset() error {
+	if arena.MatchInProgress() || arena.timeoutInProgress() {
+		return newArenaError(InvalidStateError, "Cannot reset match while it is in progress.")
+	}
+	if arena.hasUncommittedResults() {
+		log.Printf("Discarding the uncommitted results of match %s.", arena.CurrentMatch.DisplayName)
+	}
+	arena.setMatchState(PreMatch)
 	arena.matchAborted = false
-	arena.AllianceStations["R1"].Bypass = false
-	arena.AllianceStations["R2"].Bypass = false
-	arena.AllianceStations["R3"].Bypass = false
-	arena.AllianceStations["B1"].Bypass = false
-	arena.AllianceStations["B2"].Bypass = false
-	arena.AllianceStations["B3"].Bypass = false
+	for _, station := range arena.StationKeys() {
+		arena.setStationBypass(arena.AllianceStations[station], false)
+	}
 	arena.MuteMatchSounds = false
 	return nil
 }
 
+// Returns true if a real match has ended but its results haven't been committed since it was started.
+func (arena *Arena) hasUncommittedResults() bool {
+	matchType := arena.CurrentMatch.Type
+	return arena.MatchState == PostMatch && matchType != "" && matchType != "test" &&
+		!arena.CurrentMatch.ScoreCommittedAt.After(arena.CurrentMatch.StartedAt)
+}
+
 // Starts a timeout of the given duration.
 func (arena *Arena) StartTimeout(durationSec int) error {
 	if arena.MatchState != PreMatch {
-		return fmt.Errorf("Cannot start timeout while there is a match still in progress or with results pending.")
+		return newArenaError(
+			InvalidStateError, "Cannot start timeout while there is a match still in progress or with results pending.",
+		)
 	}
 
-	game.MatchTiming.TimeoutDurationSec = durationSec
-	game.UpdateMatchSounds()
+	arena.MatchTiming.TimeoutDurationSec = durationSec
+	arena.MatchSounds = game.NewMatchSounds(arena.MatchTiming)
 	arena.soundsPlayed = make(map[*game.MatchSound]struct{})
 	arena.MatchTimingNotifier.Notify()
-	arena.MatchState = TimeoutActive
-	arena.MatchStartTime = time.Now()
+	arena.setMatchState(TimeoutActive)
+	arena.MatchStartTime = arena.now()
 	arena.LastMatchTimeSec = -1
 	arena.AllianceStationDisplayMode = "timeout"
 	arena.AllianceStationDisplayModeNotifier.Notify()
@@ -398,6 +844,63 @@ func (arena *Arena) StartTimeout(durationSec int) error {
 	return nil
 }
 
+// Records a referee's card decision for the team in the given station for the current match. A red card disqualifies
+// the team, so its station is also bypassed.
+func (arena *Arena) SetCard(station string, card int) error {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	if allianceStation.Neutral {
+		return newArenaError(
+			InvalidStationError, "Cannot assign a card in neutral station '%s', which isn't part of either alliance.",
+			station,
+		)
+	}
+	if !game.IsValidCard(card) {
+		return newArenaError(InvalidCardError, "Invalid card value %d.", card)
+	}
+	allianceStation.Card = card
+	if card == game.RedCard {
+		arena.setStationBypass(allianceStation, true)
+	}
+	arena.notifyStatusChanged()
+	return nil
+}
+
+// Returns the cards assigned in the current match to the teams on each alliance, keyed by team number. Neutral stations
+// belong to neither alliance and are left out.
+func (arena *Arena) Cards() (map[string]int, map[string]int) {
+	redCards := make(map[string]int)
+	blueCards := make(map[string]int)
+	for station, allianceStation := range arena.AllianceStations {
+		if allianceStation.Neutral || allianceStation.Team == nil || allianceStation.Card == game.NoCard {
+			continue
+		}
+		if station[0] == 'R' {
+			redCards[strconv.Itoa(allianceStation.Team.Id)] = allianceStation.Card
+		} else {
+			blueCards[strconv.Itoa(allianceStation.Team.Id)] = allianceStation.Card
+		}
+	}
+	return redCards, blueCards
+}
+
+// Sets the names under which the red and blue alliances are shown on the displays for the current match, without
+// affecting any internal red/blue logic. An empty label reverts to the default. The labels are reset when the next
+// match is loaded.
+func (arena *Arena) SetAllianceLabels(redLabel, blueLabel string) {
+	if redLabel == "" {
+		redLabel = defaultRedAllianceLabel
+	}
+	if blueLabel == "" {
+		blueLabel = defaultBlueAllianceLabel
+	}
+	arena.RedAllianceLabel = redLabel
+	arena.BlueAllianceLabel = blueLabel
+	arena.notifyStatusChanged()
+}
+
 // Updates the audience display screen.
 func (arena *Arena) SetAudienceDisplayMode(mode string) {
 	if arena.AudienceDisplayMode != mode {
@@ -417,15 +920,247 @@ func (arena *Arena) SetAllianceStationDisplayMode(mode string) {
 	}
 }
 
-// Returns the fractional number of seconds since the start of the match.
+// Returns the fractional number of seconds since the start of the match. The value never falls below the high-water
+// mark recorded by the arena loop for the current start time, so that a backward jump in the system clock can't rewind
+// the match state machine.
 func (arena *Arena) MatchTimeSec() float64 {
 	if arena.MatchState == PreMatch || arena.MatchState == StartMatch || arena.MatchState == PostMatch {
 		return 0
+	}
+
+	matchTimeSec := arena.now().Sub(arena.MatchStartTime).Seconds()
+	arena.matchTimeMutex.Lock()
+	defer arena.matchTimeMutex.Unlock()
+	if arena.MatchStartTime.Equal(arena.matchTimeStartRef) && arena.maxMatchTimeSec > matchTimeSec {
+		return arena.maxMatchTimeSec
+	}
+	return matchTimeSec
+}
+
+// Records the current match time as the high-water mark for the current start time and returns it. Only the arena
+// loop calls this, so that reading the match time from elsewhere never changes it.
+func (arena *Arena) recordMatchTime() float64 {
+	matchTimeSec := arena.MatchTimeSec()
+	arena.matchTimeMutex.Lock()
+	defer arena.matchTimeMutex.Unlock()
+	if !arena.MatchStartTime.Equal(arena.matchTimeStartRef) {
+		// The match clock has been (re)started; discard the high-water mark from the previous start time.
+		arena.matchTimeStartRef = arena.MatchStartTime
+		arena.maxMatchTimeSec = 0
+	}
+	if matchTimeSec > arena.maxMatchTimeSec {
+		arena.maxMatchTimeSec = matchTimeSec
+	}
+	return matchTimeSec
+}
+
+// Returns the number of seconds since the current period of the match (or the current timeout) began, or zero outside
+// of one. There is no separate endgame period, so the endgame counts as part of teleop.
+func (arena *Arena) PeriodElapsedSec() float64 {
+	var periodStartSec float64
+	switch arena.MatchState {
+	case WarmupPeriod, TimeoutActive:
+		periodStartSec = 0
+	case AutoPeriod:
+		periodStartSec = float64(arena.MatchTiming.WarmupDurationSec)
+	case PausePeriod:
+		periodStartSec = arena.MatchTiming.GetDurationToAutoEnd().Seconds()
+	case TeleopPeriod:
+		periodStartSec = arena.MatchTiming.GetDurationToTeleopStart().Seconds()
+	default:
+		return 0
+	}
+	return math.Max(arena.MatchTimeSec()-periodStartSec, 0)
+}
+
+// Returns whether the configured delay between the start of autonomous and enabling the robots has elapsed at the given
+// match time, latching the result so that robots stay enabled for the rest of the period once it has.
+func (arena *Arena) checkAutoEnableDelay(matchTimeSec float64) bool {
+	enableTimeSec := float64(arena.MatchTiming.WarmupDurationSec) + float64(arena.EventSettings.EnableDelayMs)/1000
+	if matchTimeSec >= enableTimeSec {
+		arena.autoEnablePending = false
+	}
+	return !arena.autoEnablePending
+}
+
+// Returns the length of a full match in seconds, measured on the same clock as MatchTimeSec (i.e. including the warmup
+// period) and reflecting the current timing settings and any adjustment made to the running match's teleop period.
+func (arena *Arena) TotalMatchDurationSec() float64 {
+	return arena.teleopEndSec()
+}
+
+// Returns the audience-facing number of seconds remaining in the current period, which counts down through
+// autonomous and then resets to count down through teleop.
+func (arena *Arena) MatchCountdownSec() int {
+	return arena.countdownSec(int(arena.MatchTimeSec()))
+}
+
+// Returns the countdown for the given match time. The value latches at zero once a period has run out, including in
+// the few milliseconds between the buzzer and the arena loop moving on to the next state, and stays there through
+// the post-match period until the next match is loaded. It is also capped at the length of the period, so that a bad
+// match time never puts a negative or impossibly large countdown in front of the audience.
+func (arena *Arena) countdownSec(matchTimeSec int) int {
+	var remainingSec, periodSec int
+	switch arena.MatchState {
+	case PreMatch:
+		fallthrough
+	case StartMatch:
+		fallthrough
+	case WarmupPeriod:
+		return arena.MatchTiming.AutoDurationSec
+	case AutoPeriod:
+		periodSec = arena.MatchTiming.AutoDurationSec
+		remainingSec = arena.MatchTiming.WarmupDurationSec + arena.MatchTiming.AutoDurationSec - matchTimeSec
+	case TeleopPeriod:
+		periodSec = arena.MatchTiming.TeleopDurationSec + arena.teleopAdjustmentSec
+		remainingSec = arena.MatchTiming.WarmupDurationSec + arena.MatchTiming.AutoDurationSec +
+			arena.MatchTiming.PauseDurationSec + periodSec - matchTimeSec
+	case TimeoutActive:
+		periodSec = arena.MatchTiming.TimeoutDurationSec
+		remainingSec = arena.MatchTiming.TimeoutDurationSec - matchTimeSec
+	}
+	if remainingSec > periodSec {
+		remainingSec = periodSec
+	}
+	if remainingSec < 0 {
+		return 0
+	}
+	return remainingSec
+}
+
+// Adds a free-form note from the field staff to the current match, prefixed with the match time at which it was
+// taken, or with the time of day if it was taken before or after the match. Notes are saved to the match record when
+// the match ends; notes added after that are saved right away. Blank notes are ignored.
+func (arena *Arena) AppendNote(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	var note string
+	if arena.MatchInProgress() {
+		note = fmt.Sprintf("[T+%.1f] %s", arena.MatchTimeSec(), text)
 	} else {
-		return time.Since(arena.MatchStartTime).Seconds()
+		note = fmt.Sprintf("[%s] %s", arena.now().Format("15:04:05"), text)
+	}
+	if arena.Notes == "" {
+		arena.Notes = note
+	} else {
+		arena.Notes += "\n" + note
+	}
+	if arena.MatchState == PostMatch {
+		arena.saveMatchNotes()
+	}
+	arena.notifyStatusChanged()
+}
+
+// Copies the notes taken during the match to the match record and saves it.
+func (arena *Arena) saveMatchNotes() {
+	arena.CurrentMatch.Notes = arena.Notes
+	if arena.CurrentMatch.Type != "test" {
+		if err := arena.Datastore.UpdateMatch(arena.CurrentMatch); err != nil {
+			log.Printf("Failed to save notes for match %s: %v", arena.CurrentMatch.DisplayName, err)
+		}
 	}
 }
 
+// Lengthens (for a positive delta) or shortens the teleop period of the match in progress, for use in demos and
+// exhibitions. The adjustment lasts until the next match is started.
+func (arena *Arena) AdjustTeleopDuration(deltaSec int) error {
+	if arena.MatchState != TeleopPeriod {
+		return newArenaError(InvalidStateError, "Cannot adjust the teleop duration outside of the teleop period.")
+	}
+	if arena.teleopEndSec()+float64(deltaSec) <= arena.MatchTimeSec() {
+		return newArenaError(
+			InvalidDurationError, "Cannot shorten teleop by %d seconds since less time than that remains.", -deltaSec,
+		)
+	}
+	arena.teleopAdjustmentSec += deltaSec
+	// An extension can move the start of the endgame back into the future, in which case it starts again later.
+	if arena.MatchTimeSec() < arena.teleopEndSec()-float64(arena.MatchTiming.WarningRemainingDurationSec) {
+		arena.endgameStarted = false
+	}
+	log.Printf("Adjusted the teleop duration of match %s by %d seconds.", arena.CurrentMatch.DisplayName, deltaSec)
+	arena.MatchTimingNotifier.Notify()
+	arena.MatchTimeNotifier.Notify()
+	return nil
+}
+
+// Clears any adjustment to the teleop duration, letting the displays know about the change in timing if there was one.
+func (arena *Arena) resetTeleopAdjustment() {
+	if arena.teleopAdjustmentSec != 0 {
+		arena.teleopAdjustmentSec = 0
+		arena.MatchTimingNotifier.Notify()
+	}
+}
+
+// Returns the match time at which teleop ends, taking into account any adjustment made while the match is running.
+func (arena *Arena) teleopEndSec() float64 {
+	return arena.MatchTiming.GetDurationToTeleopEnd().Seconds() + float64(arena.teleopAdjustmentSec)
+}
+
+// Returns the wall-clock time at which the match in progress is expected to end, taking into account any adjustment to
+// the teleop duration, or false if no match is in progress. A match that has been started but not yet picked up by the
+// arena loop is expected to end one full match duration from now.
+func (arena *Arena) ExpectedEndTime() (time.Time, bool) {
+	switch arena.MatchState {
+	case StartMatch:
+		return arena.now().Add(arena.MatchTiming.GetDurationToTeleopEnd()), true
+	case WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod:
+		return arena.MatchStartTime.Add(time.Duration(arena.teleopEndSec() * float64(time.Second))), true
+	}
+	return time.Time{}, false
+}
+
+// Returns the timing of the match in progress, taking into account any adjustment to the teleop duration.
+func (arena *Arena) matchTiming() game.Timing {
+	timing := arena.MatchTiming
+	timing.TeleopDurationSec += arena.teleopAdjustmentSec
+	return timing
+}
+
+// Returns true if any robot on the field is currently enabled by the arena. This is the authoritative indicator of
+// whether robots may be moving, and accounts for the match period, the field-wide emergency stop, per-station stops
+// and bypasses, and any robot enabled for diagnostics outside of a match.
+func (arena *Arena) RobotsEnabled() bool {
+	if arena.diagnosticEnabledStation() != "" {
+		return true
+	}
+	if arena.MatchState != AutoPeriod && arena.MatchState != TeleopPeriod {
+		return false
+	}
+	if arena.Plc.GetFieldEstop() || arena.MatchState == AutoPeriod && arena.autoEnablePending {
+		return false
+	}
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.isEnabled(true) {
+			return true
+		}
+	}
+	return false
+}
+
+// Moves a match that has run to completion into the post-match state.
+func (arena *Arena) endMatch() {
+	arena.setMatchState(PostMatch)
+	arena.closeLiveScoring()
+	arena.soundHorn(HornMatchEnd)
+	arena.fieldResetConfirmed = false
+	arena.saveMatchNotes()
+	go func() {
+		// Leave the scores on the screen briefly at the end of the match.
+		time.Sleep(time.Second * matchEndScoreDwellSec)
+		arena.AudienceDisplayMode = "blank"
+		arena.AudienceDisplayModeNotifier.Notify()
+		arena.AllianceStationDisplayMode = "logo"
+		arena.AllianceStationDisplayModeNotifier.Notify()
+	}()
+	go func() {
+		// Configure the network in advance for the next match after a delay.
+		time.Sleep(time.Second * preLoadNextMatchDelaySec)
+		arena.preLoadNextMatch()
+	}()
+}
+
 // Performs a single iteration of checking inputs and timers and setting outputs accordingly to control the
 // flow of a match.
 func (arena *Arena) Update() {
@@ -433,60 +1168,90 @@ func (arena *Arena) Update() {
 	auto := false
 	enabled := false
 	sendDsPacket := false
-	matchTimeSec := arena.MatchTimeSec()
+	endgameStarting := false
+	matchTimeSec := arena.recordMatchTime()
+	period := game.PeriodAtTime(matchTimeSec, arena.matchTiming())
+	arena.checkDiagnosticEnable()
+	arena.checkTotalRobotLoss()
 	switch arena.MatchState {
 	case PreMatch:
-		auto = true
+		auto = !arena.FieldTestMode
 		enabled = false
+		arena.autoBypassUnlinkedStations()
 	case StartMatch:
-		arena.MatchStartTime = time.Now()
+		arena.MatchStartTime = arena.now()
 		arena.LastMatchTimeSec = -1
+		arena.resetTeleopAdjustment()
+		arena.endgameStarted = false
+		arena.scoreMutex.Lock()
+		arena.liveScoringClosed = false
+		arena.resetFinalizedScores()
+		arena.scoreMutex.Unlock()
 		auto = true
+		arena.soundHorn(HornMatchStart)
 		arena.AudienceDisplayMode = "match"
 		arena.AudienceDisplayModeNotifier.Notify()
 		arena.AllianceStationDisplayMode = "match"
 		arena.AllianceStationDisplayModeNotifier.Notify()
-		if game.MatchTiming.WarmupDurationSec > 0 {
-			arena.MatchState = WarmupPeriod
+		arena.autoEnablePending = arena.EventSettings.EnableDelayMs > 0
+		if arena.MatchTiming.WarmupDurationSec > 0 {
+			arena.setMatchState(WarmupPeriod)
 			enabled = false
 			sendDsPacket = false
 		} else {
-			arena.MatchState = AutoPeriod
-			enabled = true
+			arena.setMatchState(AutoPeriod)
+			enabled = arena.checkAutoEnableDelay(0)
 			sendDsPacket = true
 		}
 		arena.Plc.ResetMatch()
 	case WarmupPeriod:
 		auto = true
 		enabled = false
-		if matchTimeSec >= float64(game.MatchTiming.WarmupDurationSec) {
-			arena.MatchState = AutoPeriod
+		if period > game.PeriodWarmup {
+			arena.setMatchState(AutoPeriod)
 			auto = true
-			enabled = true
+			enabled = arena.checkAutoEnableDelay(matchTimeSec)
 			sendDsPacket = true
 		}
 	case AutoPeriod:
 		auto = true
-		enabled = true
-		if matchTimeSec >= game.GetDurationToAutoEnd().Seconds() {
+		if arena.autoEnablePending && arena.checkAutoEnableDelay(matchTimeSec) {
+			// Enable the robots right away rather than waiting for the next periodic packet.
+			sendDsPacket = true
+		}
+		enabled = !arena.autoEnablePending
+		if period > game.PeriodAuto {
+			arena.handleAstopsAtAutoEnd()
 			auto = false
 			sendDsPacket = true
-			if game.MatchTiming.PauseDurationSec > 0 {
-				arena.MatchState = PausePeriod
+			if arena.MatchTiming.PauseDurationSec > 0 {
+				arena.setMatchState(PausePeriod)
+				enabled = false
+			} else if period > game.PeriodTeleop {
+				// There is no teleop period (e.g. for an auto-only demo), so the match is already over.
+				arena.endMatch()
 				enabled = false
 			} else {
-				arena.MatchState = TeleopPeriod
+				// Skip the pause entirely so that robots stay enabled across the transition into teleop.
+				arena.setMatchState(TeleopPeriod)
 				enabled = true
+				arena.auditReenabledAfterAstop()
+				arena.RealtimeScoreNotifier.Notify()
 			}
 		}
 	case PausePeriod:
 		auto = false
 		enabled = false
-		if matchTimeSec >= game.GetDurationToTeleopStart().Seconds() {
-			arena.MatchState = TeleopPeriod
+		if period > game.PeriodTeleop {
+			// There is no teleop period, so end the match rather than enabling the robots for a single loop.
+			arena.endMatch()
+			sendDsPacket = true
+		} else if period > game.PeriodPause {
+			arena.setMatchState(TeleopPeriod)
 			auto = false
 			enabled = true
 			sendDsPacket = true
+			arena.auditReenabledAfterAstop()
 
 			// For 2020, the score calculation might change at this point without input due to Stage 1 activation.
 			arena.RealtimeScoreNotifier.Notify()
@@ -494,28 +1259,21 @@ func (arena *Arena) Update() {
 	case TeleopPeriod:
 		auto = false
 		enabled = true
-		if matchTimeSec >= game.GetDurationToTeleopEnd().Seconds() {
-			arena.MatchState = PostMatch
+		if !arena.endgameStarted && matchTimeSec >= arena.teleopEndSec()-
+			float64(arena.MatchTiming.WarningRemainingDurationSec) {
+			// Robots stay enabled so no packet is forced, but displays get an explicit state change to key off of.
+			arena.endgameStarted = true
+			endgameStarting = true
+		}
+		if period > game.PeriodTeleop {
+			arena.endMatch()
 			auto = false
 			enabled = false
 			sendDsPacket = true
-			go func() {
-				// Leave the scores on the screen briefly at the end of the match.
-				time.Sleep(time.Second * matchEndScoreDwellSec)
-				arena.AudienceDisplayMode = "blank"
-				arena.AudienceDisplayModeNotifier.Notify()
-				arena.AllianceStationDisplayMode = "logo"
-				arena.AllianceStationDisplayModeNotifier.Notify()
-			}()
-			go func() {
-				// Configure the network in advance for the next match after a delay.
-				time.Sleep(time.Second * preLoadNextMatchDelaySec)
-				arena.preLoadNextMatch()
-			}()
 		}
 	case TimeoutActive:
-		if matchTimeSec >= float64(game.MatchTiming.TimeoutDurationSec) {
-			arena.MatchState = PostTimeout
+		if matchTimeSec >= float64(arena.MatchTiming.TimeoutDurationSec) {
+			arena.setMatchState(PostTimeout)
 			go func() {
 				// Leave the timer on the screen briefly at the end of the timeout period.
 				time.Sleep(time.Second * matchEndScoreDwellSec)
@@ -526,20 +1284,29 @@ func (arena *Arena) Update() {
 			}()
 		}
 	case PostTimeout:
-		if matchTimeSec >= float64(game.MatchTiming.TimeoutDurationSec+postTimeoutSec) {
-			arena.MatchState = PreMatch
+		if matchTimeSec >= float64(arena.MatchTiming.TimeoutDurationSec+postTimeoutSec) {
+			arena.setMatchState(PreMatch)
 		}
 	}
 
 	// Send a match tick notification if passing an integer second threshold or if the match state changed.
-	if int(matchTimeSec) != int(arena.LastMatchTimeSec) || arena.MatchState != arena.lastMatchState {
+	if int(matchTimeSec) != int(arena.LastMatchTimeSec) || arena.MatchState != arena.lastMatchState ||
+		endgameStarting {
 		arena.MatchTimeNotifier.Notify()
 	}
 
 	// Send a packet if at a period transition point or if it's been long enough since the last one.
+	// Packets at transition points always go out immediately, while periodic ones may be spread out if configured.
 	if sendDsPacket || time.Since(arena.lastDsPacketTime).Seconds()*1000 >= dsPacketPeriodMs {
-		arena.sendDsPacket(auto, enabled)
-		arena.ArenaStatusNotifier.Notify()
+		if sendDsPacket {
+			arena.sendDsPacket(auto, enabled)
+		} else {
+			arena.sendDsPackets(auto, enabled, arena.dsPacketSpacingMs())
+		}
+		if arena.FieldTestMode {
+			arena.updateFieldTestResults()
+		}
+		arena.sendStatusIfChanged()
 	}
 
 	arena.handleSounds(matchTimeSec)
@@ -548,6 +1315,10 @@ func (arena *Arena) Update() {
 	arena.handlePlcInput()
 	arena.handlePlcOutput()
 
+	if arena.MatchState != arena.lastMatchState {
+		arena.saveMatchProgress()
+	}
+
 	arena.LastMatchTimeSec = matchTimeSec
 	arena.lastMatchState = arena.MatchState
 }
@@ -561,60 +1332,290 @@ func (arena *Arena) Run() {
 	go arena.accessPoint2.Run()
 	go arena.Plc.Run()
 
-	for {
-		arena.Update()
-		if time.Since(arena.lastPeriodicTaskTime).Seconds() >= periodicTaskPeriodSec {
-			arena.lastPeriodicTaskTime = time.Now()
-			go arena.runPeriodicTasks()
-		}
-		time.Sleep(time.Millisecond * arenaLoopPeriodMs)
+	arena.runLoop(nil)
+}
+
+// Adds the given number of points to the named component of the red alliance's realtime score. Safe to call
+// concurrently from multiple scoring inputs. Fails once the match has ended, unless manual scoring is turned on.
+func (arena *Arena) AddRedScore(component string, points int) error {
+	return arena.addScore(arena.RedScore, component, points)
+}
+
+// Adds the given number of points to the named component of the blue alliance's realtime score. Safe to call
+// concurrently from multiple scoring inputs. Fails once the match has ended, unless manual scoring is turned on.
+func (arena *Arena) AddBlueScore(component string, points int) error {
+	return arena.addScore(arena.BlueScore, component, points)
+}
+
+func (arena *Arena) addScore(score *game.Score, component string, points int) error {
+	arena.scoreMutex.Lock()
+	err := arena.checkScoresEditable()
+	if err == nil && arena.liveScoringClosed && !arena.ManualScoring() {
+		err = newArenaError(
+			InvalidStateError, "Live scoring for match %s closed when it ended.", arena.CurrentMatch.DisplayName,
+		)
+	}
+	if err == nil {
+		err = score.AddPoints(component, points)
+	}
+	arena.scoreMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	arena.RealtimeScoreNotifier.Notify()
+	return nil
+}
+
+// Returns copies of the red and blue alliances' realtime scores.
+func (arena *Arena) Scores() (game.Score, game.Score) {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
+	return *arena.RedScore, *arena.BlueScore
+}
+
+// Replaces the red and blue alliances' realtime scores, e.g. with the scorekeeper's edits. Fails once the scores
+// have been finalized.
+func (arena *Arena) SetScores(redScore, blueScore game.Score) error {
+	return arena.updateScores(func() {
+		*arena.RedScore = redScore
+		*arena.BlueScore = blueScore
+	})
+}
+
+// Adds each component of the given scores to the red and blue alliances' realtime scores, e.g. for an external
+// scoring system. Fails once the scores have been finalized.
+func (arena *Arena) AddScores(redScore, blueScore game.Score) error {
+	return arena.updateScores(func() {
+		arena.RedScore.AutoPoints += redScore.AutoPoints
+		arena.RedScore.TeleopPoints += redScore.TeleopPoints
+		arena.RedScore.EndgamePoints += redScore.EndgamePoints
+		arena.BlueScore.AutoPoints += blueScore.AutoPoints
+		arena.BlueScore.TeleopPoints += blueScore.TeleopPoints
+		arena.BlueScore.EndgamePoints += blueScore.EndgamePoints
+	})
+}
+
+// Applies the given change to the realtime scores as long as they can still be edited, checking and changing them
+// under the same lock.
+func (arena *Arena) updateScores(update func()) error {
+	arena.scoreMutex.Lock()
+	err := arena.checkScoresEditable()
+	if err == nil {
+		update()
 	}
+	arena.scoreMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	arena.RealtimeScoreNotifier.Notify()
+	return nil
+}
+
+// Stops live scoring inputs from changing the scores of the match that has just ended, so that the scores as of the
+// end of the match are what get committed unless they are explicitly edited.
+func (arena *Arena) closeLiveScoring() {
+	arena.scoreMutex.Lock()
+	arena.liveScoringClosed = true
+	arena.scoreMutex.Unlock()
 }
 
 // Calculates the red alliance score summary for the given realtime snapshot.
 func (arena *Arena) RedScoreSummary() *game.ScoreSummary {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
 	return arena.RedScore.Summarize()
 }
 
 // Calculates the blue alliance score summary for the given realtime snapshot.
 func (arena *Arena) BlueScoreSummary() *game.ScoreSummary {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
 	return arena.BlueScore.Summarize()
 }
 
+// Returns the winner of the current match based on the realtime scores, using the configured determiner.
+func (arena *Arena) Winner() game.MatchStatus {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
+	return arena.winner()
+}
+
+// Must be called with scoreMutex held.
+func (arena *Arena) winner() game.MatchStatus {
+	return arena.DetermineMatchStatus(arena.RedScore.Summarize(), arena.BlueScore.Summarize())
+}
+
+// Determines the winner given the two alliance score summaries, falling back to the default comparison if no custom
+// determiner has been configured.
+func (arena *Arena) DetermineMatchStatus(redScoreSummary, blueScoreSummary *game.ScoreSummary) game.MatchStatus {
+	if arena.MatchStatusDeterminer == nil {
+		return game.DetermineMatchStatus(redScoreSummary, blueScoreSummary)
+	}
+	return arena.MatchStatusDeterminer(redScoreSummary, blueScoreSummary)
+}
+
 // Loads a team into an alliance station, cleaning up the previous team there if there is one.
 func (arena *Arena) assignTeam(teamId int, station string) error {
+	arena.allianceStationsMutex.Lock()
+	releasedConns, err := arena.assignTeamLocked(teamId, station)
+	arena.allianceStationsMutex.Unlock()
+	releasedConns.close()
+	arena.markStatusChanged()
+	return err
+}
+
+// Loads a team into an alliance station, returning the connections of the previous team there for the caller to
+// close once the assignment is final. Must be called with the alliance stations mutex held.
+func (arena *Arena) assignTeamLocked(teamId int, station string) (releasedStationConns, error) {
+	var releasedConns releasedStationConns
+
 	// Reject invalid station values.
 	if _, ok := arena.AllianceStations[station]; !ok {
-		return fmt.Errorf("Invalid alliance station '%s'.", station)
+		return releasedConns, newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
 	}
 
 	// Do nothing if the station is already assigned to the requested team.
 	dsConn := arena.AllianceStations[station].DsConn
 	if dsConn != nil && dsConn.TeamId == teamId {
-		return nil
+		return releasedConns, nil
+	}
+	if team := arena.AllianceStations[station].Team; team == nil || team.Id != teamId {
+		releasedConns.tcpConn = arena.AllianceStations[station].pendingTcpConn
+		arena.AllianceStations[station].pendingTcpConn = nil
 	}
 	if dsConn != nil {
-		dsConn.close()
+		releasedConns.dsConn = dsConn
 		arena.AllianceStations[station].Team = nil
 		arena.AllianceStations[station].DsConn = nil
+		arena.AllianceStations[station].batteryEma = 0
 	}
+	arena.AllianceStations[station].resetLinkTimes()
 
 	// Leave the station empty if the team number is zero.
 	if teamId == 0 {
 		arena.AllianceStations[station].Team = nil
-		return nil
+		return releasedConns, nil
+	}
+
+	if arena.EventSettings.EnforceEventRoster {
+		if _, ok := arena.eventRoster[teamId]; !ok {
+			return releasedConns, newArenaError(
+				TeamNotRegisteredError, "Team %d is not registered for this event.", teamId,
+			)
+		}
 	}
 
 	// Load the team model. If it doesn't exist, enable anonymous operation.
-	team, err := arena.Database.GetTeamById(teamId)
+	team, err := arena.Datastore.GetTeamById(teamId)
 	if err != nil {
-		return err
+		return releasedConns, err
 	}
 	if team == nil {
 		team = &model.Team{Id: teamId}
 	}
 
 	arena.AllianceStations[station].Team = team
+	if dsConn := arena.takePrewarmedDsConn(teamId, station); dsConn != nil {
+		arena.AllianceStations[station].DsConn = dsConn
+	}
+	return releasedConns, nil
+}
+
+// Connections removed from an alliance station by the assignment of a different team.
+type releasedStationConns struct {
+	dsConn  *DriverStationConnection
+	tcpConn net.Conn
+}
+
+// Closes the released connections.
+func (releasedConns releasedStationConns) close() {
+	if releasedConns.dsConn != nil {
+		releasedConns.dsConn.close()
+	}
+	if releasedConns.tcpConn != nil {
+		releasedConns.tcpConn.Close()
+	}
+}
+
+// Sets the teams registered for the current event, replacing any previous roster. It only restricts team assignment
+// if roster enforcement is enabled in the event settings, and is loaded from the saved roster by LoadSettings.
+func (arena *Arena) SetEventRoster(teamIds []int) {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	arena.eventRoster = make(map[int]struct{}, len(teamIds))
+	for _, teamId := range teamIds {
+		arena.eventRoster[teamId] = struct{}{}
+	}
+}
+
+// Assigns each team in the given match's lineup to its station. If any assignment fails, every station is put back
+// the way it was, including its driver station connection and bypass, so that the arena isn't left with a mix of the
+// old and new lineups. The previous teams' connections are only closed once the whole lineup has been assigned.
+func (arena *Arena) assignMatchTeams(match *model.Match) error {
+	arena.allianceStationsMutex.Lock()
+	previousStations := make(map[string]AllianceStation)
+	for station, allianceStation := range arena.AllianceStations {
+		previousStations[station] = *allianceStation
+	}
+	// Pre-warmed connections taken by the assignment are handed back if it fails, so that a later load can use them.
+	previousPrewarmedDsConns := make(map[int]*DriverStationConnection)
+	for teamId, dsConn := range arena.prewarmedDsConns {
+		previousPrewarmedDsConns[teamId] = dsConn
+	}
+
+	var releasedConns []releasedStationConns
+	teamIds := []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}
+	for i, station := range arena.StationKeys() {
+		stationReleasedConns, err := arena.assignTeamLocked(teamIds[i], station)
+		if err != nil {
+			for previousStation, allianceStation := range previousStations {
+				*arena.AllianceStations[previousStation] = allianceStation
+			}
+			arena.prewarmedDsConns = previousPrewarmedDsConns
+			arena.allianceStationsMutex.Unlock()
+			return err
+		}
+		releasedConns = append(releasedConns, stationReleasedConns)
+	}
+	arena.allianceStationsMutex.Unlock()
+
+	for _, stationReleasedConns := range releasedConns {
+		stationReleasedConns.close()
+	}
+	return nil
+}
+
+// Returns an error if the given match's lineup contains the same team more than once or any invalid team numbers, or
+// nil if it is valid. Teams that don't exist in the database are allowed, since they are run anonymously.
+func (arena *Arena) validateMatchTeams(match *model.Match) error {
+	var invalidTeamIds []string
+	seenTeamIds := make(map[int]struct{})
+	for _, teamId := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+		if teamId == 0 {
+			continue
+		}
+		if _, ok := seenTeamIds[teamId]; ok {
+			return newArenaError(DuplicateTeamError, "Cannot load match containing team %d more than once.", teamId)
+		}
+		seenTeamIds[teamId] = struct{}{}
+		for _, station := range arena.neutralStationKeys {
+			if neutralTeam := arena.AllianceStations[station].Team; neutralTeam != nil && neutralTeam.Id == teamId {
+				return newArenaError(
+					DuplicateTeamError, "Cannot load match containing team %d, which is in neutral station %s.",
+					teamId, station,
+				)
+			}
+		}
+		if teamId < 0 {
+			invalidTeamIds = append(invalidTeamIds, strconv.Itoa(teamId))
+		}
+	}
+	if len(invalidTeamIds) > 0 {
+		return newArenaError(
+			InvalidTeamError, "Cannot load match containing invalid team number(s): %s.",
+			strings.Join(invalidTeamIds, ", "),
+		)
+	}
 	return nil
 }
 
@@ -624,7 +1625,7 @@ func (arena *Arena) getNextMatch(excludeCurrent bool) (*model.Match, error) {
 		return nil, nil
 	}
 
-	matches, err := arena.Database.GetMatchesByType(arena.CurrentMatch.Type)
+	matches, err := arena.Datastore.GetMatchesByType(arena.CurrentMatch.Type)
 	if err != nil {
 		return nil, err
 	}
@@ -638,6 +1639,26 @@ func (arena *Arena) getNextMatch(excludeCurrent bool) (*model.Match, error) {
 	return nil, nil
 }
 
+// Returns the number of matches of the given type whose completion status matches the given value.
+func (arena *Arena) countMatches(matchType string, complete bool) (int, error) {
+	if matchType == "test" {
+		// Test matches are never scheduled.
+		return 0, nil
+	}
+
+	matches, err := arena.Datastore.GetMatchesByType(matchType)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, match := range matches {
+		if match.IsComplete() == complete {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // Configures the field network for the next match in advance of the current match being scored and committed.
 func (arena *Arena) preLoadNextMatch() {
 	if arena.MatchState != PostMatch {
@@ -659,7 +1680,7 @@ func (arena *Arena) preLoadNextMatch() {
 		if teamId == 0 {
 			continue
 		}
-		if teams[i], err = arena.Database.GetTeamById(teamId); err != nil {
+		if teams[i], err = arena.Datastore.GetTeamById(teamId); err != nil {
 			log.Printf("Failed to get model for Team %d while pre-loading next match: %s", teamId, err.Error())
 		}
 	}
@@ -695,62 +1716,312 @@ func (arena *Arena) setupNetwork(teams [6]*model.Team) {
 
 // Returns nil if the match can be started, and an error otherwise.
 func (arena *Arena) checkCanStartMatch() error {
+	if blockers := arena.CheckCanStartMatchAll(); len(blockers) > 0 {
+		return blockers[0]
+	}
+	return nil
+}
+
+// Returns every condition currently preventing the match from starting, in order of precedence, rather than stopping
+// at the first one. Returns an empty list if the match is ready to start.
+func (arena *Arena) CheckCanStartMatchAll() []error {
+	var blockers []error
 	if arena.MatchState != PreMatch {
-		return fmt.Errorf("Cannot start match while there is a match still in progress or with results pending.")
+		blockers = append(blockers, newArenaError(
+			InvalidStateError, "Cannot start match while there is a match still in progress or with results pending.",
+		))
+	}
+	if arena.FieldTestMode {
+		blockers = append(
+			blockers, newArenaError(InvalidStateError, "Cannot start match while field test mode is active."),
+		)
+	}
+	if arena.EventSettings.RequireReplayToRestart && arena.CurrentMatch.IsComplete() && !arena.replayLoaded {
+		blockers = append(blockers, newArenaError(
+			MatchAlreadyCompleteError, "Cannot start match %s because it is already complete; load a replay instead.",
+			arena.CurrentMatch.DisplayName,
+		))
+	}
+	if arena.hasEnabledFault() {
+		blockers = append(blockers, newArenaError(
+			NotReadyError, "Cannot start match while a robot has reported being enabled when it should be disabled.",
+		))
 	}
 
-	err := arena.checkAllianceStationsReady("R1", "R2", "R3", "B1", "B2", "B3")
-	if err != nil {
-		return err
+	for _, station := range arena.StationKeys() {
+		if stationBlockers := arena.checkAllianceStationsReady(station); len(stationBlockers) > 0 {
+			blockers = append(blockers, stationBlockers...)
+		} else if err := arena.checkLinkStable(station); err != nil {
+			blockers = append(blockers, err)
+		}
+	}
+	if err := arena.checkMinRobotsConnected(); err != nil {
+		blockers = append(blockers, err)
+	}
+	if err := arena.checkFieldResetConfirmed(); err != nil {
+		blockers = append(blockers, err)
+	}
+	if err := arena.checkNoDiagnosticEnable(); err != nil {
+		blockers = append(blockers, err)
 	}
 
 	if arena.Plc.IsEnabled() {
 		if !arena.Plc.IsHealthy {
-			return fmt.Errorf("Cannot start match while PLC is not healthy.")
+			blockers = append(blockers, newArenaError(NotReadyError, "Cannot start match while PLC is not healthy."))
 		}
 		if arena.Plc.GetFieldEstop() {
-			return fmt.Errorf("Cannot start match while field emergency stop is active.")
+			blockers = append(
+				blockers, newArenaError(NotReadyError, "Cannot start match while field emergency stop is active."),
+			)
 		}
 		for name, status := range arena.Plc.GetArmorBlockStatuses() {
 			if !status {
-				return fmt.Errorf("Cannot start match while PLC ArmorBlock '%s' is not connected.", name)
+				blockers = append(blockers, newArenaError(
+					NotReadyError, "Cannot start match while PLC ArmorBlock '%s' is not connected.", name,
+				))
 			}
 		}
 	}
 
-	return nil
+	// The external veto is only consulted once every built-in condition has been satisfied.
+	if arena.StartVeto != nil && len(blockers) == 0 {
+		if err := arena.StartVeto(); err != nil {
+			blockers = append(blockers, err)
+		}
+	}
+
+	return blockers
 }
 
-func (arena *Arena) checkAllianceStationsReady(stations ...string) error {
+// Returns the reasons, if any, that each of the given stations is not ready for the match to start.
+func (arena *Arena) checkAllianceStationsReady(stations ...string) []error {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	var blockers []error
 	for _, station := range stations {
 		allianceStation := arena.AllianceStations[station]
 		if allianceStation.Estop {
-			return fmt.Errorf("Cannot start match while an emergency stop is active.")
+			blockers = append(blockers, newArenaError(
+				NotReadyError, "Cannot start match while an emergency stop is active (station %s).", station,
+			))
 		}
 		if !allianceStation.Bypass {
-			if allianceStation.DsConn == nil || !allianceStation.DsConn.RobotLinked {
-				return fmt.Errorf("Cannot start match until all robots are connected or bypassed.")
+			if allianceStation.ConnectionState() == StationAwaitingConnection {
+				blockers = append(blockers, newArenaError(
+					NotReadyError, "Cannot start match until all robots are connected or bypassed (station %s; the "+
+						"driver station for team %d has not connected).", station, allianceStation.Team.Id,
+				))
+			} else if allianceStation.DsConn == nil || !allianceStation.DsConn.RobotLinked {
+				blockers = append(blockers, newArenaError(
+					NotReadyError, "Cannot start match until all robots are connected or bypassed (station %s).",
+					station,
+				))
+			} else if arena.EventSettings.RequireRobotCodeToStart && !allianceStation.DsConn.RobotCodeRunning {
+				blockers = append(blockers, newArenaError(
+					NotReadyError, "Cannot start match until robot code is running (station %s).", station,
+				))
 			}
 		}
 	}
 
+	return blockers
+}
+
+// Returns an error if the robot link at the given station hasn't yet been stable for long enough to start the match.
+// Kept apart from checkAllianceStationsReady so that it gates only the match start and not the PLC stack lights.
+func (arena *Arena) checkLinkStable(station string) error {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation := arena.AllianceStations[station]
+	if !allianceStation.Bypass && !allianceStation.isLinkStable(arena.EventSettings.MinLinkStableSec) {
+		return newArenaError(
+			NotReadyError, "Cannot start match until the robot link at station %s has been stable for %d seconds.",
+			station, arena.EventSettings.MinLinkStableSec,
+		)
+	}
 	return nil
 }
 
-func (arena *Arena) sendDsPacket(auto bool, enabled bool) {
+// Bypasses any station whose robot still hasn't linked once the configured deadline after loading the match has
+// passed. This happens only once per match, so the operator can un-bypass a station afterwards.
+func (arena *Arena) autoBypassUnlinkedStations() {
+	if arena.EventSettings.AutoBypassAfterSec <= 0 || arena.autoBypassApplied ||
+		arena.now().Sub(arena.matchLoadedTime) < time.Duration(arena.EventSettings.AutoBypassAfterSec)*time.Second {
+		return
+	}
+	arena.autoBypassApplied = true
+	for station, allianceStation := range arena.AllianceStations {
+		if !allianceStation.Bypass && (allianceStation.DsConn == nil || !allianceStation.DsConn.RobotLinked) {
+			arena.setStationBypass(allianceStation, true)
+			log.Printf(
+				"Automatically bypassed station %s after its robot failed to connect within %d seconds.", station,
+				arena.EventSettings.AutoBypassAfterSec,
+			)
+			arena.notifyStatusChanged()
+		}
+	}
+}
+
+// Guards against accidentally running a real match with an empty field; test matches may run with no robots.
+func (arena *Arena) checkMinRobotsConnected() error {
+	if arena.CurrentMatch.Type == "" || arena.CurrentMatch.Type == "test" {
+		return nil
+	}
+	connectedRobots := 0
 	for _, allianceStation := range arena.AllianceStations {
+		if !allianceStation.Neutral && !allianceStation.Bypass && allianceStation.DsConn != nil &&
+			allianceStation.DsConn.RobotLinked {
+			connectedRobots++
+		}
+	}
+	if connectedRobots < arena.EventSettings.MinRobotsToStartMatch {
+		return newArenaError(
+			NotReadyError, "Cannot start %s match with fewer than %d connected, non-bypassed robot(s).",
+			arena.CurrentMatch.Type, arena.EventSettings.MinRobotsToStartMatch,
+		)
+	}
+	return nil
+}
+
+func (arena *Arena) sendDsPacket(auto bool, enabled bool) {
+	arena.sendDsPackets(auto, enabled, 0)
+}
+
+// Updates and sends the control packet for every connected driver station. If spacingMs is positive, the packets are
+// written from a separate goroutine with that delay between consecutive stations instead of in a burst, which helps on
+// congested networks at the cost of up to five times the spacing in added latency for the last station.
+func (arena *Arena) sendDsPackets(auto bool, enabled bool, spacingMs int) {
+	var spacedPackets []spacedControlPacket
+	diagnosticEnabledStation := arena.diagnosticEnabledStation()
+	arena.allianceStationsMutex.Lock()
+	for _, station := range arena.controlledStationKeys() {
+		allianceStation := arena.AllianceStations[station]
 		dsConn := allianceStation.DsConn
 		if dsConn != nil {
-			dsConn.Auto = auto
-			dsConn.Enabled = enabled && !allianceStation.Estop && !allianceStation.Astop && !allianceStation.Bypass
+			previousStatus := dsConn.statusSnapshot()
+			// A robot enabled for diagnostics is run in teleop regardless of the match period.
+			diagnosticEnabled := station == diagnosticEnabledStation
+			dsConn.Auto = auto && !diagnosticEnabled
+			dsConn.Enabled = allianceStation.isEnabled(enabled || diagnosticEnabled)
 			dsConn.Estop = allianceStation.Estop
-			err := dsConn.update(arena)
-			if err != nil {
-				log.Printf("Unable to send driver station packet for team %d.", allianceStation.Team.Id)
+			if spacingMs > 0 {
+				// Only the writes are spaced out; the packets are built here so that they see a consistent state.
+				packet := dsConn.nextControlPacket(arena)
+				dsConn.checkLinkTimeout()
+				spacedPackets = append(spacedPackets, spacedControlPacket{dsConn, dsConn.udpConn, packet})
+			} else {
+				packet, err := dsConn.update(arena)
+				arena.traceSentPacket(dsConn, packet, err)
+			}
+			if dsConn.statusSnapshot() != previousStatus {
+				arena.markStatusChanged()
 			}
 		}
+		allianceStation.updateLinkTimes()
+		allianceStation.updateDowntime(enabled, arena.now())
+		allianceStation.countPacket(arena.MatchInProgress())
+		allianceStation.updateBatteryTrend(arena.batteryEmaFactor())
+		allianceStation.recordStatus()
+	}
+	arena.updateTransientFaults()
+	arena.allianceStationsMutex.Unlock()
+	if len(spacedPackets) > 0 {
+		go arena.sendSpacedControlPackets(spacedPackets, spacingMs)
 	}
 	arena.lastDsPacketTime = time.Now()
+	arena.checkEnabledFaults()
+}
+
+// A control packet encoded by the arena loop, waiting to be written out with spacing.
+type spacedControlPacket struct {
+	dsConn  *DriverStationConnection
+	udpConn net.Conn
+	packet  [22]byte
+}
+
+// Writes out the given pre-encoded packets with the given delay between them. Doesn't touch any arena or driver
+// station state, since it runs concurrently with the arena loop.
+func (arena *Arena) sendSpacedControlPackets(spacedPackets []spacedControlPacket, spacingMs int) {
+	for i, spacedPacket := range spacedPackets {
+		if i > 0 {
+			time.Sleep(time.Duration(spacingMs) * time.Millisecond)
+		}
+		err := writeControlPacket(spacedPacket.udpConn, spacedPacket.packet)
+		arena.traceSentPacket(spacedPacket.dsConn, spacedPacket.packet, err)
+	}
+}
+
+// Logs a failure to send the given control packet and records it in the packet trace, taking the commanded mode from
+// the packet itself rather than the connection.
+func (arena *Arena) traceSentPacket(dsConn *DriverStationConnection, packet [22]byte, err error) {
+	if err != nil {
+		log.Printf("Unable to send driver station packet for team %d.", dsConn.TeamId)
+	}
+	arena.tracePacket(
+		"sent", dsConn, "auto=%v,enabled=%v,estop=%v,error=%v", packet[3]&0x02 != 0, packet[3]&0x04 != 0,
+		packet[3]&0x80 != 0, err != nil,
+	)
+}
+
+// Returns the configured spacing between periodic driver station packets, limited to what fits within the period.
+func (arena *Arena) dsPacketSpacingMs() int {
+	if arena.EventSettings.DsPacketSpacingMs > maxDsPacketSpacingMs {
+		return maxDsPacketSpacingMs
+	}
+	return arena.EventSettings.DsPacketSpacingMs
+}
+
+// Returns the number of milliseconds remaining until the next periodic driver station packet is due to be sent, or
+// zero if it is already due.
+func (arena *Arena) TimeUntilNextDsPacketMs() float64 {
+	remainingMs := dsPacketPeriodMs - time.Since(arena.lastDsPacketTime).Seconds()*1000
+	if remainingMs < 0 {
+		return 0
+	}
+	return remainingMs
+}
+
+// Returns true if the station's robot should be enabled given whether the match period calls for enabled robots.
+func (allianceStation *AllianceStation) isEnabled(matchEnabled bool) bool {
+	return matchEnabled && !allianceStation.Estop && !allianceStation.Astop && !allianceStation.Bypass
+}
+
+// Returns true if the robot link has been continuously up for at least the given number of seconds.
+func (allianceStation *AllianceStation) isLinkStable(minLinkStableSec int) bool {
+	if minLinkStableSec <= 0 {
+		return true
+	}
+	return !allianceStation.LinkedSince.IsZero() &&
+		time.Since(allianceStation.LinkedSince) >= time.Duration(minLinkStableSec)*time.Second
+}
+
+// Records the time at which the robot link came up or dropped if it has changed since the last packet.
+func (allianceStation *AllianceStation) updateLinkTimes() {
+	robotLinked := allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked
+	if robotLinked && !allianceStation.wasRobotLinked {
+		allianceStation.LinkedSince = time.Now()
+	} else if !robotLinked && allianceStation.wasRobotLinked {
+		allianceStation.LinkedSince = time.Time{}
+		allianceStation.LastDroppedAt = time.Now()
+	}
+	allianceStation.wasRobotLinked = robotLinked
+}
+
+// Clears the link timestamps, for use when a different team is assigned to the station.
+func (allianceStation *AllianceStation) resetLinkTimes() {
+	allianceStation.LinkedSince = time.Time{}
+	allianceStation.LastDroppedAt = time.Time{}
+	allianceStation.wasRobotLinked = false
+}
+
+// Returns the station that the given team is currently assigned to, or false if the team isn't on the field.
+func (arena *Arena) FindTeamStation(teamId int) (string, bool) {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	station := arena.getAssignedAllianceStation(teamId)
+	return station, station != ""
 }
 
 // Returns the alliance station identifier for the given team, or the empty string if the team is not present
@@ -772,22 +2043,24 @@ func (arena *Arena) handlePlcInput() {
 		arena.AbortMatch()
 	}
 	redEstops, blueEstops := arena.Plc.GetTeamEstops()
+	redEthernets, blueEthernets := arena.Plc.GetEthernetConnected()
+	arena.allianceStationsMutex.Lock()
 	arena.handleEstop("R1", redEstops[0])
 	arena.handleEstop("R2", redEstops[1])
 	arena.handleEstop("R3", redEstops[2])
 	arena.handleEstop("B1", blueEstops[0])
 	arena.handleEstop("B2", blueEstops[1])
 	arena.handleEstop("B3", blueEstops[2])
-	redEthernets, blueEthernets := arena.Plc.GetEthernetConnected()
-	arena.AllianceStations["R1"].Ethernet = redEthernets[0]
-	arena.AllianceStations["R2"].Ethernet = redEthernets[1]
-	arena.AllianceStations["R3"].Ethernet = redEthernets[2]
-	arena.AllianceStations["B1"].Ethernet = blueEthernets[0]
-	arena.AllianceStations["B2"].Ethernet = blueEthernets[1]
-	arena.AllianceStations["B3"].Ethernet = blueEthernets[2]
-
-	if arena.MatchState == PreMatch || arena.MatchState == PostMatch || arena.MatchState == TimeoutActive ||
-		arena.MatchState == PostTimeout {
+	ethernets := append(redEthernets[:], blueEthernets[:]...)
+	for i, station := range stationKeys {
+		if allianceStation := arena.AllianceStations[station]; allianceStation.Ethernet != ethernets[i] {
+			allianceStation.Ethernet = ethernets[i]
+			arena.markStatusChanged()
+		}
+	}
+	arena.allianceStationsMutex.Unlock()
+
+	if !arena.MatchInProgress() {
 		// Don't do anything if we're outside the match, otherwise we may overwrite manual edits.
 		return
 	}
@@ -806,8 +2079,8 @@ func (arena *Arena) handlePlcOutput() {
 	case PostTimeout:
 		// Set the stack light state -- solid alliance color(s) if robots are not connected, solid orange if scores are
 		// not input, or blinking green if ready.
-		redAllianceReady := arena.checkAllianceStationsReady("R1", "R2", "R3") == nil
-		blueAllianceReady := arena.checkAllianceStationsReady("B1", "B2", "B3") == nil
+		redAllianceReady := len(arena.checkAllianceStationsReady("R1", "R2", "R3")) == 0
+		blueAllianceReady := len(arena.checkAllianceStationsReady("B1", "B2", "B3")) == 0
 		greenStackLight := redAllianceReady && blueAllianceReady && arena.Plc.GetCycleState(2, 0, 2)
 		arena.Plc.SetStackLights(!redAllianceReady, !blueAllianceReady, false, greenStackLight)
 		arena.Plc.SetStackBuzzer(redAllianceReady && blueAllianceReady)
@@ -837,30 +2110,80 @@ func (arena *Arena) handlePlcOutput() {
 
 func (arena *Arena) handleEstop(station string, state bool) {
 	allianceStation := arena.AllianceStations[station]
+	wasEstop, wasAstop := allianceStation.Estop, allianceStation.Astop
+	defer func() {
+		if allianceStation.Estop != wasEstop || allianceStation.Astop != wasAstop {
+			arena.markStatusChanged()
+		}
+	}()
 	if state {
+		// Pressing the stop hands control of a restored stop back to the physical button.
+		allianceStation.stopsRestored = false
 		if arena.MatchState == AutoPeriod {
 			allianceStation.Astop = true
 		} else {
 			allianceStation.Estop = true
 		}
-	} else {
+	} else if !allianceStation.stopsRestored {
 		if arena.MatchState != AutoPeriod {
 			allianceStation.Astop = false
 		}
-		if arena.MatchTimeSec() == 0 {
-			// Don't reset the e-stop while a match is in progress.
+		if arena.MatchTimeSec() == 0 && !arena.hasEnabledFault() {
+			// Don't reset the e-stop while a match is in progress, or while the field is stopped because a robot
+			// ignored the disable command.
 			allianceStation.Estop = false
 		}
 	}
 }
 
+// Resolves any stops triggered during the autonomous period, either latching them as e-stops for the remainder of the
+// match or clearing them so that the robots can re-enable for teleop, depending on the arena's policy.
+func (arena *Arena) handleAstopsAtAutoEnd() {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.Astop {
+			arena.markStatusChanged()
+			if arena.AstopLatchesThroughMatch {
+				allianceStation.Estop = true
+			} else {
+				allianceStation.astopCleared = true
+			}
+			allianceStation.Astop = false
+		}
+	}
+}
+
+// Flags and logs each robot that is being re-enabled for teleop after having been stopped during autonomous, since
+// that is a safety-relevant event which officials need to be aware of.
+func (arena *Arena) auditReenabledAfterAstop() {
+	for _, station := range arena.StationKeys() {
+		allianceStation := arena.AllianceStations[station]
+		if !allianceStation.astopCleared {
+			continue
+		}
+		allianceStation.astopCleared = false
+		if allianceStation.isEnabled(true) {
+			allianceStation.ReenabledAfterAstop = true
+			teamId := 0
+			if allianceStation.Team != nil {
+				teamId = allianceStation.Team.Id
+			}
+			log.Printf(
+				"Re-enabled team %d in station %s for teleop after it was stopped during autonomous.", teamId, station,
+			)
+			arena.notifyStatusChanged()
+		}
+	}
+}
+
 func (arena *Arena) handleSounds(matchTimeSec float64) {
 	if arena.MatchState == PreMatch {
 		// Only apply this logic during a match.
 		return
 	}
 
-	for _, sound := range game.MatchSounds {
+	for _, sound := range arena.MatchSounds {
 		if sound.MatchTimeSec < 0 {
 			// Skip sounds with negative timestamps; they are meant to only be triggered explicitly.
 			continue
@@ -870,8 +2193,14 @@ func (arena *Arena) handleSounds(matchTimeSec float64) {
 			// Skip timeout sounds if this is a regular match, and vice versa.
 			continue
 		}
+		soundTimeSec := sound.MatchTimeSec
+		if !sound.Timeout &&
+			soundTimeSec > float64(arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec) {
+			// Sounds during teleop move along with any adjustment to its duration.
+			soundTimeSec += float64(arena.teleopAdjustmentSec)
+		}
 		if _, ok := arena.soundsPlayed[sound]; !ok {
-			if matchTimeSec > sound.MatchTimeSec && matchTimeSec-sound.MatchTimeSec < 1 {
+			if matchTimeSec > soundTimeSec && matchTimeSec-soundTimeSec < 1 {
 				arena.playSound(sound.Name)
 				arena.soundsPlayed[sound] = struct{}{}
 			}
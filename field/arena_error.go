@@ -0,0 +1,49 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Structured error type returned from arena operations, allowing callers to distinguish between kinds of failures.
+
+package field
+
+import (
+	"errors"
+	"fmt"
+)
+
+type ArenaErrorCode int
+
+const (
+	InvalidStateError ArenaErrorCode = iota
+	InvalidStationError
+	TeamNotFoundError
+	DuplicateTeamError
+	SubstitutionNotAllowedError
+	NotReadyError
+	MatchNotFoundError
+	InvalidCardError
+	InvalidDurationError
+	TeamNotRegisteredError
+	MatchAlreadyCompleteError
+	InvalidTeamError
+	InvalidSettingError
+	InvalidOverrideError
+)
+
+type ArenaError struct {
+	Code    ArenaErrorCode
+	Message string
+}
+
+// Creates a new arena error of the given kind with a formatted message.
+func newArenaError(code ArenaErrorCode, format string, args ...interface{}) *ArenaError {
+	return &ArenaError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (err *ArenaError) Error() string {
+	return err.Message
+}
+
+// Returns true if the given error is an arena error with the given code.
+func IsArenaErrorCode(err error, code ArenaErrorCode) bool {
+	var arenaErr *ArenaError
+	return errors.As(err, &arenaErr) && arenaErr.Code == code
+}
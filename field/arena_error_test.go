@@ -0,0 +1,43 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsArenaErrorCode(t *testing.T) {
+	err := newArenaError(InvalidStationError, "Invalid alliance station '%s'.", "R4")
+	assert.Equal(t, "Invalid alliance station 'R4'.", err.Error())
+	assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	assert.False(t, IsArenaErrorCode(err, InvalidStateError))
+	assert.True(t, IsArenaErrorCode(fmt.Errorf("Wrapped: %w", err), InvalidStationError))
+	assert.False(t, IsArenaErrorCode(fmt.Errorf("Invalid alliance station 'R4'."), InvalidStationError))
+	assert.False(t, IsArenaErrorCode(nil, InvalidStationError))
+}
+
+func TestArenaErrorCodes(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.True(t, IsArenaErrorCode(arena.assignTeam(254, "R4"), InvalidStationError))
+	assert.True(t, IsArenaErrorCode(arena.LoadMatch(&model.Match{Red1: 254, Blue1: 254}), DuplicateTeamError))
+	assert.True(t, IsArenaErrorCode(arena.LoadMatch(&model.Match{Red1: 254, Blue1: -1}), InvalidTeamError))
+	assert.True(t, IsArenaErrorCode(arena.AbortMatch(), InvalidStateError))
+	assert.True(t, IsArenaErrorCode(arena.StartMatch(), NotReadyError))
+
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "qualification"}))
+	assert.True(t, IsArenaErrorCode(arena.SubstituteTeam(254, "R1"), SubstitutionNotAllowedError))
+
+	arena.MatchState = AutoPeriod
+	assert.True(t, IsArenaErrorCode(arena.LoadMatch(&model.Match{}), InvalidStateError))
+	assert.True(t, IsArenaErrorCode(arena.StartMatch(), InvalidStateError))
+	assert.True(t, IsArenaErrorCode(arena.ResetMatch(), InvalidStateError))
+	assert.True(t, IsArenaErrorCode(arena.StartTimeout(10), InvalidStateError))
+
+	arena.EventSettings.ElimType = "triple"
+	assert.True(t, IsArenaErrorCode(arena.CreatePlayoffBracket(), InvalidSettingError))
+}
@@ -34,6 +34,16 @@ type ArenaNotifiers struct {
 type MatchTimeMessage struct {
 	MatchState
 	MatchTimeSec int
+	CountdownSec int
+	Endgame      bool
+}
+
+// A single station of the lineup in the match load message. The team is the one resolved when the lineup last
+// changed, so that subscribers don't need to look it up again.
+type LineupStation struct {
+	Team     *model.Team
+	Empty    bool
+	Bypassed bool
 }
 
 type audienceAllianceScoreFields struct {
@@ -73,7 +83,7 @@ func (arena *Arena) generateAllianceStationDisplayModeMessage() interface{} {
 func (arena *Arena) generateArenaStatusMessage() interface{} {
 	// Convert AP team wifi network status array to a map by station for ease of client use.
 	teamWifiStatuses := make(map[string]network.TeamWifiStatus)
-	for i, station := range []string{"R1", "R2", "R3", "B1", "B2", "B3"} {
+	for i, station := range arena.StationKeys() {
 		if arena.EventSettings.Ap2TeamChannel == 0 || i < 3 {
 			teamWifiStatuses[station] = arena.accessPoint.TeamWifiStatuses[i]
 		} else {
@@ -81,18 +91,43 @@ func (arena *Arena) generateArenaStatusMessage() interface{} {
 		}
 	}
 
+	// List every outstanding start condition so that the UI can display them as a checklist.
+	startBlockers := make([]string, 0)
+	for _, err := range arena.CheckCanStartMatchAll() {
+		startBlockers = append(startBlockers, err.Error())
+	}
+
 	return &struct {
-		MatchId          int
-		AllianceStations map[string]*AllianceStation
-		TeamWifiStatuses map[string]network.TeamWifiStatus
+		MatchId                 int
+		IsTest                  bool
+		AllianceStations        map[string]*AllianceStation
+		StationConnectionStates map[string]StationConnectionState
+		TeamInfos               map[string]TeamInfo
+		TeamWifiStatuses        map[string]network.TeamWifiStatus
 		MatchState
+		RedAllianceLabel      string
+		BlueAllianceLabel     string
+		MatchLoaded           bool
 		CanStartMatch         bool
+		StartBlockers         []string
+		LineupChanges         []LineupChange
+		AllianceMismatches    []AllianceMismatch
+		StartTokenRequired    bool
+		RobotsEnabled         bool
+		EnabledFault          bool
+		FieldTestMode         bool
+		FieldResetConfirmed   bool
+		DsNetworkAvailable    bool
 		PlcIsHealthy          bool
 		FieldEstop            bool
 		PlcArmorBlockStatuses map[string]bool
-	}{arena.CurrentMatch.Id, arena.AllianceStations, teamWifiStatuses, arena.MatchState,
-		arena.checkCanStartMatch() == nil, arena.Plc.IsHealthy, arena.Plc.GetFieldEstop(),
-		arena.Plc.GetArmorBlockStatuses()}
+		Notes                 string
+	}{arena.CurrentMatch.Id, arena.CurrentMatch.IsTest(), arena.AllianceStations, arena.stationConnectionStates(),
+		arena.teamInfos(), teamWifiStatuses, arena.MatchState, arena.RedAllianceLabel, arena.BlueAllianceLabel,
+		arena.MatchState == PreMatch, len(startBlockers) == 0, startBlockers, arena.LineupChanges(),
+		arena.AllianceMismatches(), arena.StartTokenRequired(), arena.RobotsEnabled(), arena.hasEnabledFault(),
+		arena.FieldTestMode, arena.fieldResetConfirmed, arena.DsNetworkAvailable(), arena.Plc.IsHealthy,
+		arena.Plc.GetFieldEstop(), arena.Plc.GetArmorBlockStatuses(), arena.Notes}
 }
 
 func (arena *Arena) generateAudienceDisplayModeMessage() interface{} {
@@ -131,7 +166,7 @@ func (arena *Arena) generateMatchLoadMessage() interface{} {
 	for _, allianceStation := range arena.AllianceStations {
 		if allianceStation.Team != nil {
 			rankings[strconv.Itoa(allianceStation.Team.Id)], _ =
-				arena.Database.GetRankingForTeam(allianceStation.Team.Id)
+				arena.Datastore.GetRankingForTeam(allianceStation.Team.Id)
 		}
 	}
 
@@ -140,21 +175,28 @@ func (arena *Arena) generateMatchLoadMessage() interface{} {
 	blueOffFieldTeams := []*model.Team{}
 	if arena.CurrentMatch.Type == "elimination" {
 		matchup, _ = arena.PlayoffBracket.GetMatchup(arena.CurrentMatch.ElimRound, arena.CurrentMatch.ElimGroup)
-		redOffFieldTeamIds, blueOffFieldTeamIds, _ := arena.Database.GetOffFieldTeamIds(arena.CurrentMatch)
+		redOffFieldTeamIds, blueOffFieldTeamIds, _ := arena.Datastore.GetOffFieldTeamIds(arena.CurrentMatch)
 		for _, teamId := range redOffFieldTeamIds {
-			team, _ := arena.Database.GetTeamById(teamId)
+			team, _ := arena.Datastore.GetTeamById(teamId)
 			redOffFieldTeams = append(redOffFieldTeams, team)
 		}
 		for _, teamId := range blueOffFieldTeamIds {
-			team, _ := arena.Database.GetTeamById(teamId)
+			team, _ := arena.Datastore.GetTeamById(teamId)
 			blueOffFieldTeams = append(blueOffFieldTeams, team)
 		}
 	}
 
+	lineup := make(map[string]LineupStation, len(stationKeys))
+	for _, station := range arena.StationKeys() {
+		team := arena.lineupTeams[station]
+		lineup[station] = LineupStation{team, team == nil, arena.AllianceStations[station].Bypass}
+	}
+
 	return &struct {
 		MatchType         string
 		Match             *model.Match
 		Teams             map[string]*model.Team
+		Lineup            map[string]LineupStation
 		Rankings          map[string]*game.Ranking
 		Matchup           *bracket.Matchup
 		RedOffFieldTeams  []*model.Team
@@ -163,6 +205,7 @@ func (arena *Arena) generateMatchLoadMessage() interface{} {
 		arena.CurrentMatch.CapitalizedType(),
 		arena.CurrentMatch,
 		teams,
+		lineup,
 		rankings,
 		matchup,
 		redOffFieldTeams,
@@ -170,12 +213,29 @@ func (arena *Arena) generateMatchLoadMessage() interface{} {
 	}
 }
 
+// Captures the team assigned to each alliance station for the match load message, for use whenever the lineup changes.
+func (arena *Arena) resolveLineup() {
+	arena.lineupTeams = make(map[string]*model.Team, len(stationKeys))
+	for _, station := range arena.StationKeys() {
+		if team := arena.AllianceStations[station].Team; team != nil {
+			lineupTeam := *team
+			arena.lineupTeams[station] = &lineupTeam
+		}
+	}
+}
+
 func (arena *Arena) generateMatchTimeMessage() interface{} {
-	return MatchTimeMessage{arena.MatchState, int(arena.MatchTimeSec())}
+	matchTimeSec := int(arena.MatchTimeSec())
+	return MatchTimeMessage{
+		arena.MatchState, matchTimeSec, arena.countdownSec(matchTimeSec),
+		arena.MatchState == TeleopPeriod && arena.endgameStarted,
+	}
 }
 
+// Reports the timing of the match in progress, including any adjustment to the teleop duration.
 func (arena *Arena) generateMatchTimingMessage() interface{} {
-	return &game.MatchTiming
+	timing := arena.matchTiming()
+	return &timing
 }
 
 func (arena *Arena) generateRealtimeScoreMessage() interface{} {
@@ -184,8 +244,9 @@ func (arena *Arena) generateRealtimeScoreMessage() interface{} {
 		Blue *audienceAllianceScoreFields
 		MatchState
 	}{}
-	fields.Red = getAudienceAllianceScoreFields(arena.RedScore, arena.RedScoreSummary())
-	fields.Blue = getAudienceAllianceScoreFields(arena.BlueScore, arena.BlueScoreSummary())
+	redScore, blueScore := arena.Scores()
+	fields.Red = getAudienceAllianceScoreFields(&redScore, redScore.Summarize())
+	fields.Blue = getAudienceAllianceScoreFields(&blueScore, blueScore.Summarize())
 	fields.MatchState = arena.MatchState
 	return &fields
 }
@@ -206,6 +267,7 @@ func (arena *Arena) generateScorePostedMessage() interface{} {
 
 	return &struct {
 		MatchType        string
+		IsTest           bool
 		Match            *model.Match
 		RedScoreSummary  *game.ScoreSummary
 		BlueScoreSummary *game.ScoreSummary
@@ -214,6 +276,7 @@ func (arena *Arena) generateScorePostedMessage() interface{} {
 		SeriesLeader     string
 	}{
 		arena.SavedMatch.CapitalizedType(),
+		arena.SavedMatch.IsTest(),
 		arena.SavedMatch,
 		arena.SavedMatchResult.RedScoreSummary(),
 		arena.SavedMatchResult.BlueScoreSummary(),
@@ -0,0 +1,112 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Serialization of the arena state, so that the process can be restarted or upgraded between matches without losing
+// the loaded match and the state of the stations.
+
+package field
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"log"
+	"time"
+)
+
+// Snapshot of the arena state as produced by MarshalState. The lineup is carried by the match itself.
+type savedArenaState struct {
+	Match          model.Match
+	MatchState     MatchState
+	MatchStartTime time.Time
+	MatchAborted   bool
+	Stations       map[string]savedStationState
+}
+
+type savedStationState struct {
+	Bypass bool
+	Estop  bool
+	Astop  bool
+}
+
+// Returns a serialized snapshot of the loaded match, its lineup, the bypasses and stops of each station, and the match
+// clock, for later use with RestoreState.
+func (arena *Arena) MarshalState() ([]byte, error) {
+	state := savedArenaState{
+		Match:          *arena.CurrentMatch,
+		MatchState:     arena.MatchState,
+		MatchStartTime: arena.MatchStartTime,
+		MatchAborted:   arena.matchAborted,
+		Stations:       make(map[string]savedStationState),
+	}
+	for _, station := range stationKeys {
+		allianceStation := arena.AllianceStations[station]
+		state.Stations[station] = savedStationState{
+			Bypass: allianceStation.Bypass, Estop: allianceStation.Estop, Astop: allianceStation.Astop,
+		}
+	}
+	return json.Marshal(state)
+}
+
+// Restores a snapshot produced by MarshalState, typically in a freshly started process. The match is re-read from the
+// database, so that any changes made to it since the snapshot was taken aren't undone, and loaded with its lineup so
+// that the driver stations reconnect to the same stations as before; only test matches, which aren't stored, come from
+// the snapshot itself. The bypasses and stops are then reapplied, and restored stops stay latched until the next match
+// is loaded or the station's stop button is pressed and released. The robots are never left enabled: a match that was
+// still being played is restored to the post-match state as aborted, like RecoverInProgressMatch does, and a timeout
+// is not resumed.
+func (arena *Arena) RestoreState(data []byte) error {
+	if arena.MatchState != PreMatch {
+		return newArenaError(
+			InvalidStateError, "Cannot restore the arena state while a match is in progress or has results pending.",
+		)
+	}
+	var state savedArenaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	match := &state.Match
+	if match.Id != 0 {
+		storedMatch, err := arena.Datastore.GetMatchById(match.Id)
+		if err != nil {
+			return err
+		}
+		if storedMatch == nil {
+			return newArenaError(
+				MatchNotFoundError, "Cannot restore match %s, which no longer exists.", state.Match.DisplayName,
+			)
+		}
+		match = storedMatch
+	}
+	if err := arena.LoadMatch(match); err != nil {
+		return err
+	}
+	for station, stationState := range state.Stations {
+		if allianceStation, ok := arena.AllianceStations[station]; ok {
+			arena.setStationBypass(allianceStation, stationState.Bypass)
+			arena.allianceStationsMutex.Lock()
+			allianceStation.Estop = stationState.Estop
+			allianceStation.Astop = stationState.Astop
+			allianceStation.stopsRestored = stationState.Estop || stationState.Astop
+			arena.allianceStationsMutex.Unlock()
+		}
+	}
+
+	switch state.MatchState {
+	case StartMatch, WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod:
+		arena.MatchStartTime = state.MatchStartTime
+		arena.setMatchState(PostMatch)
+		arena.matchAborted = true
+		log.Printf(
+			"Restored match %s, which was interrupted while in %s; treating it as aborted.", match.DisplayName,
+			state.MatchState,
+		)
+	case PostMatch:
+		arena.MatchStartTime = state.MatchStartTime
+		arena.setMatchState(PostMatch)
+		arena.matchAborted = state.MatchAborted
+		log.Printf("Restored match %s in the post-match state.", match.DisplayName)
+	default:
+		log.Printf("Restored match %s in the pre-match state.", match.DisplayName)
+	}
+	arena.notifyStatusChanged()
+	return nil
+}
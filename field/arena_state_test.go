@@ -0,0 +1,86 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMarshalAndRestoreState(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.Database.CreateTeam(&model.Team{Id: 148})
+	match := model.Match{Type: "practice", DisplayName: "3", Red1: 254, Red2: 1114, Blue3: 148}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Estop = true
+	data, err := arena.MarshalState()
+	assert.Nil(t, err)
+
+	// Restoring into an arena that has since moved on should bring back the match, lineup and station state.
+	arena.LoadTestMatch()
+	assert.Nil(t, arena.RestoreState(data))
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	assert.Equal(t, "3", arena.CurrentMatch.DisplayName)
+	assert.Equal(t, 254, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, "The Cheesy Poofs", arena.AllianceStations["R1"].Team.Nickname)
+	assert.Equal(t, 1114, arena.AllianceStations["R2"].Team.Id)
+	assert.Nil(t, arena.AllianceStations["R3"].Team)
+	assert.Equal(t, 148, arena.AllianceStations["B3"].Team.Id)
+	for _, station := range stationKeys {
+		assert.Equal(t, station == "R3" || station == "B1", arena.AllianceStations[station].Bypass, station)
+		assert.Equal(t, station == "B2", arena.AllianceStations[station].Estop, station)
+	}
+
+	// The restored stop should survive the arena loop, even though the stop button isn't pressed, until the button is
+	// pressed and released.
+	arena.Update()
+	arena.Update()
+	assert.True(t, arena.AllianceStations["B2"].Estop)
+	arena.handleEstop("B2", true)
+	arena.handleEstop("B2", false)
+	assert.False(t, arena.AllianceStations["B2"].Estop)
+
+	// The match should be re-read from the database rather than taken from a stale snapshot.
+	match.Blue2 = 1114
+	match.Red2 = 0
+	arena.Database.UpdateMatch(&match)
+	assert.Nil(t, arena.RestoreState(data))
+	assert.Nil(t, arena.AllianceStations["R2"].Team)
+	assert.Equal(t, 1114, arena.AllianceStations["B2"].Team.Id)
+	assert.True(t, arena.AllianceStations["B2"].Estop)
+	arena.Update()
+	assert.True(t, arena.AllianceStations["B2"].Estop)
+
+	// Loading another match clears the restored stops.
+	assert.Nil(t, arena.LoadTestMatch())
+	arena.Update()
+	assert.False(t, arena.AllianceStations["B2"].Estop)
+
+	// A match which has since been deleted can't be restored.
+	arena.Database.DeleteMatch(match.Id)
+	err = arena.RestoreState(data)
+	assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+
+	// A match that was still being played is never resumed.
+	arena.LoadTestMatch()
+	arena.MatchState = TeleopPeriod
+	arena.MatchStartTime = time.Now().Add(-60 * time.Second)
+	data, err = arena.MarshalState()
+	assert.Nil(t, err)
+	assert.NotNil(t, arena.RestoreState(data))
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.RestoreState(data))
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.True(t, arena.matchAborted)
+	assert.False(t, arena.RobotsEnabled())
+
+	assert.NotNil(t, arena.RestoreState([]byte("{")))
+}
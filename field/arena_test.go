@@ -4,10 +4,16 @@
 package field
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/model"
 	"github.com/Team254/cheesy-arena-lite/tournament"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -113,6 +119,7 @@ func TestArenaMatchFlow(t *testing.T) {
 	arena.AllianceStations["B1"].Bypass = true
 	arena.AllianceStations["B2"].Bypass = true
 	arena.AllianceStations["B3"].DsConn.RobotLinked = true
+	arena.AllianceStations["B3"].DsConn.RobotCodeRunning = true
 	err = arena.StartMatch()
 	assert.Nil(t, err)
 	arena.Update()
@@ -123,7 +130,7 @@ func TestArenaMatchFlow(t *testing.T) {
 	assert.Equal(t, WarmupPeriod, arena.MatchState)
 	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Auto)
 	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Enabled)
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
 	arena.Update()
 	assert.Equal(t, AutoPeriod, arena.MatchState)
 	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Auto)
@@ -132,8 +139,8 @@ func TestArenaMatchFlow(t *testing.T) {
 	assert.Equal(t, AutoPeriod, arena.MatchState)
 	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Auto)
 	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Enabled)
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec) * time.Second)
 	arena.Update()
 	assert.Equal(t, PausePeriod, arena.MatchState)
 	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Auto)
@@ -142,8 +149,8 @@ func TestArenaMatchFlow(t *testing.T) {
 	assert.Equal(t, PausePeriod, arena.MatchState)
 	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Auto)
 	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Enabled)
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec+game.MatchTiming.PauseDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec) * time.Second)
 	arena.Update()
 	assert.Equal(t, TeleopPeriod, arena.MatchState)
 	assert.Equal(t, false, arena.AllianceStations["B3"].DsConn.Auto)
@@ -180,8 +187,8 @@ func TestArenaMatchFlow(t *testing.T) {
 	assert.Equal(t, true, arena.AllianceStations["B3"].DsConn.Enabled)
 
 	// Check match end.
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec+game.MatchTiming.PauseDurationSec+game.MatchTiming.TeleopDurationSec) *
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec+arena.MatchTiming.TeleopDurationSec) *
 		time.Second)
 	arena.Update()
 	assert.Equal(t, PostMatch, arena.MatchState)
@@ -316,6 +323,7 @@ func TestMatchStartRobotLinkEnforcement(t *testing.T) {
 	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 106}
 	for _, station := range arena.AllianceStations {
 		station.DsConn.RobotLinked = true
+		station.DsConn.RobotCodeRunning = true
 	}
 	err = arena.StartMatch()
 	assert.Nil(t, err)
@@ -472,6 +480,45 @@ func TestSubstituteTeam(t *testing.T) {
 	assert.Nil(t, arena.SubstituteTeam(107, "R1"))
 }
 
+func TestMatchLoadMessageLineup(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 101, Nickname: "Original"})
+	arena.Database.CreateTeam(&model.Team{Id: 102})
+	arena.Database.CreateTeam(&model.Team{Id: 107})
+	match := model.Match{Type: "practice", Red1: 101, Blue2: 102}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.AllianceStations["R2"].Bypass = true
+
+	lineup := func() map[string]LineupStation {
+		var message struct{ Lineup map[string]LineupStation }
+		data, _ := json.Marshal(arena.generateMatchLoadMessage())
+		assert.Nil(t, json.Unmarshal(data, &message))
+		return message.Lineup
+	}
+	if assert.Equal(t, 6, len(lineup())) {
+		assert.Equal(t, 101, lineup()["R1"].Team.Id)
+		assert.False(t, lineup()["R1"].Empty)
+		assert.True(t, lineup()["R2"].Empty)
+		assert.True(t, lineup()["R2"].Bypassed)
+		assert.Equal(t, 102, lineup()["B2"].Team.Id)
+		assert.False(t, lineup()["B2"].Bypassed)
+	}
+
+	// The lineup is resolved when it changes rather than every time the message is built.
+	team, _ := arena.Database.GetTeamById(101)
+	team.Nickname = "Renamed"
+	arena.Database.UpdateTeam(team)
+	assert.Equal(t, "Original", lineup()["R1"].Team.Nickname)
+
+	assert.Nil(t, arena.SubstituteTeam(107, "R2"))
+	assert.Equal(t, 107, lineup()["R2"].Team.Id)
+	assert.False(t, lineup()["R2"].Empty)
+	assert.Nil(t, arena.SubstituteTeam(0, "B2"))
+	assert.True(t, lineup()["B2"].Empty)
+	assert.Nil(t, lineup()["B2"].Team)
+}
+
 func TestAstop(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -487,7 +534,9 @@ func TestAstop(t *testing.T) {
 	arena.AllianceStations["R2"].DsConn = dummyDs
 
 	arena.AllianceStations["R1"].DsConn.RobotLinked = true
+	arena.AllianceStations["R1"].DsConn.RobotCodeRunning = true
 	arena.AllianceStations["R2"].DsConn.RobotLinked = true
+	arena.AllianceStations["R2"].DsConn.RobotCodeRunning = true
 	arena.AllianceStations["R3"].Bypass = true
 	arena.AllianceStations["B1"].Bypass = true
 	arena.AllianceStations["B2"].Bypass = true
@@ -495,7 +544,7 @@ func TestAstop(t *testing.T) {
 	err = arena.StartMatch()
 	assert.Nil(t, err)
 	arena.Update()
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
 	arena.Update()
 	assert.Equal(t, AutoPeriod, arena.MatchState)
 	assert.Equal(t, true, arena.AllianceStations["R1"].DsConn.Enabled)
@@ -533,12 +582,12 @@ func TestAstop(t *testing.T) {
 	assert.Equal(t, false, arena.AllianceStations["R1"].DsConn.Enabled)
 	assert.Equal(t, false, arena.AllianceStations["R2"].DsConn.Enabled)
 
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec) * time.Second)
 	arena.Update()
 	assert.Equal(t, PausePeriod, arena.MatchState)
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec+game.MatchTiming.PauseDurationSec) * time.Second)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec) * time.Second)
 	arena.handleEstop("R1", false)
 	arena.handleEstop("R2", true)
 	assert.Equal(t, false, arena.AllianceStations["R1"].Astop)
@@ -569,7 +618,7 @@ func TestArenaTimeout(t *testing.T) {
 	// Test regular ending of timeout.
 	timeoutDurationSec := 9
 	assert.Nil(t, arena.StartTimeout(timeoutDurationSec))
-	assert.Equal(t, timeoutDurationSec, game.MatchTiming.TimeoutDurationSec)
+	assert.Equal(t, timeoutDurationSec, arena.MatchTiming.TimeoutDurationSec)
 	assert.Equal(t, TimeoutActive, arena.MatchState)
 	arena.MatchStartTime = time.Now().Add(-time.Duration(timeoutDurationSec) * time.Second)
 	arena.Update()
@@ -581,7 +630,7 @@ func TestArenaTimeout(t *testing.T) {
 	// Test early cancellation of timeout.
 	timeoutDurationSec = 28
 	assert.Nil(t, arena.StartTimeout(timeoutDurationSec))
-	assert.Equal(t, timeoutDurationSec, game.MatchTiming.TimeoutDurationSec)
+	assert.Equal(t, timeoutDurationSec, arena.MatchTiming.TimeoutDurationSec)
 	assert.Equal(t, TimeoutActive, arena.MatchState)
 	assert.Nil(t, arena.AbortMatch())
 	arena.Update()
@@ -601,9 +650,9 @@ func TestArenaTimeout(t *testing.T) {
 	arena.Update()
 	assert.NotNil(t, arena.StartTimeout(1))
 	assert.NotEqual(t, TimeoutActive, arena.MatchState)
-	assert.Equal(t, timeoutDurationSec, game.MatchTiming.TimeoutDurationSec)
-	arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec+game.MatchTiming.PauseDurationSec+game.MatchTiming.TeleopDurationSec) *
+	assert.Equal(t, timeoutDurationSec, arena.MatchTiming.TimeoutDurationSec)
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec+arena.MatchTiming.TeleopDurationSec) *
 		time.Second)
 	for arena.MatchState != PostMatch {
 		arena.Update()
@@ -625,13 +674,13 @@ func TestSaveTeamHasConnected(t *testing.T) {
 	arena.LoadMatch(&match)
 	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 101}
 	arena.AllianceStations["R1"].Bypass = true
-	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{TeamId: 102, RobotLinked: true}
+	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{TeamId: 102, RobotLinked: true, RobotCodeRunning: true}
 	arena.AllianceStations["R3"].DsConn = &DriverStationConnection{TeamId: 103}
 	arena.AllianceStations["R3"].Bypass = true
 	arena.AllianceStations["B1"].DsConn = &DriverStationConnection{TeamId: 104}
 	arena.AllianceStations["B1"].Bypass = true
-	arena.AllianceStations["B2"].DsConn = &DriverStationConnection{TeamId: 105, RobotLinked: true}
-	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 106, RobotLinked: true}
+	arena.AllianceStations["B2"].DsConn = &DriverStationConnection{TeamId: 105, RobotLinked: true, RobotCodeRunning: true}
+	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 106, RobotLinked: true, RobotCodeRunning: true}
 	arena.AllianceStations["B3"].Team.City = "Sand Hosay" // Change some other field to verify that it isn't saved.
 	assert.Nil(t, arena.StartMatch())
 
@@ -647,3 +696,1945 @@ func TestSaveTeamHasConnected(t *testing.T) {
 		assert.Equal(t, "San Jose", teams[5].City)
 	}
 }
+
+func TestArenaWinner(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.Equal(t, game.TieMatch, arena.Winner())
+	arena.RedScore.TeleopPoints = 10
+	assert.Equal(t, game.RedWonMatch, arena.Winner())
+	arena.BlueScore.EndgamePoints = 15
+	assert.Equal(t, game.BlueWonMatch, arena.Winner())
+
+	// Check that a custom determiner is used in place of the default comparison.
+	arena.MatchStatusDeterminer = func(redScoreSummary, blueScoreSummary *game.ScoreSummary) game.MatchStatus {
+		if redScoreSummary.AutoPoints != blueScoreSummary.AutoPoints {
+			return game.TieMatch
+		}
+		return game.RedWonMatch
+	}
+	assert.Equal(t, game.RedWonMatch, arena.Winner())
+	arena.BlueScore.AutoPoints = 5
+	assert.Equal(t, game.TieMatch, arena.Winner())
+
+	// Check that a nil determiner falls back to the default comparison.
+	arena.MatchStatusDeterminer = nil
+	assert.Equal(t, game.BlueWonMatch, arena.Winner())
+}
+
+func TestAllianceStationLinkTimes(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	allianceStation := arena.AllianceStations["R1"]
+	allianceStation.DsConn = &DriverStationConnection{TeamId: 254}
+	arena.sendDsPacket(false, false)
+	assert.True(t, allianceStation.LinkedSince.IsZero())
+	assert.True(t, allianceStation.LastDroppedAt.IsZero())
+
+	// Check that the link-up time is recorded and isn't changed by subsequent packets.
+	allianceStation.DsConn.lastPacketTime = time.Now()
+	allianceStation.DsConn.RobotLinked = true
+	arena.sendDsPacket(false, false)
+	linkedSince := allianceStation.LinkedSince
+	assert.False(t, linkedSince.IsZero())
+	assert.True(t, allianceStation.LastDroppedAt.IsZero())
+	arena.sendDsPacket(false, false)
+	assert.Equal(t, linkedSince, allianceStation.LinkedSince)
+
+	// Check that the drop time is recorded.
+	allianceStation.DsConn.RobotLinked = false
+	arena.sendDsPacket(false, false)
+	assert.True(t, allianceStation.LinkedSince.IsZero())
+	assert.False(t, allianceStation.LastDroppedAt.IsZero())
+
+	// Check that the timestamps are cleared when a different team is assigned.
+	assert.Nil(t, arena.assignTeam(0, "R1"))
+	assert.True(t, allianceStation.LinkedSince.IsZero())
+	assert.True(t, allianceStation.LastDroppedAt.IsZero())
+}
+
+func TestAbortMatchDisablesRobotsImmediately(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Set up a local UDP endpoint to stand in for the driver station.
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		RobotLinked: true, RobotCodeRunning: true, lastPacketTime: time.Now(), udpConn: udpConn}
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+
+	packet := readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0x04), packet[3]&0x04)
+
+	// Check that the disabled packet goes out as part of the abort, without waiting for another loop iteration.
+	assert.Nil(t, arena.AbortMatch())
+	assert.Equal(t, false, arena.AllianceStations["R1"].DsConn.Enabled)
+	packet = readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0), packet[3]&0x04)
+}
+
+// Drains all pending packets from the given UDP listener and returns the most recent one.
+func readLastUdpPacket(t *testing.T, listener *net.UDPConn) [22]byte {
+	var packet [22]byte
+	packetCount := 0
+	for {
+		var data [22]byte
+		listener.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		if _, err := listener.Read(data[:]); err != nil {
+			break
+		}
+		packet = data
+		packetCount++
+	}
+	assert.NotEqual(t, 0, packetCount)
+	return packet
+}
+
+func TestLoadMatchValidatesTeams(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 101})
+	arena.Database.CreateTeam(&model.Team{Id: 102})
+	existingMatch := model.Match{Type: "practice", Red1: 101, Red2: 102}
+	arena.Database.CreateMatch(&existingMatch)
+	assert.Nil(t, arena.LoadMatch(&existingMatch))
+
+	// Check that all invalid teams are reported and that nothing about the arena is changed.
+	match := model.Match{Type: "practice", Red1: 103, Red2: -201, Red3: 104, Blue1: -202, Blue2: 0, Blue3: -203}
+	arena.Database.CreateMatch(&match)
+	err := arena.LoadMatch(&match)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid team number(s): -201, -202, -203.")
+	}
+	assert.Equal(t, existingMatch.Id, arena.CurrentMatch.Id)
+	assert.Equal(t, 101, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, 102, arena.AllianceStations["R2"].Team.Id)
+	assert.Nil(t, arena.AllianceStations["R3"].Team)
+	assert.Nil(t, arena.AllianceStations["B1"].Team)
+
+	// Check that teams missing from the database are loaded anonymously.
+	match = model.Match{Type: "practice", Red1: 103, Blue1: 201}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, 103, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, 201, arena.AllianceStations["B1"].Team.Id)
+}
+
+// Datastore that fails to look up one particular team, for testing recovery from a failed assignment.
+type failingTeamDatastore struct {
+	Datastore
+	failingTeamId int
+}
+
+func (store *failingTeamDatastore) GetTeamById(id int) (*model.Team, error) {
+	if id == store.failingTeamId {
+		return nil, fmt.Errorf("lookup of team %d failed", id)
+	}
+	return store.Datastore.GetTeamById(id)
+}
+
+func TestLoadMatchRollback(t *testing.T) {
+	arena := setupTestArena(t)
+
+	match := model.Match{Type: "practice", Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	r1TcpConn := &failingCloseConn{}
+	r1DsConn := &DriverStationConnection{TeamId: 101, AllianceStation: "R1", tcpConn: r1TcpConn}
+	arena.AllianceStations["R1"].DsConn = r1DsConn
+	b2DsConn := &DriverStationConnection{TeamId: 105, AllianceStation: "B2"}
+	arena.AllianceStations["B2"].DsConn = b2DsConn
+	arena.AllianceStations["R2"].Bypass = true
+
+	// Make the assignment fail at the fourth station by having its team lookup error out.
+	arena.Datastore = &failingTeamDatastore{Datastore: arena.Database, failingTeamId: 107}
+	newMatch := model.Match{Type: "practice", Red1: 111, Red2: 112, Red3: 113, Blue1: 107, Blue2: 105}
+	arena.Database.CreateMatch(&newMatch)
+	assert.NotNil(t, arena.LoadMatch(&newMatch))
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	for station, teamId := range map[string]int{"R1": 101, "R2": 102, "R3": 103, "B1": 104, "B2": 105, "B3": 106} {
+		if assert.NotNil(t, arena.AllianceStations[station].Team) {
+			assert.Equal(t, teamId, arena.AllianceStations[station].Team.Id)
+		}
+	}
+	assert.Equal(t, r1DsConn, arena.AllianceStations["R1"].DsConn) // Pointer equality
+	assert.False(t, r1TcpConn.closed)
+	assert.Equal(t, b2DsConn, arena.AllianceStations["B2"].DsConn) // Pointer equality
+	assert.True(t, arena.AllianceStations["R2"].Bypass)
+
+	// Check that the previous connections are closed once a lineup is assigned successfully.
+	arena.Datastore = arena.Database
+	assert.Nil(t, arena.LoadMatch(&newMatch))
+	assert.Equal(t, 111, arena.AllianceStations["R1"].Team.Id)
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+	assert.True(t, r1TcpConn.closed)
+}
+
+func TestMatchCounts(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateMatch(&model.Match{Type: "practice", DisplayName: "1", Status: game.RedWonMatch})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "1", Status: game.TieMatch})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "2", Status: game.BlueWonMatch})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "3"})
+
+	count, err := arena.RemainingMatchCount("practice")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+	count, err = arena.CompletedMatchCount("practice")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	count, err = arena.RemainingMatchCount("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	count, err = arena.CompletedMatchCount("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	count, err = arena.RemainingMatchCount("elimination")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+	count, err = arena.RemainingMatchCount("test")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+	count, err = arena.CompletedMatchCount("test")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestAstopPolicyAtAutoEnd(t *testing.T) {
+	for _, latchesThroughMatch := range []bool{false, true} {
+		arena := setupTestArena(t)
+		arena.EventSettings.AutoEstopLatchesThroughMatch = latchesThroughMatch
+		assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+		assert.Nil(t, arena.LoadSettings())
+		assert.Equal(t, latchesThroughMatch, arena.AstopLatchesThroughMatch)
+
+		arena.Database.CreateTeam(&model.Team{Id: 254})
+		assert.Nil(t, arena.assignTeam(254, "R1"))
+		arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, RobotLinked: true, RobotCodeRunning: true}
+		arena.AllianceStations["R2"].Bypass = true
+		arena.AllianceStations["R3"].Bypass = true
+		arena.AllianceStations["B1"].Bypass = true
+		arena.AllianceStations["B2"].Bypass = true
+		arena.AllianceStations["B3"].Bypass = true
+		assert.Nil(t, arena.StartMatch())
+		arena.Update()
+		arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+		arena.Update()
+		assert.Equal(t, AutoPeriod, arena.MatchState)
+
+		// Stop the robot during auto and release the button.
+		arena.handleEstop("R1", true)
+		arena.handleEstop("R1", false)
+		assert.Equal(t, true, arena.AllianceStations["R1"].Astop)
+
+		arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+			arena.MatchTiming.AutoDurationSec) * time.Second)
+		arena.Update()
+		assert.Equal(t, PausePeriod, arena.MatchState)
+		assert.Equal(t, false, arena.AllianceStations["R1"].Astop)
+		assert.Equal(t, latchesThroughMatch, arena.AllianceStations["R1"].Estop)
+		assert.False(t, arena.AllianceStations["R1"].ReenabledAfterAstop)
+
+		arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+			arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec) * time.Second)
+		arena.Update()
+		assert.Equal(t, TeleopPeriod, arena.MatchState)
+		assert.Equal(t, !latchesThroughMatch, arena.AllianceStations["R1"].DsConn.Enabled)
+		assert.Equal(t, !latchesThroughMatch, arena.AllianceStations["R1"].ReenabledAfterAstop)
+		assert.False(t, arena.AllianceStations["R2"].ReenabledAfterAstop)
+
+		// The audit flag should be cleared for the next match.
+		arena.AllianceStations["R1"].Bypass = true
+		arena.MatchState = PostMatch
+		assert.Nil(t, arena.ResetMatch())
+		assert.Nil(t, arena.LoadTestMatch())
+		assert.False(t, arena.AllianceStations["R1"].ReenabledAfterAstop)
+	}
+}
+
+func TestMatchCountdownSec(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.Equal(t, arena.MatchTiming.AutoDurationSec, arena.MatchCountdownSec())
+	arena.MatchState = WarmupPeriod
+	arena.MatchStartTime = time.Now().Add(-1500 * time.Millisecond)
+	assert.Equal(t, arena.MatchTiming.AutoDurationSec, arena.MatchCountdownSec())
+	arena.MatchState = AutoPeriod
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+4) * time.Second)
+	assert.Equal(t, arena.MatchTiming.AutoDurationSec-4, arena.MatchCountdownSec())
+	arena.MatchState = PausePeriod
+	assert.Equal(t, 0, arena.MatchCountdownSec())
+	arena.MatchState = TeleopPeriod
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec+
+		arena.MatchTiming.AutoDurationSec+arena.MatchTiming.PauseDurationSec) * time.Second)
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec, arena.MatchCountdownSec())
+	arena.MatchStartTime = arena.MatchStartTime.Add(-100 * time.Second)
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec-100, arena.MatchCountdownSec())
+	arena.MatchState = PostMatch
+	assert.Equal(t, 0, arena.MatchCountdownSec())
+
+	message := arena.generateMatchTimeMessage().(MatchTimeMessage)
+	assert.Equal(t, 0, message.CountdownSec)
+	arena.MatchState = TimeoutActive
+	arena.MatchTiming.TimeoutDurationSec = 300
+	arena.MatchStartTime = time.Now().Add(-10 * time.Second)
+	message = arena.generateMatchTimeMessage().(MatchTimeMessage)
+	assert.Equal(t, 10, message.MatchTimeSec)
+	assert.Equal(t, 290, message.CountdownSec)
+}
+
+func TestCountdownSecClamped(t *testing.T) {
+	arena := setupTestArena(t)
+	teleopStartSec := arena.MatchTiming.WarmupDurationSec + arena.MatchTiming.AutoDurationSec +
+		arena.MatchTiming.PauseDurationSec
+
+	// A match time from before the start of the period should never show more than the whole period.
+	arena.MatchState = AutoPeriod
+	assert.Equal(t, arena.MatchTiming.AutoDurationSec, arena.countdownSec(-50))
+	assert.Equal(t, 0, arena.countdownSec(1000000))
+	arena.MatchState = TeleopPeriod
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec, arena.countdownSec(0))
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec, arena.countdownSec(-1000000))
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec-5, arena.countdownSec(teleopStartSec+5))
+	assert.Equal(t, 0, arena.countdownSec(1000000))
+	arena.teleopAdjustmentSec = 10
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec+10, arena.countdownSec(0))
+	arena.MatchState = TimeoutActive
+	arena.MatchTiming.TimeoutDurationSec = 300
+	assert.Equal(t, 300, arena.countdownSec(-20))
+	assert.Equal(t, 0, arena.countdownSec(301))
+
+	// The clamped value is what goes out to the displays.
+	arena.MatchStartTime = time.Now().Add(time.Hour)
+	message := arena.generateMatchTimeMessage().(MatchTimeMessage)
+	assert.Equal(t, 300, message.CountdownSec)
+}
+
+func TestAddScore(t *testing.T) {
+	arena := setupTestArena(t)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			assert.Nil(t, arena.AddRedScore("teleop", 2))
+		}()
+		go func() {
+			defer waitGroup.Done()
+			assert.Nil(t, arena.AddBlueScore("auto", 1))
+		}()
+	}
+	waitGroup.Wait()
+	assert.Equal(t, 100, arena.RedScore.TeleopPoints)
+	assert.Equal(t, 50, arena.BlueScore.AutoPoints)
+	assert.NotNil(t, arena.AddRedScore("foul", 5))
+
+	// Check that the scores are reset when a new match is loaded.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, game.Score{}, *arena.RedScore)
+	assert.Equal(t, game.Score{}, *arena.BlueScore)
+}
+
+func TestMatchTimeSecWithClockJumpingBackward(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Use a clock without a monotonic reading so that wall-clock jumps are visible to the arena.
+	currentTime := time.Now().Round(0)
+	arena.now = func() time.Time { return currentTime }
+
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Equal(t, WarmupPeriod, arena.MatchState)
+
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec+2) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	matchTimeSec := arena.MatchTimeSec()
+	assert.Equal(t, float64(arena.MatchTiming.WarmupDurationSec+2), matchTimeSec)
+
+	// Simulate an NTP correction that sets the clock back well before the match started.
+	currentTime = currentTime.Add(-60 * time.Second)
+	assert.Equal(t, matchTimeSec, arena.MatchTimeSec())
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.Equal(t, matchTimeSec, arena.MatchTimeSec())
+
+	// Once the clock catches back up, the match clock resumes advancing.
+	currentTime = currentTime.Add(61 * time.Second)
+	assert.Equal(t, matchTimeSec+1, arena.MatchTimeSec())
+
+	// Only the arena loop moves the high-water mark; reading the match time elsewhere leaves it alone.
+	currentTime = currentTime.Add(-60 * time.Second)
+	assert.Equal(t, matchTimeSec, arena.MatchTimeSec())
+	currentTime = currentTime.Add(60 * time.Second)
+	arena.Update()
+	currentTime = currentTime.Add(-60 * time.Second)
+	assert.Equal(t, matchTimeSec+1, arena.MatchTimeSec())
+
+	// Restarting the match clock discards the previous high-water mark.
+	arena.MatchStartTime = currentTime
+	assert.Equal(t, 0.0, arena.MatchTimeSec())
+}
+
+func TestStartMatchConcurrentCalls(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+
+	var waitGroup sync.WaitGroup
+	var startCount, rejectCount int32
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			if err := arena.StartMatch(); err == nil {
+				atomic.AddInt32(&startCount, 1)
+			} else if IsArenaErrorCode(err, InvalidStateError) {
+				atomic.AddInt32(&rejectCount, 1)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	assert.Equal(t, int32(1), startCount)
+	assert.Equal(t, int32(49), rejectCount)
+	assert.Equal(t, StartMatch, arena.MatchState)
+
+	// A start request arriving before the loop has processed START_MATCH should also be rejected.
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Cannot start match while there is a match still in progress")
+	}
+	arena.Update()
+	assert.Equal(t, WarmupPeriod, arena.MatchState)
+}
+
+func TestLoadMatchByNumber(t *testing.T) {
+	arena := setupTestArena(t)
+
+	practiceMatch2 := model.Match{Type: "practice", DisplayName: "2"}
+	qualificationMatch1 := model.Match{Type: "qualification", DisplayName: "1"}
+	qualificationMatch2 := model.Match{Type: "qualification", DisplayName: "2"}
+	arena.Database.CreateMatch(&practiceMatch2)
+	arena.Database.CreateMatch(&qualificationMatch1)
+	arena.Database.CreateMatch(&qualificationMatch2)
+
+	// The same number should resolve to a different match depending on the type.
+	assert.Nil(t, arena.LoadMatchByNumber("qualification", 2))
+	assert.Equal(t, qualificationMatch2.Id, arena.CurrentMatch.Id)
+	assert.Nil(t, arena.LoadMatchByNumber("practice", 2))
+	assert.Equal(t, practiceMatch2.Id, arena.CurrentMatch.Id)
+
+	err := arena.LoadMatchByNumber("practice", 1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "No practice match with number 1 exists.", err.Error())
+		assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+	}
+	assert.Equal(t, practiceMatch2.Id, arena.CurrentMatch.Id)
+}
+
+func TestArenaCheckCanStartMatchMinLinkStable(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{RobotLinked: true, RobotCodeRunning: true}
+	arena.AllianceStations["B3"].LinkedSince = time.Now().Add(-2 * time.Second)
+
+	// The default of zero shouldn't require any link duration.
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	arena.EventSettings.MinLinkStableSec = 5
+	err := arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start match until the robot link at station B3 has been stable for 5 seconds.",
+			err.Error())
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+	}
+
+	// The stack lights should still show the alliance as ready, since only the match start waits for the link.
+	assert.Empty(t, arena.checkAllianceStationsReady("B1", "B2", "B3"))
+
+	arena.AllianceStations["B3"].LinkedSince = time.Now().Add(-5 * time.Second)
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	// A link that has just come up and not yet been timestamped isn't considered stable.
+	arena.AllianceStations["B3"].LinkedSince = time.Time{}
+	assert.NotNil(t, arena.checkCanStartMatch())
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
+func TestAuthorizedSubstituteTeam(t *testing.T) {
+	arena := setupTestArena(t)
+	for teamId := 101; teamId <= 107; teamId++ {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+
+	match := model.Match{Type: "qualification", Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.NotNil(t, arena.SubstituteTeam(107, "R1"))
+
+	// The authorized path should perform the swap and persist the substitution flag.
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(107, "R1"))
+	assert.Equal(t, 107, arena.CurrentMatch.Red1)
+	assert.Equal(t, 107, arena.AllianceStations["R1"].Team.Id)
+	dbMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, 107, dbMatch.Red1)
+	assert.True(t, dbMatch.TeamsSubstituted)
+
+	// The station and team should still be validated.
+	err := arena.AuthorizedSubstituteTeam(107, "R4")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+	err = arena.AuthorizedSubstituteTeam(108, "B1")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, TeamNotFoundError))
+	}
+	assert.Equal(t, 104, arena.CurrentMatch.Blue1)
+
+	// Routine substitutions in practice matches shouldn't be flagged.
+	match = model.Match{Type: "practice", Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Nil(t, arena.SubstituteTeam(107, "R1"))
+	dbMatch, _ = arena.Database.GetMatchById(match.Id)
+	assert.False(t, dbMatch.TeamsSubstituted)
+}
+
+func TestRobotsEnabled(t *testing.T) {
+	arena := setupTestArena(t)
+
+	for _, matchState := range []MatchState{PreMatch, StartMatch, WarmupPeriod, PausePeriod, PostMatch, TimeoutActive,
+		PostTimeout} {
+		arena.MatchState = matchState
+		assert.False(t, arena.RobotsEnabled(), "state %d", matchState)
+	}
+	arena.MatchState = AutoPeriod
+	assert.True(t, arena.RobotsEnabled())
+	arena.MatchState = TeleopPeriod
+	assert.True(t, arena.RobotsEnabled())
+
+	// Robots shouldn't be considered enabled if every station is stopped or bypassed.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Estop = true
+	arena.AllianceStations["B1"].Astop = true
+	arena.AllianceStations["B2"].Bypass = true
+	assert.True(t, arena.RobotsEnabled())
+	arena.AllianceStations["B3"].Bypass = true
+	assert.False(t, arena.RobotsEnabled())
+	arena.AllianceStations["B3"].Bypass = false
+	assert.True(t, arena.RobotsEnabled())
+
+	// Check that the field emergency stop disables everything during teleop.
+	arena.Plc.SetAddress("1.2.3.4")
+	assert.True(t, arena.Plc.GetFieldEstop())
+	assert.False(t, arena.RobotsEnabled())
+	arena.Plc.SetAddress("")
+	assert.True(t, arena.RobotsEnabled())
+}
+
+func TestArenaCheckCanStartMatchMinRobots(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	// An empty test match should still be allowed to start.
+	assert.Equal(t, 1, arena.EventSettings.MinRobotsToStartMatch)
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	err := arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start qualification match with fewer than 1 connected, non-bypassed robot(s).",
+			err.Error())
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+	}
+
+	arena.AllianceStations["R2"].Bypass = false
+	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{RobotLinked: true, RobotCodeRunning: true}
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	arena.EventSettings.MinRobotsToStartMatch = 2
+	assert.NotNil(t, arena.checkCanStartMatch())
+	arena.AllianceStations["B1"].Bypass = false
+	arena.AllianceStations["B1"].DsConn = &DriverStationConnection{RobotLinked: true, RobotCodeRunning: true}
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	// A minimum of zero disables the check.
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
+func TestStartVeto(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+
+	vetoCalls := 0
+	arena.StartVeto = func() error {
+		vetoCalls++
+		return fmt.Errorf("Field is not ready.")
+	}
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Field is not ready.", err.Error())
+	}
+	assert.Equal(t, 1, vetoCalls)
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// The veto shouldn't be consulted if one of the built-in conditions already fails.
+	arena.AllianceStations["B3"].Bypass = false
+	assert.NotNil(t, arena.StartMatch())
+	assert.Equal(t, 1, vetoCalls)
+	arena.AllianceStations["B3"].Bypass = true
+
+	arena.StartVeto = func() error { return nil }
+	assert.Nil(t, arena.StartMatch())
+	assert.Equal(t, StartMatch, arena.MatchState)
+}
+
+func TestAllianceLabels(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Equal(t, "Red", arena.RedAllianceLabel)
+	assert.Equal(t, "Blue", arena.BlueAllianceLabel)
+
+	arena.SetAllianceLabels("Gold", "Silver")
+	assert.Equal(t, "Gold", arena.RedAllianceLabel)
+	assert.Equal(t, "Silver", arena.BlueAllianceLabel)
+	arena.SetAllianceLabels("", "Purple")
+	assert.Equal(t, "Red", arena.RedAllianceLabel)
+	assert.Equal(t, "Purple", arena.BlueAllianceLabel)
+
+	// Check that the labels revert to the defaults when the next match is loaded.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, "Red", arena.RedAllianceLabel)
+	assert.Equal(t, "Blue", arena.BlueAllianceLabel)
+}
+
+func TestTimeUntilNextDsPacketMs(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.lastDsPacketTime = time.Now().Add(-100 * time.Millisecond)
+	remainingMs := arena.TimeUntilNextDsPacketMs()
+	assert.True(t, remainingMs > 100 && remainingMs <= 150, "got %f", remainingMs)
+
+	arena.sendDsPacket(false, false)
+	assert.True(t, arena.TimeUntilNextDsPacketMs() > 200)
+
+	arena.lastDsPacketTime = time.Now().Add(-time.Second)
+	assert.Equal(t, 0.0, arena.TimeUntilNextDsPacketMs())
+}
+
+func TestSetCard(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.assignTeam(254, "R2"))
+	assert.Nil(t, arena.assignTeam(1114, "B3"))
+
+	assert.Nil(t, arena.SetCard("R2", game.YellowCard))
+	assert.Equal(t, game.YellowCard, arena.AllianceStations["R2"].Card)
+	assert.False(t, arena.AllianceStations["R2"].Bypass)
+
+	// A red card should also bypass the station.
+	assert.Nil(t, arena.SetCard("B3", game.RedCard))
+	assert.True(t, arena.AllianceStations["B3"].Bypass)
+	redCards, blueCards := arena.Cards()
+	assert.Equal(t, map[string]int{"254": game.YellowCard}, redCards)
+	assert.Equal(t, map[string]int{"1114": game.RedCard}, blueCards)
+
+	err := arena.SetCard("R4", game.YellowCard)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+	err = arena.SetCard("R1", 3)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidCardError))
+	}
+
+	// Check that cards are cleared when the next match is loaded.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, game.NoCard, arena.AllianceStations["R2"].Card)
+	assert.Equal(t, game.NoCard, arena.AllianceStations["B3"].Card)
+}
+
+func TestAutoBypassUnlinkedStations(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	arena.EventSettings.AutoBypassAfterSec = 30
+	assert.Nil(t, arena.LoadTestMatch())
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{RobotLinked: true, lastPacketTime: currentTime}
+
+	// Nothing should be bypassed before the deadline.
+	currentTime = currentTime.Add(29 * time.Second)
+	arena.autoBypassUnlinkedStations()
+	for _, allianceStation := range arena.AllianceStations {
+		assert.False(t, allianceStation.Bypass)
+	}
+
+	currentTime = currentTime.Add(time.Second)
+	arena.autoBypassUnlinkedStations()
+	assert.False(t, arena.AllianceStations["R1"].Bypass)
+	assert.True(t, arena.AllianceStations["R2"].Bypass)
+	assert.True(t, arena.AllianceStations["R3"].Bypass)
+	assert.True(t, arena.AllianceStations["B1"].Bypass)
+	assert.True(t, arena.AllianceStations["B2"].Bypass)
+	assert.True(t, arena.AllianceStations["B3"].Bypass)
+
+	// An operator override should stick.
+	arena.AllianceStations["B2"].Bypass = false
+	currentTime = currentTime.Add(time.Second)
+	arena.autoBypassUnlinkedStations()
+	assert.False(t, arena.AllianceStations["B2"].Bypass)
+
+	// A zero deadline disables the feature.
+	arena.EventSettings.AutoBypassAfterSec = 0
+	assert.Nil(t, arena.LoadTestMatch())
+	arena.AllianceStations["B3"].Bypass = false
+	currentTime = currentTime.Add(time.Hour)
+	arena.autoBypassUnlinkedStations()
+	assert.False(t, arena.AllianceStations["B3"].Bypass)
+}
+
+func TestArenaStatusStartBlockers(t *testing.T) {
+	arena := setupTestArena(t)
+	getStatus := func() map[string]interface{} {
+		var status map[string]interface{}
+		data, _ := json.Marshal(arena.generateArenaStatusMessage())
+		assert.Nil(t, json.Unmarshal(data, &status))
+		return status
+	}
+
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	arena.AllianceStations["R1"].Estop = true
+	arena.FieldTestMode = true
+	status := getStatus()
+	assert.Equal(t, true, status["MatchLoaded"])
+	assert.Equal(t, false, status["CanStartMatch"])
+	assert.Equal(
+		t,
+		[]interface{}{
+			"Cannot start match while field test mode is active.",
+			"Cannot start match while an emergency stop is active (station R1).",
+		},
+		status["StartBlockers"],
+	)
+
+	arena.FieldTestMode = false
+	arena.AllianceStations["R1"].Estop = false
+	status = getStatus()
+	assert.Equal(t, true, status["MatchLoaded"])
+	assert.Equal(t, true, status["CanStartMatch"])
+	assert.Equal(t, []interface{}{}, status["StartBlockers"])
+
+	assert.Nil(t, arena.StartMatch())
+	status = getStatus()
+	assert.Equal(t, false, status["MatchLoaded"])
+	assert.Equal(t, false, status["CanStartMatch"])
+}
+
+func TestCheckCanStartMatchAll(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.MinLinkStableSec = 5
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Estop = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].DsConn = &DriverStationConnection{RobotLinked: true, RobotCodeRunning: true}
+	arena.AllianceStations["R3"].LinkedSince = time.Now()
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+
+	blockers := arena.CheckCanStartMatchAll()
+	var messages []string
+	for _, err := range blockers {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Cannot start match while an emergency stop is active (station R2).",
+			"Cannot start match until the robot link at station R3 has been stable for 5 seconds.",
+			"Cannot start match until all robots are connected or bypassed (station B2).",
+		},
+		messages,
+	)
+	assert.Equal(t, blockers[0], arena.checkCanStartMatch())
+
+	arena.AllianceStations["R2"].Estop = false
+	arena.AllianceStations["B2"].Bypass = true
+	arena.EventSettings.MinLinkStableSec = 0
+	assert.Empty(t, arena.CheckCanStartMatchAll())
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
+func TestStationKeys(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Equal(t, []string{"R1", "R2", "R3", "B1", "B2", "B3"}, arena.StationKeys())
+	for _, station := range arena.StationKeys() {
+		assert.Contains(t, arena.AllianceStations, station)
+	}
+
+	// Modifying the returned slice shouldn't affect subsequent calls.
+	arena.StationKeys()[0] = "X1"
+	assert.Equal(t, "R1", arena.StationKeys()[0])
+}
+
+func TestArenaCheckCanStartMatchRobotCode(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	arena.AllianceStations["R1"].Bypass = false
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{DsLinked: true, RadioLinked: true}
+
+	// Radio linked but roboRIO unreachable.
+	err := arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "until all robots are connected or bypassed")
+	}
+
+	// RoboRIO reachable but no robot code running.
+	arena.AllianceStations["R1"].DsConn.RobotLinked = true
+	err = arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start match until robot code is running (station R1).", err.Error())
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+	}
+	arena.EventSettings.RequireRobotCodeToStart = false
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	arena.EventSettings.RequireRobotCodeToStart = true
+	arena.AllianceStations["R1"].DsConn.RobotCodeRunning = true
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	// Bypassing the station should skip the check entirely.
+	arena.AllianceStations["R1"].DsConn.RobotCodeRunning = false
+	arena.AllianceStations["R1"].Bypass = true
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
+func TestUnloadMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 254, Blue3: 1114}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	tcpConn := setupFakeTcpConnection(t)
+	defer tcpConn.Close()
+	dsConn, err := newDriverStationConnection(254, "R1", tcpConn, 0, 0)
+	assert.Nil(t, err)
+	arena.AllianceStations["R1"].DsConn = dsConn
+	arena.AllianceStations["B2"].Bypass = true
+
+	assert.Nil(t, arena.UnloadMatch())
+	assert.Equal(t, "test", arena.CurrentMatch.Type)
+	assert.Nil(t, arena.AllianceStations["R1"].Team)
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+	assert.Nil(t, arena.AllianceStations["B3"].Team)
+	assert.True(t, arena.AllianceStations["B2"].Bypass)
+
+	// The connection to the driver station should have been closed.
+	_, err = tcpConn.Write([]byte{0})
+	assert.NotNil(t, err)
+
+	arena.MatchState = AutoPeriod
+	err = arena.UnloadMatch()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+}
+
+func TestAllianceConnectionStatus(t *testing.T) {
+	arena := setupTestArena(t)
+
+	connected, total, allLinked := arena.AllianceConnectionStatus(RedAlliance)
+	assert.Equal(t, 0, connected)
+	assert.Equal(t, 3, total)
+	assert.False(t, allLinked)
+
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{RobotLinked: true}
+	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{RobotLinked: true}
+	connected, total, allLinked = arena.AllianceConnectionStatus(RedAlliance)
+	assert.Equal(t, 2, connected)
+	assert.Equal(t, 3, total)
+	assert.False(t, allLinked)
+
+	// Bypassed stations shouldn't count towards the total.
+	arena.AllianceStations["R3"].Bypass = true
+	connected, total, allLinked = arena.AllianceConnectionStatus(RedAlliance)
+	assert.Equal(t, 2, connected)
+	assert.Equal(t, 2, total)
+	assert.True(t, allLinked)
+
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	connected, total, allLinked = arena.AllianceConnectionStatus(BlueAlliance)
+	assert.Equal(t, 0, connected)
+	assert.Equal(t, 0, total)
+	assert.True(t, allLinked)
+
+	connected, total, allLinked = arena.AllianceConnectionStatus(2)
+	assert.Equal(t, 0, connected)
+	assert.Equal(t, 0, total)
+	assert.False(t, allLinked)
+}
+
+func TestZeroPauseKeepsRobotsEnabled(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func(pauseDurationSec int) { arena.MatchTiming.PauseDurationSec = pauseDurationSec }(
+		arena.MatchTiming.PauseDurationSec,
+	)
+	arena.MatchTiming.PauseDurationSec = 0
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		RobotLinked: true, RobotCodeRunning: true, lastPacketTime: time.Now(), udpConn: udpConn}
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	readLastUdpPacket(t, dsListener)
+
+	// Step across the auto-teleop boundary and check that every packet sent keeps the robot enabled.
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.AutoDurationSec)*time.Second - 20*time.Millisecond)
+	sawPausePeriod := false
+	for i := 0; i < 5; i++ {
+		arena.lastDsPacketTime = time.Time{}
+		arena.Update()
+		sawPausePeriod = sawPausePeriod || arena.MatchState == PausePeriod
+		var packet [22]byte
+		dsListener.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		_, err := dsListener.Read(packet[:])
+		assert.Nil(t, err)
+		assert.Equal(t, byte(0x04), packet[3]&0x04)
+		currentTime = currentTime.Add(10 * time.Millisecond)
+	}
+	assert.False(t, sawPausePeriod)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, false, arena.AllianceStations["R1"].DsConn.Auto)
+}
+
+func TestZeroTeleopEndsMatchAfterAuto(t *testing.T) {
+	for _, pauseDurationSec := range []int{2, 0} {
+		arena := setupTestArena(t)
+		arena.MatchTiming.PauseDurationSec = pauseDurationSec
+		arena.MatchTiming.TeleopDurationSec = 0
+		currentTime := time.Now()
+		arena.now = func() time.Time { return currentTime }
+		var horns []string
+		arena.OnHorn = func(event string) { horns = append(horns, event) }
+		dsConn := &DriverStationConnection{DsLinked: true, RobotLinked: true, lastPacketTime: time.Now()}
+		arena.AllianceStations["R1"].DsConn = dsConn
+		arena.MatchState = StartMatch
+		arena.Update()
+
+		// Step through the whole match and check that the robots are never enabled outside of auto.
+		states := make(map[MatchState]bool)
+		for i := 0; arena.MatchState != PostMatch && i < 10000; i++ {
+			currentTime = currentTime.Add(10 * time.Millisecond)
+			dsConn.lastPacketTime = time.Now()
+			arena.lastDsPacketTime = time.Time{}
+			arena.Update()
+			states[arena.MatchState] = true
+			if arena.MatchState != AutoPeriod {
+				assert.False(t, dsConn.Enabled, "pause %d, state %v", pauseDurationSec, arena.MatchState)
+			}
+		}
+		assert.Equal(t, PostMatch, arena.MatchState)
+		assert.True(t, states[AutoPeriod])
+		assert.Equal(t, pauseDurationSec > 0, states[PausePeriod])
+		assert.False(t, states[TeleopPeriod])
+		assert.False(t, arena.endgameStarted)
+		assert.Equal(t, []string{HornMatchStart, HornMatchEnd}, horns)
+		assert.InDelta(
+			t, arena.MatchTiming.GetDurationToTeleopEnd().Seconds(), currentTime.Sub(arena.MatchStartTime).Seconds(), 0.02,
+		)
+	}
+
+	// Without a pause there should be no teleop sounds left to play, and only one to end the match.
+	timing := game.DefaultMatchTiming
+	timing.PauseDurationSec = 0
+	timing.TeleopDurationSec = 0
+	var endSoundCount int
+	for _, sound := range game.NewMatchSounds(timing) {
+		if sound.Name == "resume" || sound.Name == "warning" {
+			assert.Less(t, sound.MatchTimeSec, 0.0)
+		}
+		if sound.Name == "end" && !sound.Timeout && sound.MatchTimeSec >= 0 {
+			endSoundCount++
+		}
+	}
+	assert.Equal(t, 1, endSoundCount)
+}
+
+func TestEndgameLongerThanTeleop(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.MatchTiming.TeleopDurationSec = 10
+	arena.MatchTiming.WarningRemainingDurationSec = 30
+	arena.MatchSounds = game.NewMatchSounds(arena.MatchTiming)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	arena.MatchState = StartMatch
+	arena.Update()
+
+	// The endgame should start along with teleop rather than being skipped.
+	currentTime = currentTime.Add(arena.MatchTiming.GetDurationToTeleopStart() + 10*time.Millisecond)
+	for i := 0; arena.MatchState != TeleopPeriod && i < 5; i++ {
+		arena.Update()
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	arena.Update()
+	assert.True(t, arena.endgameStarted)
+	for _, sound := range arena.MatchSounds {
+		if sound.Name == "warning" {
+			assert.Equal(t, arena.MatchTiming.GetDurationToTeleopStart().Seconds()-float64(arena.MatchTiming.WarmupDurationSec),
+				sound.MatchTimeSec)
+		}
+	}
+
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.TeleopDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+}
+
+func TestAdjustTeleopDuration(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	err := arena.AdjustTeleopDuration(10)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(arena.MatchTiming.GetDurationToTeleopStart())
+	for i := 0; i < 3; i++ {
+		arena.Update()
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec, arena.MatchCountdownSec())
+
+	assert.Nil(t, arena.AdjustTeleopDuration(10))
+	assert.Equal(t, arena.MatchTiming.TeleopDurationSec+10, arena.MatchCountdownSec())
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.TeleopDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, 10, arena.MatchCountdownSec())
+
+	// Teleop can't be shortened to end before the current time.
+	err = arena.AdjustTeleopDuration(-10)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidDurationError))
+	}
+	assert.Nil(t, arena.AdjustTeleopDuration(-5))
+	currentTime = currentTime.Add(4 * time.Second)
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	currentTime = currentTime.Add(time.Second)
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+
+	// The adjustment shouldn't carry over to the next match.
+	assert.Nil(t, arena.ResetMatch())
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Equal(t, 0, arena.teleopAdjustmentSec)
+}
+
+func TestAdjustTeleopDurationDuringEndgame(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	dsConn := &DriverStationConnection{}
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(arena.MatchTiming.GetDurationToTeleopEnd() -
+		time.Duration(arena.MatchTiming.WarningRemainingDurationSec-5)*time.Second)
+	for i := 0; i < 4; i++ {
+		arena.Update()
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.True(t, arena.endgameStarted)
+
+	// Extending teleop past the endgame warning should end the endgame until it comes around again.
+	assert.Nil(t, arena.AdjustTeleopDuration(20))
+	assert.False(t, arena.endgameStarted)
+	assert.Equal(
+		t, arena.MatchTiming.TeleopDurationSec+20, arena.generateMatchTimingMessage().(*game.Timing).TeleopDurationSec,
+	)
+	assert.Equal(t, arena.MatchTiming.WarningRemainingDurationSec+15, arena.MatchCountdownSec())
+	data := dsConn.encodeControlPacket(arena)
+	assert.Equal(t, arena.MatchTiming.WarningRemainingDurationSec+12, int(data[20])<<8+int(data[21]))
+	arena.Update()
+	assert.False(t, arena.endgameStarted)
+	currentTime = currentTime.Add(15 * time.Second)
+	arena.Update()
+	assert.True(t, arena.endgameStarted)
+
+	// An extension that leaves the start of the endgame in the past keeps it running.
+	currentTime = currentTime.Add(10 * time.Second)
+	assert.Nil(t, arena.AdjustTeleopDuration(5))
+	assert.True(t, arena.endgameStarted)
+	assert.Equal(t, arena.MatchTiming.WarningRemainingDurationSec-5, arena.MatchCountdownSec())
+
+	// The remaining time sent to the driver stations never goes negative, even if the arena loop falls behind.
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarningRemainingDurationSec) * time.Second)
+	data = dsConn.encodeControlPacket(arena)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, byte(0), data[20])
+	assert.Equal(t, byte(0), data[21])
+
+	// The timing reported to the displays goes back to normal once the next match starts.
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.Nil(t, arena.ResetMatch())
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Equal(t, arena.MatchTiming, *arena.generateMatchTimingMessage().(*game.Timing))
+}
+
+func TestAssignTeamConcurrentWithDsPackets(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		// Run the driver station packet loop until the reassignments are finished.
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				arena.sendDsPacket(false, false)
+			}
+		}
+	}()
+
+	// Repeatedly reassign the station, simulating the driver station of each new team connecting in turn.
+	for i := 0; i < 200; i++ {
+		teamId := 254
+		if i%2 == 1 {
+			teamId = 1114
+		}
+		assert.Nil(t, arena.assignTeam(teamId, "R1"))
+		dsConn := &DriverStationConnection{TeamId: teamId, AllianceStation: "R1", lastPacketTime: time.Now()}
+		assert.True(t, arena.attachDsConn(dsConn))
+
+		// A connection for a team that has since been reassigned elsewhere should be turned away.
+		assert.False(t, arena.attachDsConn(&DriverStationConnection{TeamId: 9999, AllianceStation: "R1"}))
+		arena.detachDsConn(&DriverStationConnection{TeamId: 9999, AllianceStation: "R1"})
+		assert.Equal(t, dsConn, arena.AllianceStations["R1"].DsConn)
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, 1114, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, 1114, arena.AllianceStations["R1"].DsConn.TeamId)
+}
+
+func TestLoadMatchSurrogates(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	match := model.Match{Type: "qualification", DisplayName: "1", Red2: 254, Red2IsSurrogate: true, Blue3: 1114}
+	arena.Database.CreateMatch(&match)
+
+	assert.Nil(t, arena.LoadMatch(&match))
+	for _, station := range arena.StationKeys() {
+		assert.Equal(t, station == "R2", arena.AllianceStations[station].Surrogate, station)
+	}
+
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.False(t, arena.AllianceStations["R2"].Surrogate)
+}
+
+func TestTotalMatchDurationSec(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Equal(t, arena.MatchTiming.GetDurationToTeleopEnd().Seconds(), arena.TotalMatchDurationSec())
+
+	arena.MatchTiming.WarmupDurationSec = 3
+	arena.MatchTiming.AutoDurationSec = 20
+	arena.MatchTiming.PauseDurationSec = 0
+	arena.MatchTiming.TeleopDurationSec = 100
+	assert.Equal(t, 123.0, arena.TotalMatchDurationSec())
+
+	// Adjusting the teleop duration of the running match should be reflected.
+	arena.MatchState = TeleopPeriod
+	arena.MatchStartTime = time.Now().Add(-30 * time.Second)
+	assert.Nil(t, arena.AdjustTeleopDuration(15))
+	assert.Equal(t, 138.0, arena.TotalMatchDurationSec())
+}
+
+func TestLoadMatchWithDsNetworkUnavailable(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 254}
+	arena.Database.CreateMatch(&match)
+	assert.True(t, arena.DsNetworkAvailable())
+
+	// A down network is reported through the status rather than blocking the match from being loaded.
+	atomic.StoreInt32(&arena.dsNetworkUnavailable, 1)
+	assert.False(t, arena.DsNetworkAvailable())
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, 254, arena.AllianceStations["R1"].Team.Id)
+}
+
+func TestSendDsPacketsWithSpacing(t *testing.T) {
+	arena := setupTestArena(t)
+
+	var dsListeners []*net.UDPConn
+	for i, station := range []string{"R1", "B3"} {
+		dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.Nil(t, err)
+		defer dsListener.Close()
+		udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+		assert.Nil(t, err)
+		defer udpConn.Close()
+		arena.AllianceStations[station].DsConn = &DriverStationConnection{TeamId: 254 + i, AllianceStation: station,
+			lastPacketTime: time.Now(), udpConn: udpConn}
+		dsListeners = append(dsListeners, dsListener)
+	}
+
+	// The spaced packets should go out in the background without holding up the caller.
+	startTime := time.Now()
+	arena.sendDsPackets(false, false, maxDsPacketSpacingMs)
+	assert.Less(t, time.Since(startTime).Milliseconds(), int64(maxDsPacketSpacingMs))
+
+	// The packets should already have been built by the caller, leaving only the writes to the background.
+	assert.Equal(t, 1, arena.AllianceStations["R1"].DsConn.packetCount)
+	assert.Equal(t, 1, arena.AllianceStations["B3"].DsConn.packetCount)
+	for _, dsListener := range dsListeners {
+		readLastUdpPacket(t, dsListener)
+	}
+
+	// The configured spacing should be capped so that all packets fit within the period.
+	arena.EventSettings.DsPacketSpacingMs = 1000
+	assert.Equal(t, maxDsPacketSpacingMs, arena.dsPacketSpacingMs())
+	arena.EventSettings.DsPacketSpacingMs = 5
+	assert.Equal(t, 5, arena.dsPacketSpacingMs())
+}
+
+func TestAssignTeamEventRoster(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	arena.SetEventRoster([]int{254})
+
+	// Without enforcement, any team can be assigned.
+	assert.Nil(t, arena.assignTeam(1114, "B1"))
+	assert.Nil(t, arena.assignTeam(0, "B1"))
+
+	arena.EventSettings.EnforceEventRoster = true
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	err := arena.assignTeam(1114, "B1")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, TeamNotRegisteredError))
+		assert.Equal(t, "Team 1114 is not registered for this event.", err.Error())
+	}
+	assert.Nil(t, arena.AllianceStations["B1"].Team)
+
+	match := model.Match{Type: "qualification", Red1: 254, Blue1: 1114}
+	arena.Database.CreateMatch(&match)
+	err = arena.LoadMatch(&match)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, TeamNotRegisteredError))
+	}
+	arena.SetEventRoster([]int{254, 1114})
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, 1114, arena.AllianceStations["B1"].Team.Id)
+}
+
+func TestMatchCountdownSecLatchesAtZero(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	startTime := currentTime
+	currentTime = startTime.Add(time.Duration(arena.TotalMatchDurationSec()-10) * time.Second)
+	for i := 0; i < 3; i++ {
+		arena.Update()
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	currentTime = startTime.Add(time.Duration(arena.TotalMatchDurationSec()*1000-1) * time.Millisecond)
+	assert.Equal(t, 1, arena.MatchCountdownSec())
+
+	// At the buzzer and after it, even if the arena loop is late to notice, the countdown should read zero.
+	currentTime = currentTime.Add(time.Millisecond)
+	assert.Equal(t, 0, arena.MatchCountdownSec())
+	currentTime = currentTime.Add(1500 * time.Millisecond)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, 0, arena.MatchCountdownSec())
+	assert.Equal(t, 0, arena.generateMatchTimeMessage().(MatchTimeMessage).CountdownSec)
+
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	for i := 0; i < 3; i++ {
+		currentTime = currentTime.Add(10 * time.Second)
+		arena.Update()
+		assert.Equal(t, 0, arena.MatchCountdownSec())
+	}
+
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, arena.MatchTiming.AutoDurationSec, arena.MatchCountdownSec())
+}
+
+func TestAppendNote(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Date(2026, 4, 18, 9, 30, 15, 0, time.Local)
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+
+	arena.AppendNote("Field reset slow")
+	arena.AppendNote("   ")
+	assert.Equal(t, "[09:30:15] Field reset slow", arena.Notes)
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(30500 * time.Millisecond)
+	arena.AppendNote(" R2 lost comms ")
+	assert.Equal(t, "[09:30:15] Field reset slow\n[T+30.5] R2 lost comms", arena.Notes)
+	dbMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, "", dbMatch.Notes)
+
+	// The notes should be saved when the match ends, and again if more are added afterwards.
+	assert.Nil(t, arena.AbortMatch())
+	dbMatch, _ = arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, arena.Notes, dbMatch.Notes)
+	currentTime = currentTime.Add(2 * time.Minute)
+	arena.AppendNote("Replay requested")
+	dbMatch, _ = arena.Database.GetMatchById(match.Id)
+	assert.Equal(
+		t, "[09:30:15] Field reset slow\n[T+30.5] R2 lost comms\n[09:32:45] Replay requested", dbMatch.Notes,
+	)
+
+	assert.Nil(t, arena.ForceReset())
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, "", arena.Notes)
+}
+
+func TestSubstituteTeamCustomStation(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+
+	// A station that is in the layout but has no corresponding match field should be rejected without assigning it.
+	arena.AllianceStations["X1"] = new(AllianceStation)
+	err := arena.SubstituteTeam(254, "X1")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+	assert.Nil(t, arena.AllianceStations["X1"].Team)
+
+	// Once mapped, substituting into the station should keep the match record in sync.
+	stationMatchTeamFields["X1"] = func(match *model.Match) *int { return &match.Blue3 }
+	defer delete(stationMatchTeamFields, "X1")
+	assert.Nil(t, arena.SubstituteTeam(1114, "X1"))
+	assert.Equal(t, 1114, arena.AllianceStations["X1"].Team.Id)
+	assert.Equal(t, 1114, arena.CurrentMatch.Blue3)
+	assert.Nil(t, arena.SubstituteTeam(254, "R2"))
+	assert.Equal(t, 254, arena.CurrentMatch.Red2)
+}
+
+func TestBypassTeam(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Nil(t, arena.assignTeam(254, "B2"))
+	assert.Nil(t, arena.assignTeam(1114, "R1"))
+	station, ok := arena.FindTeamStation(254)
+	assert.True(t, ok)
+	assert.Equal(t, "B2", station)
+
+	assert.Nil(t, arena.BypassTeam(254, true))
+	for _, station := range arena.StationKeys() {
+		assert.Equal(t, station == "B2", arena.AllianceStations[station].Bypass, station)
+	}
+
+	// A team that isn't on the field can't be bypassed.
+	for _, teamId := range []int{148, 0} {
+		_, ok = arena.FindTeamStation(teamId)
+		assert.False(t, ok)
+		err := arena.BypassTeam(teamId, true)
+		if assert.NotNil(t, err) {
+			assert.True(t, IsArenaErrorCode(err, TeamNotFoundError))
+			assert.Equal(t, fmt.Sprintf("Team %d is not on the field.", teamId), err.Error())
+		}
+	}
+
+	// The same restriction on clearing a bypass mid-match applies.
+	arena.MatchState = TeleopPeriod
+	err := arena.BypassTeam(254, false)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+	assert.True(t, arena.AllianceStations["B2"].Bypass)
+	assert.Nil(t, arena.BypassTeam(1114, true))
+	assert.True(t, arena.AllianceStations["R1"].Bypass)
+	arena.MatchState = PostMatch
+	assert.Nil(t, arena.BypassTeam(254, false))
+	assert.False(t, arena.AllianceStations["B2"].Bypass)
+}
+
+func TestBypassAlliance(t *testing.T) {
+	arena := setupTestArena(t)
+	for i, station := range arena.StationKeys() {
+		arena.AllianceStations[station].DsConn = &DriverStationConnection{TeamId: 254 + i, AllianceStation: station,
+			RobotLinked: i >= 3, RobotCodeRunning: i >= 3}
+	}
+	assert.Equal(t, 3, len(arena.CheckCanStartMatchAll()))
+
+	err := arena.BypassAlliance(2, true)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+	assert.Nil(t, arena.BypassAlliance(RedAlliance, true))
+	for _, station := range []string{"R1", "R2", "R3"} {
+		assert.True(t, arena.AllianceStations[station].Bypass)
+	}
+	assert.Empty(t, arena.CheckCanStartMatchAll())
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	for i, station := range arena.StationKeys() {
+		assert.Equal(t, i >= 3, arena.AllianceStations[station].DsConn.Enabled)
+	}
+
+	// Bypassing mid-match disables the robots, but clearing the bypass must wait until the match is over.
+	err = arena.BypassAlliance(RedAlliance, false)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+	assert.True(t, arena.AllianceStations["R1"].Bypass)
+	assert.Nil(t, arena.BypassAlliance(BlueAlliance, true))
+	arena.lastDsPacketTime = time.Time{}
+	arena.Update()
+	for _, station := range arena.StationKeys() {
+		assert.False(t, arena.AllianceStations[station].DsConn.Enabled)
+	}
+
+	assert.Nil(t, arena.AbortMatch())
+	assert.Nil(t, arena.BypassAlliance(BlueAlliance, false))
+	assert.False(t, arena.AllianceStations["B2"].Bypass)
+	assert.True(t, arena.AllianceStations["R2"].Bypass)
+}
+
+func TestEndgameNotificationWithoutDsPacket(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		udpConn: udpConn}
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	endgameStartSec := arena.TotalMatchDurationSec() - float64(arena.MatchTiming.WarningRemainingDurationSec)
+	currentTime = currentTime.Add(time.Duration(endgameStartSec*1000-10) * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		arena.Update()
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.False(t, arena.generateMatchTimeMessage().(MatchTimeMessage).Endgame)
+	readLastUdpPacket(t, dsListener)
+
+	// Crossing into the endgame should flag the match time message but not force a driver station packet.
+	currentTime = currentTime.Add(10 * time.Millisecond)
+	arena.lastDsPacketTime = time.Now()
+	arena.Update()
+	assert.True(t, arena.endgameStarted)
+	assert.True(t, arena.generateMatchTimeMessage().(MatchTimeMessage).Endgame)
+	var packet [22]byte
+	dsListener.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, err = dsListener.Read(packet[:])
+	assert.NotNil(t, err)
+
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarningRemainingDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.False(t, arena.generateMatchTimeMessage().(MatchTimeMessage).Endgame)
+}
+
+func TestAutoEnableDelay(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.EnableDelayMs = 250
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		udpConn: udpConn}
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = allianceStation.DsConn == nil
+	}
+	arena.AllianceStations["R1"].DsConn.RobotLinked = true
+	arena.AllianceStations["R1"].DsConn.RobotCodeRunning = true
+	arena.AllianceStations["R1"].DsConn.lastPacketTime = time.Now()
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	startTime := arena.MatchStartTime
+	autoStartTime := startTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	currentTime = autoStartTime
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.False(t, arena.AllianceStations["R1"].DsConn.Enabled)
+	assert.False(t, arena.RobotsEnabled())
+	currentTime = autoStartTime.Add(249 * time.Millisecond)
+	arena.lastDsPacketTime = time.Time{}
+	arena.Update()
+	assert.False(t, arena.AllianceStations["R1"].DsConn.Enabled)
+	readLastUdpPacket(t, dsListener)
+
+	// The robots should be enabled with an immediate packet as soon as the delay elapses.
+	currentTime = autoStartTime.Add(250 * time.Millisecond)
+	arena.lastDsPacketTime = time.Now()
+	arena.Update()
+	assert.True(t, arena.AllianceStations["R1"].DsConn.Enabled)
+	assert.True(t, arena.RobotsEnabled())
+	packet := readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0x04), packet[3]&0x04)
+	assert.Equal(t, startTime, arena.MatchStartTime)
+
+	// Without a delay, the robots should be enabled right at the start of autonomous.
+	assert.Nil(t, arena.AbortMatch())
+	assert.Nil(t, arena.ResetMatch())
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["R1"].DsConn.lastPacketTime = time.Now()
+	arena.EventSettings.EnableDelayMs = 0
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.True(t, arena.AllianceStations["R1"].DsConn.Enabled)
+}
+
+func TestMatchInProgress(t *testing.T) {
+	arena := setupTestArena(t)
+
+	for _, testCase := range []struct {
+		matchState  MatchState
+		inProgress  bool
+		canAbort    bool
+		canReset    bool
+		canUnbypass bool
+	}{
+		{PreMatch, false, false, true, true},
+		{StartMatch, true, true, false, false},
+		{WarmupPeriod, true, true, false, false},
+		{AutoPeriod, true, true, false, false},
+		{PausePeriod, true, true, false, false},
+		{TeleopPeriod, true, true, false, false},
+		{PostMatch, false, false, true, true},
+		{TimeoutActive, false, true, false, true},
+		{PostTimeout, false, false, false, true},
+	} {
+		arena.MatchState = testCase.matchState
+		assert.Equal(t, testCase.inProgress, arena.MatchInProgress(), "state %d", testCase.matchState)
+		arena.AllianceStations["R1"].Bypass = true
+		assert.Equal(t, testCase.canUnbypass, arena.SetBypass("R1", false) == nil, "state %d", testCase.matchState)
+
+		arena.MatchState = testCase.matchState
+		arena.matchAborted = false
+		assert.Equal(t, testCase.canAbort, arena.AbortMatch() == nil, "state %d", testCase.matchState)
+
+		arena.MatchState = testCase.matchState
+		assert.Equal(t, testCase.canReset, arena.ResetMatch() == nil, "state %d", testCase.matchState)
+	}
+}
+
+func TestPeriodElapsedSec(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.MatchTiming.WarmupDurationSec = 2
+	arena.MatchTiming.AutoDurationSec = 15
+	arena.MatchTiming.PauseDurationSec = 3
+	arena.MatchTiming.TeleopDurationSec = 135
+	startTime := time.Now()
+	currentTime := startTime
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+
+	assert.Equal(t, 0.0, arena.PeriodElapsedSec())
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	for _, testCase := range []struct {
+		matchTimeSec float64
+		matchState   MatchState
+		elapsedSec   float64
+	}{
+		{0, WarmupPeriod, 0},
+		{1.5, WarmupPeriod, 1.5},
+		{2, AutoPeriod, 0},
+		{2.25, AutoPeriod, 0.25},
+		{16.5, AutoPeriod, 14.5},
+		{17, PausePeriod, 0},
+		{19, PausePeriod, 2},
+		{20, TeleopPeriod, 0},
+		{20.5, TeleopPeriod, 0.5},
+		{154.75, TeleopPeriod, 134.75},
+		{155, PostMatch, 0},
+		{200, PostMatch, 0},
+	} {
+		currentTime = startTime.Add(time.Duration(testCase.matchTimeSec * float64(time.Second)))
+		arena.Update()
+		assert.Equal(t, testCase.matchState, arena.MatchState, "time %v", testCase.matchTimeSec)
+		assert.InDelta(t, testCase.elapsedSec, arena.PeriodElapsedSec(), 0.001, "time %v", testCase.matchTimeSec)
+	}
+
+	assert.Nil(t, arena.ResetMatch())
+	assert.Equal(t, 0.0, arena.PeriodElapsedSec())
+	assert.Nil(t, arena.StartTimeout(60))
+	currentTime = currentTime.Add(10 * time.Second)
+	assert.InDelta(t, 10.0, arena.PeriodElapsedSec(), 0.001)
+}
+
+func TestStartMatchWithToken(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.False(t, arena.StartTokenRequired())
+
+	assert.Nil(t, arena.SetStartToken(" final-1 "))
+	assert.True(t, arena.StartTokenRequired())
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		assert.Equal(t, "Cannot start match without its start confirmation token.", err.Error())
+	}
+	err = arena.StartMatchWithToken("final-2")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start match with an incorrect start confirmation token.", err.Error())
+	}
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// Loading a match clears the token.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.False(t, arena.StartTokenRequired())
+	assert.Nil(t, arena.SetStartToken("final-1"))
+
+	// A blocked start doesn't use up the token.
+	arena.AllianceStations["R1"].Bypass = false
+	err = arena.StartMatchWithToken("final-1")
+	assert.True(t, IsArenaErrorCode(err, NotReadyError))
+	assert.True(t, arena.StartTokenRequired())
+	arena.AllianceStations["R1"].Bypass = true
+
+	assert.Nil(t, arena.StartMatchWithToken("final-1"))
+	assert.Equal(t, StartMatch, arena.MatchState)
+	assert.False(t, arena.StartTokenRequired())
+	err = arena.SetStartToken("final-1")
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+
+	// Clearing the token restores the plain start.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.SetStartToken("final-1"))
+	assert.Nil(t, arena.SetStartToken(""))
+	assert.Nil(t, arena.StartMatch())
+}
+
+func TestExpectedEndTime(t *testing.T) {
+	arena := setupTestArena(t)
+	startTime := time.Date(2026, 4, 18, 14, 45, 0, 0, time.Local)
+	currentTime := startTime
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	matchDuration := arena.MatchTiming.GetDurationToTeleopEnd()
+
+	_, ok := arena.ExpectedEndTime()
+	assert.False(t, ok)
+
+	assert.Nil(t, arena.StartMatch())
+	endTime, ok := arena.ExpectedEndTime()
+	assert.True(t, ok)
+	assert.Equal(t, startTime.Add(matchDuration), endTime)
+
+	// The expected end time stays fixed as the match progresses through its periods.
+	arena.Update()
+	for _, elapsed := range []time.Duration{time.Second, 10 * time.Second, 30 * time.Second, 100 * time.Second} {
+		currentTime = startTime.Add(elapsed)
+		arena.Update()
+		endTime, ok = arena.ExpectedEndTime()
+		assert.True(t, ok, "%v", elapsed)
+		assert.Equal(t, startTime.Add(matchDuration), endTime, "%v", elapsed)
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+
+	assert.Nil(t, arena.AdjustTeleopDuration(20))
+	endTime, _ = arena.ExpectedEndTime()
+	assert.Equal(t, startTime.Add(matchDuration+20*time.Second), endTime)
+
+	currentTime = startTime.Add(matchDuration + 20*time.Second)
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+	_, ok = arena.ExpectedEndTime()
+	assert.False(t, ok)
+
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.StartTimeout(60))
+	_, ok = arena.ExpectedEndTime()
+	assert.False(t, ok)
+}
+
+func TestResetMatchRequiresCommittedResults(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	startMatch := func() {
+		// Resetting the match clears the bypasses, so reapply them before every start.
+		for _, allianceStation := range arena.AllianceStations {
+			allianceStation.Bypass = true
+		}
+		assert.Nil(t, arena.StartMatch())
+		arena.Update()
+	}
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+
+	// A match that has never been played can be reset freely.
+	assert.Nil(t, arena.ResetMatch())
+
+	startMatch()
+	assert.Nil(t, arena.AbortMatch())
+	assert.Equal(t, PostMatch, arena.MatchState)
+	err := arena.ResetMatch()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+		assert.Equal(t, "Cannot reset match 1 until its results have been committed.", err.Error())
+	}
+	assert.Equal(t, PostMatch, arena.MatchState)
+
+	// Results committed from an earlier play of the match don't count.
+	arena.CurrentMatch.ScoreCommittedAt = arena.CurrentMatch.StartedAt.Add(-time.Minute)
+	assert.NotNil(t, arena.ResetMatch())
+	arena.CurrentMatch.ScoreCommittedAt = time.Now()
+	assert.Nil(t, arena.ResetMatch())
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// Discarding the results requires forcing the reset.
+	startMatch()
+	assert.Nil(t, arena.AbortMatch())
+	assert.NotNil(t, arena.ResetMatch())
+	assert.Nil(t, arena.ForceReset())
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// Test matches have no results to lose.
+	assert.Nil(t, arena.LoadTestMatch())
+	startMatch()
+	assert.Nil(t, arena.AbortMatch())
+	assert.Nil(t, arena.ResetMatch())
+
+	// Forcing the reset still doesn't allow interrupting a match.
+	startMatch()
+	err = arena.ForceReset()
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+}
+
+func TestStartCompletedMatchRequiresReplay(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	bypassAll := func() {
+		for _, allianceStation := range arena.AllianceStations {
+			allianceStation.Bypass = true
+		}
+	}
+	match := model.Match{Type: "qualification", DisplayName: "1", Status: game.RedWonMatch}
+	arena.Database.CreateMatch(&match)
+
+	// Completed matches can be started again by default.
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+	assert.Empty(t, arena.CheckCanStartMatchAll())
+
+	arena.EventSettings.RequireReplayToRestart = true
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, MatchAlreadyCompleteError))
+		assert.Equal(t, "Cannot start match 1 because it is already complete; load a replay instead.", err.Error())
+	}
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	assert.Nil(t, arena.LoadReplay(&match))
+	bypassAll()
+	assert.Nil(t, arena.StartMatch())
+
+	// Reloading the match normally requires the replay to be requested again.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+	assert.True(t, IsArenaErrorCode(arena.StartMatch(), MatchAlreadyCompleteError))
+
+	// Unplayed matches aren't affected.
+	match.Status = game.MatchNotPlayed
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+	assert.Nil(t, arena.StartMatch())
+}
+
+func TestArenaStatusIsTest(t *testing.T) {
+	arena := setupTestArena(t)
+	isTest := func() bool {
+		var status struct{ IsTest bool }
+		data, _ := json.Marshal(arena.generateArenaStatusMessage())
+		assert.Nil(t, json.Unmarshal(data, &status))
+		return status.IsTest
+	}
+	assert.True(t, isTest())
+
+	match := model.Match{Type: "practice", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.False(t, isTest())
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.True(t, isTest())
+}
+
+func TestMatchTimingIsPerArena(t *testing.T) {
+	arena := setupTestArena(t)
+	otherArena, err := NewArena(filepath.Join(t.TempDir(), "other.db"))
+	assert.Nil(t, err)
+	defer otherArena.Database.Close()
+
+	arena.EventSettings.TeleopDurationSec = 100
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.Nil(t, otherArena.StartTimeout(30))
+	assert.Equal(t, 100, arena.MatchTiming.TeleopDurationSec)
+	assert.Equal(t, game.DefaultMatchTiming.TimeoutDurationSec, arena.MatchTiming.TimeoutDurationSec)
+	assert.Equal(t, game.DefaultMatchTiming.TeleopDurationSec, otherArena.MatchTiming.TeleopDurationSec)
+	assert.Equal(t, 30, otherArena.MatchTiming.TimeoutDurationSec)
+	timeoutEndSec := func(sounds []*game.MatchSound) float64 {
+		for _, sound := range sounds {
+			if sound.Name == "end" && sound.Timeout {
+				return sound.MatchTimeSec
+			}
+		}
+		return -1
+	}
+	assert.Equal(t, 0.0, timeoutEndSec(arena.MatchSounds))
+	assert.Equal(t, 30.0, timeoutEndSec(otherArena.MatchSounds))
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Smoothed battery voltage of each robot, so that displays can show whether it is dropping quickly or holding steady.
+
+package field
+
+// Smoothing factor used if the configured one is out of range; each new sample contributes this fraction of the
+// average.
+const defaultBatteryEmaFactor = 0.2
+
+// Folds the latest reported battery voltage into the station's exponential moving average, restarting the average
+// whenever the robot isn't linked.
+func (allianceStation *AllianceStation) updateBatteryTrend(factor float64) {
+	dsConn := allianceStation.DsConn
+	if dsConn == nil || !dsConn.RobotLinked || dsConn.BatteryVoltage <= 0 {
+		allianceStation.batteryEma = 0
+		return
+	}
+	if allianceStation.batteryEma == 0 {
+		allianceStation.batteryEma = dsConn.BatteryVoltage
+	} else {
+		allianceStation.batteryEma += factor * (dsConn.BatteryVoltage - allianceStation.batteryEma)
+	}
+}
+
+// Returns the configured battery smoothing factor, or the default if it isn't within (0, 1].
+func (arena *Arena) batteryEmaFactor() float64 {
+	if factor := arena.EventSettings.BatteryEmaFactor; factor > 0 && factor <= 1 {
+		return factor
+	}
+	return defaultBatteryEmaFactor
+}
+
+// Returns the battery voltage last reported by the robot in the given station along with its moving average. An
+// average below the current voltage means the voltage has been dropping. Returns zeros if the robot isn't linked or
+// the station is invalid.
+func (arena *Arena) BatteryTrend(station string) (current, ema float64) {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok || allianceStation.DsConn == nil || allianceStation.batteryEma == 0 {
+		return 0, 0
+	}
+	return allianceStation.DsConn.BatteryVoltage, allianceStation.batteryEma
+}
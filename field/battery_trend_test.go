@@ -0,0 +1,85 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBatteryTrend(t *testing.T) {
+	arena := setupTestArena(t)
+	dsConn := &DriverStationConnection{DsLinked: true, RobotLinked: true, BatteryVoltage: 12.5}
+	arena.AllianceStations["R1"].DsConn = dsConn
+	sendPacket := func() {
+		dsConn.lastPacketTime = time.Now()
+		arena.sendDsPacket(false, false)
+	}
+	current, ema := arena.BatteryTrend("R1")
+	assert.Equal(t, 0.0, current)
+	assert.Equal(t, 0.0, ema)
+
+	// The first sample seeds the average.
+	sendPacket()
+	current, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 12.5, current)
+	assert.Equal(t, 12.5, ema)
+
+	// With a steadily dropping voltage, the average should settle at a lag of slope * (1 - factor) / factor behind it.
+	arena.EventSettings.BatteryEmaFactor = 0.2
+	for i := 0; i < 60; i++ {
+		dsConn.BatteryVoltage -= 0.05
+		sendPacket()
+	}
+	current, ema = arena.BatteryTrend("R1")
+	assert.InDelta(t, 9.5, current, 0.001)
+	assert.InDelta(t, 0.2, ema-current, 0.001)
+
+	// Once the voltage holds steady, the average should converge on it.
+	for i := 0; i < 60; i++ {
+		sendPacket()
+	}
+	current, ema = arena.BatteryTrend("R1")
+	assert.InDelta(t, current, ema, 0.001)
+
+	// A factor of one disables the smoothing, and an out-of-range factor falls back to the default.
+	arena.EventSettings.BatteryEmaFactor = 1
+	dsConn.BatteryVoltage = 11
+	sendPacket()
+	_, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 11.0, ema)
+	arena.EventSettings.BatteryEmaFactor = 0
+	assert.Equal(t, defaultBatteryEmaFactor, arena.batteryEmaFactor())
+	arena.EventSettings.BatteryEmaFactor = 1.5
+	assert.Equal(t, defaultBatteryEmaFactor, arena.batteryEmaFactor())
+
+	// Losing the robot link restarts the average.
+	dsConn.RobotLinked = false
+	sendPacket()
+	current, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 0.0, current)
+	assert.Equal(t, 0.0, ema)
+	dsConn.RobotLinked = true
+	dsConn.BatteryVoltage = 12
+	sendPacket()
+	_, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 12.0, ema)
+
+	// Removing the driver station should clear the average rather than leaving it behind a nil connection.
+	dsConn.AllianceStation = "R1"
+	arena.detachDsConn(dsConn)
+	current, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 0.0, current)
+	assert.Equal(t, 0.0, ema)
+	arena.AllianceStations["R1"].DsConn = dsConn
+	sendPacket()
+	_, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 12.0, ema)
+
+	assert.Nil(t, arena.LoadTestMatch())
+	_, ema = arena.BatteryTrend("R1")
+	assert.Equal(t, 0.0, ema)
+	_, ema = arena.BatteryTrend("R4")
+	assert.Equal(t, 0.0, ema)
+}
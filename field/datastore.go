@@ -0,0 +1,40 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Persistence interface through which the arena reads and writes the event data it needs to run matches.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+)
+
+// The subset of the database that the arena itself depends on. *model.Database is the default implementation; tests
+// can substitute a fake to exercise match loading and result persistence without a real database.
+type Datastore interface {
+	GetEventSettings() (*model.EventSettings, error)
+	GetAllAlliances() ([]model.Alliance, error)
+	GetAllTeams() ([]model.Team, error)
+	GetTeamById(id int) (*model.Team, error)
+	UpdateTeam(team *model.Team) error
+	GetMatchById(id int) (*model.Match, error)
+	GetMatchByName(matchType string, displayName string) (*model.Match, error)
+	GetMatchesByType(matchType string) ([]model.Match, error)
+	UpdateMatch(match *model.Match) error
+	GetMatchResultForMatch(matchId int) (*model.MatchResult, error)
+	GetRankingForTeam(teamId int) (*game.Ranking, error)
+	GetAllRankings() (game.Rankings, error)
+	ReplaceAllRankings(rankings game.Rankings) error
+	GetOffFieldTeamIds(match *model.Match) ([]int, []int, error)
+	GetMatchProgress() (*model.MatchProgress, error)
+	SaveMatchProgress(matchProgress *model.MatchProgress) error
+	ClearMatchProgress() error
+}
+
+var _ Datastore = (*model.Database)(nil)
+
+// Replaces the database backing the arena, e.g. after a backup is restored, and makes it the arena's datastore.
+func (arena *Arena) SetDatabase(database *model.Database) {
+	arena.Database = database
+	arena.Datastore = database
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// In-memory stand-in for the database's team and match storage; everything else falls through to the embedded store.
+type fakeDatastore struct {
+	Datastore
+	teams          map[int]model.Team
+	matches        []model.Match
+	updatedMatches []model.Match
+}
+
+func (store *fakeDatastore) GetTeamById(id int) (*model.Team, error) {
+	if team, ok := store.teams[id]; ok {
+		return &team, nil
+	}
+	return nil, nil
+}
+
+func (store *fakeDatastore) GetMatchesByType(matchType string) ([]model.Match, error) {
+	var matches []model.Match
+	for _, match := range store.matches {
+		if match.Type == matchType {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func (store *fakeDatastore) UpdateMatch(match *model.Match) error {
+	store.updatedMatches = append(store.updatedMatches, *match)
+	return nil
+}
+
+func TestArenaWithFakeDatastore(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Equal(t, arena.Database, arena.Datastore)
+	store := &fakeDatastore{
+		Datastore: arena.Datastore,
+		teams:     map[int]model.Team{254: {Id: 254, Nickname: "The Cheesy Poofs"}, 1114: {Id: 1114}},
+		matches: []model.Match{
+			{Id: 1, Type: "qualification", DisplayName: "1", Status: game.RedWonMatch},
+			{Id: 2, Type: "qualification", DisplayName: "2", Red1: 254, Blue1: 1114},
+		},
+	}
+	arena.Datastore = store
+
+	arena.CurrentMatch = &store.matches[0]
+	assert.Nil(t, arena.LoadNextMatch())
+	assert.Equal(t, 2, arena.CurrentMatch.Id)
+	assert.Equal(t, "The Cheesy Poofs", arena.AllianceStations["R1"].Team.Nickname)
+	assert.Equal(t, 1114, arena.AllianceStations["B1"].Team.Id)
+	remaining, err := arena.RemainingMatchCount("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, remaining)
+
+	// Persisting the substitution should go to the injected store rather than the real database.
+	assert.Nil(t, arena.AuthorizedSubstituteTeam(0, "B1"))
+	if assert.NotEmpty(t, store.updatedMatches) {
+		updatedMatch := store.updatedMatches[len(store.updatedMatches)-1]
+		assert.Equal(t, 2, updatedMatch.Id)
+		assert.True(t, updatedMatch.TeamsSubstituted)
+		assert.Equal(t, 0, updatedMatch.Blue1)
+	}
+	match, err := arena.Database.GetMatchById(2)
+	assert.Nil(t, err)
+	assert.Nil(t, match)
+}
+
+func TestSetDatabase(t *testing.T) {
+	arena := setupTestArena(t)
+	database := model.SetupTestDb(t, "field_datastore")
+
+	arena.SetDatabase(database)
+	assert.Equal(t, database, arena.Database)
+	assert.Equal(t, database, arena.Datastore)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Briefly enabling a single robot outside of a match, so that the FTA can check it over without running a test match.
+
+package field
+
+import (
+	"log"
+	"time"
+)
+
+// Longest time that a robot can be enabled for diagnostics in one go.
+const maxDiagnosticEnableSec = 30
+
+// The robot currently enabled for diagnostics, if any.
+type diagnosticEnable struct {
+	station string
+	teamId  int
+	until   time.Time
+}
+
+// Enables the robot in the given station in teleop mode for the given number of seconds, after which it is disabled
+// again automatically. Only allowed before a match has started, with no stops active anywhere on the field and the
+// station's driver station connected. The enable ends early if any of those conditions stops holding, and only one
+// robot can be enabled this way at a time.
+func (arena *Arena) DiagnosticEnable(station string, durationSec int) error {
+	if arena.MatchState != PreMatch {
+		return newArenaError(InvalidStateError, "Cannot enable a robot for diagnostics outside of the pre-match state.")
+	}
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok || allianceStation.Neutral {
+		return newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	if durationSec <= 0 || durationSec > maxDiagnosticEnableSec {
+		return newArenaError(
+			InvalidDurationError, "Diagnostic enable duration must be between 1 and %d seconds.",
+			maxDiagnosticEnableSec,
+		)
+	}
+	if arena.anyStopActive() {
+		return newArenaError(InvalidStateError, "Cannot enable a robot for diagnostics while a stop is active.")
+	}
+
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	arena.diagnosticEnableMutex.Lock()
+	defer arena.diagnosticEnableMutex.Unlock()
+	if arena.diagnosticEnable != nil && arena.diagnosticEnable.station != station {
+		return newArenaError(
+			InvalidStateError, "Cannot enable a robot for diagnostics while the one in station %s is enabled.",
+			arena.diagnosticEnable.station,
+		)
+	}
+	if allianceStation.Bypass || allianceStation.Team == nil || allianceStation.DsConn == nil {
+		return newArenaError(
+			NotReadyError, "Cannot enable the robot in station %s for diagnostics without a connected driver station.",
+			station,
+		)
+	}
+	teamId := allianceStation.Team.Id
+	arena.diagnosticEnable = &diagnosticEnable{
+		station: station, teamId: teamId, until: arena.now().Add(time.Duration(durationSec) * time.Second),
+	}
+	// Send the enable command on the next loop rather than waiting for the periodic packet.
+	arena.lastDsPacketTime = time.Time{}
+	log.Printf("Enabling team %d in station %s for diagnostics for %d seconds.", teamId, station, durationSec)
+	return nil
+}
+
+// Disables the robot enabled for diagnostics, if there is one, ahead of its time running out.
+func (arena *Arena) CancelDiagnosticEnable() {
+	arena.diagnosticEnableMutex.Lock()
+	defer arena.diagnosticEnableMutex.Unlock()
+	arena.endDiagnosticEnable("it was cancelled")
+}
+
+// Ends the diagnostic enable if its time has run out or the conditions for it no longer hold. Called from the arena
+// loop before deciding what to send to the robots.
+func (arena *Arena) checkDiagnosticEnable() {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	arena.diagnosticEnableMutex.Lock()
+	defer arena.diagnosticEnableMutex.Unlock()
+	enable := arena.diagnosticEnable
+	if enable == nil {
+		return
+	}
+	allianceStation := arena.AllianceStations[enable.station]
+	switch {
+	case arena.MatchState != PreMatch:
+		arena.endDiagnosticEnable("the match state changed")
+	case !arena.now().Before(enable.until):
+		arena.endDiagnosticEnable("its time ran out")
+	case arena.anyStopActive():
+		arena.endDiagnosticEnable("a stop was activated")
+	case allianceStation.Bypass || allianceStation.Team == nil || allianceStation.Team.Id != enable.teamId:
+		arena.endDiagnosticEnable("the station changed")
+	}
+}
+
+// Returns the station whose robot is currently enabled for diagnostics, or the empty string if there is none.
+func (arena *Arena) diagnosticEnabledStation() string {
+	arena.diagnosticEnableMutex.Lock()
+	defer arena.diagnosticEnableMutex.Unlock()
+	if arena.diagnosticEnable == nil {
+		return ""
+	}
+	return arena.diagnosticEnable.station
+}
+
+// Clears the diagnostic enable, if there is one, and logs why. Must be called with diagnosticEnableMutex held.
+func (arena *Arena) endDiagnosticEnable(reason string) {
+	enable := arena.diagnosticEnable
+	if enable == nil {
+		return
+	}
+	arena.diagnosticEnable = nil
+	// Disable the robot on the next loop rather than waiting for the periodic packet.
+	arena.lastDsPacketTime = time.Time{}
+	log.Printf(
+		"Disabled team %d in station %s after enabling it for diagnostics, since %s.", enable.teamId, enable.station,
+		reason,
+	)
+}
+
+// Returns an error if a robot is enabled for diagnostics, since it must be disabled before a match can start.
+func (arena *Arena) checkNoDiagnosticEnable() error {
+	arena.diagnosticEnableMutex.Lock()
+	defer arena.diagnosticEnableMutex.Unlock()
+	if arena.diagnosticEnable != nil {
+		return newArenaError(
+			NotReadyError, "Cannot start match while the robot in station %s is enabled for diagnostics.",
+			arena.diagnosticEnable.station,
+		)
+	}
+	return nil
+}
+
+// Returns true if the field e-stop or any station's e-stop or a-stop is active.
+func (arena *Arena) anyStopActive() bool {
+	if arena.Plc.GetFieldEstop() {
+		return true
+	}
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.Estop || allianceStation.Astop {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDiagnosticEnable(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	assert.Nil(t, arena.assignTeam(254, "R2"))
+	dsConn := &DriverStationConnection{
+		TeamId: 254, AllianceStation: "R2", DsLinked: true, RobotLinked: true, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["R2"].DsConn = dsConn
+	otherDsConn := &DriverStationConnection{DsLinked: true, RobotLinked: true, lastPacketTime: time.Now()}
+	arena.AllianceStations["B1"].DsConn = otherDsConn
+
+	// Only the requested robot should be enabled, in teleop, until its time runs out.
+	assert.Nil(t, arena.DiagnosticEnable("R2", 5))
+	arena.Update()
+	assert.True(t, dsConn.Enabled)
+	assert.False(t, dsConn.Auto)
+	assert.False(t, otherDsConn.Enabled)
+	assert.True(t, arena.RobotsEnabled())
+	assert.Contains(
+		t, arena.CheckCanStartMatchAll(),
+		newArenaError(NotReadyError, "Cannot start match while the robot in station R2 is enabled for diagnostics."),
+	)
+	currentTime = currentTime.Add(4 * time.Second)
+	arena.Update()
+	assert.True(t, dsConn.Enabled)
+	currentTime = currentTime.Add(time.Second)
+	arena.Update()
+	assert.False(t, dsConn.Enabled)
+	assert.False(t, arena.RobotsEnabled())
+
+	// Activating a stop ends the enable right away.
+	assert.Nil(t, arena.DiagnosticEnable("R2", 10))
+	arena.Update()
+	assert.True(t, dsConn.Enabled)
+	arena.AllianceStations["B3"].Estop = true
+	arena.Update()
+	assert.False(t, dsConn.Enabled)
+	arena.AllianceStations["B3"].Estop = true
+	err := arena.DiagnosticEnable("R2", 10)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot enable a robot for diagnostics while a stop is active.", err.Error())
+	}
+	arena.AllianceStations["B3"].Estop = false
+
+	// So does changing the station's team or cancelling.
+	assert.Nil(t, arena.DiagnosticEnable("R2", 10))
+	arena.Update()
+	assert.True(t, dsConn.Enabled)
+	arena.CancelDiagnosticEnable()
+	arena.Update()
+	assert.False(t, dsConn.Enabled)
+	assert.Nil(t, arena.DiagnosticEnable("R2", 10))
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "test"}))
+	arena.Update()
+	assert.Equal(t, "", arena.diagnosticEnabledStation())
+
+	// The request itself is validated.
+	assert.Nil(t, arena.assignTeam(254, "R2"))
+	arena.AllianceStations["R2"].DsConn = dsConn
+	for _, durationSec := range []int{0, -1, maxDiagnosticEnableSec + 1} {
+		err = arena.DiagnosticEnable("R2", durationSec)
+		if assert.NotNil(t, err) {
+			assert.True(t, IsArenaErrorCode(err, InvalidDurationError))
+		}
+	}
+	err = arena.DiagnosticEnable("R4", 5)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+	err = arena.DiagnosticEnable("R1", 5)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+	}
+	arena.AllianceStations["R2"].Bypass = true
+	assert.NotNil(t, arena.DiagnosticEnable("R2", 5))
+	arena.AllianceStations["R2"].Bypass = false
+	assert.Nil(t, arena.DiagnosticEnable("R2", 5))
+	err = arena.DiagnosticEnable("B1", 5)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "the one in station R2 is enabled")
+	}
+}
+
+func TestDiagnosticEnableBlockedDuringMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Nil(t, arena.assignTeam(254, "R2"))
+	dsConn := &DriverStationConnection{
+		TeamId: 254, AllianceStation: "R2", DsLinked: true, RobotLinked: true, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["R2"].DsConn = dsConn
+
+	for _, state := range []MatchState{StartMatch, WarmupPeriod, AutoPeriod, PausePeriod, TeleopPeriod, PostMatch,
+		TimeoutActive} {
+		arena.MatchState = state
+		err := arena.DiagnosticEnable("R2", 5)
+		if assert.NotNil(t, err, state.String()) {
+			assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+		}
+	}
+
+	// An enable that somehow outlives the pre-match state is never applied.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.DiagnosticEnable("R2", 5))
+	arena.MatchState = PausePeriod
+	arena.MatchStartTime = time.Now().Add(-arena.MatchTiming.GetDurationToAutoEnd())
+	arena.lastDsPacketTime = time.Time{}
+	arena.Update()
+	assert.False(t, dsConn.Enabled)
+	assert.Equal(t, "", arena.diagnosticEnabledStation())
+}
@@ -7,13 +7,12 @@ package field
 
 import (
 	"fmt"
-	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/model"
-	"github.com/Team254/cheesy-arena-lite/network"
 	"log"
 	"net"
 	"regexp"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,6 +27,10 @@ const (
 	maxTcpPacketBytes              = 4096
 )
 
+// Opening the TCP listener is retried at this interval until it succeeds, so that the field recovers by itself once
+// the server's address is corrected.
+const dsListenRetryPeriodSec = 5
+
 type DriverStationConnection struct {
 	TeamId                    int
 	AllianceStation           string
@@ -37,18 +40,24 @@ type DriverStationConnection struct {
 	DsLinked                  bool
 	RadioLinked               bool
 	RobotLinked               bool
+	RobotCodeRunning          bool
+	RobotEnabled              bool
 	BatteryVoltage            float64
 	DsRobotTripTimeMs         int
 	MissedPacketCount         int
 	SecondsSinceLastRobotLink float64
 	lastPacketTime            time.Time
 	lastRobotLinkedTime       time.Time
+	lastEnabledTime           time.Time
 	packetCount               int
 	missedPacketOffset        int
 	tcpConn                   net.Conn
 	udpConn                   net.Conn
 	log                       *TeamMatchLog
 
+	// Sent the status reported by the next UDP status packet when it arrives; guarded by allianceStationsMutex.
+	statusPacketWaiters []chan dsStatusSnapshot
+
 	// WrongStation indicates if the team in the station is the incorrect team
 	// by being non-empty. If the team is in the correct station, or no team is
 	// connected, this is empty.
@@ -57,87 +66,155 @@ type DriverStationConnection struct {
 
 var allianceStationPositionMap = map[string]byte{"R1": 0, "R2": 1, "R3": 2, "B1": 3, "B2": 4, "B3": 5}
 
-// Opens a UDP connection for communicating to the driver station.
-func newDriverStationConnection(teamId int, allianceStation string, tcpConn net.Conn) (*DriverStationConnection, error) {
+// Opens a UDP connection for communicating to the driver station, retrying up to the given number of times with
+// exponential backoff starting at the given interval.
+func newDriverStationConnection(
+	teamId int, allianceStation string, tcpConn net.Conn, connectRetries, connectBackoffMs int,
+) (*DriverStationConnection, error) {
 	ipAddress, _, err := net.SplitHostPort(tcpConn.RemoteAddr().String())
 	if err != nil {
 		return nil, err
 	}
 	log.Printf("Driver station for Team %d connected from %s\n", teamId, ipAddress)
 
-	udpConn, err := net.Dial("udp4", fmt.Sprintf("%s:%d", ipAddress, driverStationUdpSendPort))
+	udpConn, err := dialDriverStationUdp(ipAddress, connectRetries, connectBackoffMs)
 	if err != nil {
 		return nil, err
 	}
 	return &DriverStationConnection{TeamId: teamId, AllianceStation: allianceStation, tcpConn: tcpConn, udpConn: udpConn}, nil
 }
 
+// Opens the UDP connection to the driver station at the given address, retrying with exponential backoff on failure.
+// Dialing UDP sends nothing, so what can fail and is retried is the local setup of the socket, such as finding a route
+// to the driver station while the field network interface is briefly down. The total wait before giving up is at most
+// connectBackoffMs * (2^connectRetries - 1) milliseconds (see EventSettings.DsConnectMaxWaitMs); it is spent in the
+// goroutine registering this driver station, blocking neither the arena loop nor the accepting of other ones.
+func dialDriverStationUdp(ipAddress string, connectRetries, connectBackoffMs int) (net.Conn, error) {
+	backoff := time.Duration(connectBackoffMs) * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		udpConn, err := net.Dial("udp4", fmt.Sprintf("%s:%d", ipAddress, driverStationUdpSendPort))
+		if err == nil {
+			return udpConn, nil
+		}
+		if attempt > connectRetries {
+			return nil, err
+		}
+		log.Printf("Failed to open UDP connection to %s (attempt %d): %v", ipAddress, attempt, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
 // Loops indefinitely to read packets and update connection status.
 func (arena *Arena) listenForDsUdpPackets() {
-	udpAddress, _ := net.ResolveUDPAddr("udp4", fmt.Sprintf(":%d", driverStationUdpReceivePort))
+	udpAddress, _ := net.ResolveUDPAddr("udp4", fmt.Sprintf(":%d", arena.DsUdpListenPort))
 	listener, err := net.ListenUDP("udp4", udpAddress)
 	if err != nil {
 		log.Fatalf("Error opening driver station UDP socket: %v", err)
 	}
-	log.Printf("Listening for driver stations on UDP port %d\n", driverStationUdpReceivePort)
+	log.Printf("Listening for driver stations on UDP port %d\n", arena.DsUdpListenPort)
 
 	var data [50]byte
 	for {
 		listener.Read(data[:])
+		arena.handleDsUdpPacket(data)
+	}
+}
 
-		teamId := int(data[4])<<8 + int(data[5])
-
-		var dsConn *DriverStationConnection
-		for _, allianceStation := range arena.AllianceStations {
-			if allianceStation.Team != nil && allianceStation.Team.Id == teamId {
-				dsConn = allianceStation.DsConn
-				break
-			}
+// Updates the connection status of the driver station that sent the given UDP status packet, if it belongs to a team
+// in the current match.
+func (arena *Arena) handleDsUdpPacket(data [50]byte) {
+	teamId := int(data[4])<<8 + int(data[5])
+
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	var dsConn *DriverStationConnection
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.Team != nil && allianceStation.Team.Id == teamId {
+			dsConn = allianceStation.DsConn
+			break
 		}
+	}
+	if dsConn == nil {
+		return
+	}
 
-		if dsConn != nil {
-			dsConn.DsLinked = true
-			dsConn.lastPacketTime = time.Now()
+	previousStatus := dsConn.statusSnapshot()
+	dsConn.DsLinked = true
+	dsConn.lastPacketTime = time.Now()
 
-			dsConn.RadioLinked = data[3]&0x10 != 0
-			dsConn.RobotLinked = data[3]&0x20 != 0
-			if dsConn.RobotLinked {
-				dsConn.lastRobotLinkedTime = time.Now()
+	dsConn.decodeLinkStatus(data[3])
+	if dsConn.RobotLinked {
+		dsConn.lastRobotLinkedTime = time.Now()
+	}
+	if dsConn.RobotCodeRunning {
+		// Robot battery voltage, stored as volts * 256.
+		dsConn.BatteryVoltage = float64(data[6]) + float64(data[7])/256
+	}
+	arena.tracePacket(
+		"udpStatus", dsConn, "radioLinked=%v,robotLinked=%v,robotCodeRunning=%v,robotEnabled=%v,"+
+			"batteryVoltage=%.2f", dsConn.RadioLinked, dsConn.RobotLinked, dsConn.RobotCodeRunning,
+		dsConn.RobotEnabled, dsConn.BatteryVoltage,
+	)
+	if dsConn.statusSnapshot() != previousStatus {
+		arena.markStatusChanged()
+	}
 
-				// Robot battery voltage, stored as volts * 256.
-				dsConn.BatteryVoltage = float64(data[6]) + float64(data[7])/256
-			}
-		}
+	// Wake up anything waiting for this driver station to answer. Each waiter is buffered for exactly this send.
+	for _, waiter := range dsConn.statusPacketWaiters {
+		waiter <- dsConn.statusSnapshot()
 	}
+	dsConn.statusPacketWaiters = nil
 }
 
-// Sends a control packet to the Driver Station and checks for timeout conditions.
-func (dsConn *DriverStationConnection) update(arena *Arena) error {
-	err := dsConn.sendControlPacket(arena)
+// Sends a control packet to the Driver Station and checks for timeout conditions. Returns the packet that was sent.
+func (dsConn *DriverStationConnection) update(arena *Arena) ([22]byte, error) {
+	packet := dsConn.nextControlPacket(arena)
+	err := writeControlPacket(dsConn.udpConn, packet)
 	if err != nil {
-		return err
+		return packet, err
 	}
+	dsConn.checkLinkTimeout()
+
+	return packet, nil
+}
+
+// Records when the robot was last commanded enabled and encodes the next periodic control packet.
+func (dsConn *DriverStationConnection) nextControlPacket(arena *Arena) [22]byte {
+	if dsConn.Enabled {
+		dsConn.lastEnabledTime = time.Now()
+	}
+	return dsConn.encodeControlPacket(arena)
+}
 
+// Marks the driver station as disconnected if no status packet has been received from it recently.
+func (dsConn *DriverStationConnection) checkLinkTimeout() {
 	if time.Since(dsConn.lastPacketTime).Seconds() > driverStationUdpLinkTimeoutSec {
 		dsConn.DsLinked = false
 		dsConn.RadioLinked = false
 		dsConn.RobotLinked = false
+		dsConn.RobotCodeRunning = false
+		dsConn.RobotEnabled = false
 		dsConn.BatteryVoltage = 0
 	}
 	dsConn.SecondsSinceLastRobotLink = time.Since(dsConn.lastRobotLinkedTime).Seconds()
-
-	return nil
 }
 
+// Releases the connection's resources. A failure to close one of them is logged rather than returned so that the
+// others are still released and the station can always be reassigned.
 func (dsConn *DriverStationConnection) close() {
 	if dsConn.log != nil {
 		dsConn.log.Close()
 	}
 	if dsConn.udpConn != nil {
-		dsConn.udpConn.Close()
+		if err := dsConn.udpConn.Close(); err != nil {
+			log.Printf("Error closing UDP connection to driver station for team %d: %v", dsConn.TeamId, err)
+		}
 	}
 	if dsConn.tcpConn != nil {
-		dsConn.tcpConn.Close()
+		if err := dsConn.tcpConn.Close(); err != nil {
+			log.Printf("Error closing TCP connection to driver station for team %d: %v", dsConn.TeamId, err)
+		}
 	}
 }
 
@@ -220,7 +297,9 @@ func (dsConn *DriverStationConnection) encodeControlPacket(arena *Arena) [22]byt
 	packet[18] = byte(currentTime.Month())
 	packet[19] = byte(currentTime.Year() - 1900)
 
-	// Remaining number of seconds in match.
+	// Remaining number of seconds in match, including any adjustment to the teleop duration. It never goes below zero,
+	// even if the arena loop is late in moving on from a period that has run out.
+	timing := arena.matchTiming()
 	var matchSecondsRemaining int
 	switch arena.MatchState {
 	case PreMatch:
@@ -228,19 +307,22 @@ func (dsConn *DriverStationConnection) encodeControlPacket(arena *Arena) [22]byt
 	case TimeoutActive:
 		fallthrough
 	case PostTimeout:
-		matchSecondsRemaining = game.MatchTiming.AutoDurationSec
+		matchSecondsRemaining = timing.AutoDurationSec
 	case StartMatch:
 		fallthrough
 	case AutoPeriod:
-		matchSecondsRemaining = game.MatchTiming.AutoDurationSec - int(arena.MatchTimeSec())
+		matchSecondsRemaining = timing.AutoDurationSec - int(arena.MatchTimeSec())
 	case PausePeriod:
-		matchSecondsRemaining = game.MatchTiming.TeleopDurationSec
+		matchSecondsRemaining = timing.TeleopDurationSec
 	case TeleopPeriod:
-		matchSecondsRemaining = game.MatchTiming.AutoDurationSec + game.MatchTiming.TeleopDurationSec +
-			game.MatchTiming.PauseDurationSec - int(arena.MatchTimeSec())
+		matchSecondsRemaining = timing.AutoDurationSec + timing.TeleopDurationSec + timing.PauseDurationSec -
+			int(arena.MatchTimeSec())
 	default:
 		matchSecondsRemaining = 0
 	}
+	if matchSecondsRemaining < 0 {
+		matchSecondsRemaining = 0
+	}
 	packet[20] = byte(matchSecondsRemaining >> 8 & 0xff)
 	packet[21] = byte(matchSecondsRemaining & 0xff)
 
@@ -252,9 +334,13 @@ func (dsConn *DriverStationConnection) encodeControlPacket(arena *Arena) [22]byt
 
 // Builds and sends the next control packet to the Driver Station.
 func (dsConn *DriverStationConnection) sendControlPacket(arena *Arena) error {
-	packet := dsConn.encodeControlPacket(arena)
-	if dsConn.udpConn != nil {
-		_, err := dsConn.udpConn.Write(packet[:])
+	return writeControlPacket(dsConn.udpConn, dsConn.encodeControlPacket(arena))
+}
+
+// Writes an already encoded control packet to the given connection, if there is one.
+func writeControlPacket(udpConn net.Conn, packet [22]byte) error {
+	if udpConn != nil {
+		_, err := udpConn.Write(packet[:])
 		if err != nil {
 			return err
 		}
@@ -263,6 +349,15 @@ func (dsConn *DriverStationConnection) sendControlPacket(arena *Arena) error {
 	return nil
 }
 
+// Decodes the status byte of a UDP packet from the driver station. The roboRIO may answer pings (robot linked) without
+// any robot code running to communicate with the driver station, which is reported separately.
+func (dsConn *DriverStationConnection) decodeLinkStatus(status byte) {
+	dsConn.RadioLinked = status&0x10 != 0
+	dsConn.RobotCodeRunning = status&0x20 != 0
+	dsConn.RobotLinked = status&0x08 != 0 || dsConn.RobotCodeRunning
+	dsConn.RobotEnabled = status&0x02 != 0
+}
+
 // Deserializes a packet from the DS into a structure representing the DS/robot status.
 func (dsConn *DriverStationConnection) decodeStatusPacket(data [36]byte) {
 	// Average DS-robot trip time in milliseconds.
@@ -272,17 +367,51 @@ func (dsConn *DriverStationConnection) decodeStatusPacket(data [36]byte) {
 	dsConn.MissedPacketCount = int(data[2]) - dsConn.missedPacketOffset
 }
 
+// Returns false if the field network that driver stations connect over is known to be down, i.e. the driver station
+// listener couldn't be opened on the expected address and is still being retried.
+func (arena *Arena) DsNetworkAvailable() bool {
+	return atomic.LoadInt32(&arena.dsNetworkUnavailable) == 0
+}
+
+// Attempts to open the driver station TCP listener on the field network, returning nil on failure. Records whether the
+// network is available and raises or resolves the corresponding fault when that changes.
+func (arena *Arena) openDsListener() net.Listener {
+	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", arena.DsListenIpAddress, arena.DsTcpListenPort))
+	if err != nil {
+		if atomic.SwapInt32(&arena.dsNetworkUnavailable, 1) == 0 {
+			log.Printf("Error opening driver station TCP socket: %v", err.Error())
+			log.Printf(
+				"Change IP address to %s to fix; retrying every %d seconds.", arena.DsListenIpAddress,
+				dsListenRetryPeriodSec,
+			)
+			arena.markStatusChanged()
+		}
+		// Raised on every attempt so that it is listed again if the FTA clears it while the network is still down.
+		arena.raiseFault(
+			DsNetworkUnavailableFault, TransientFault, "",
+			"Driver stations can't connect because the field network address %s is unavailable.",
+			arena.DsListenIpAddress,
+		)
+		return nil
+	}
+	if atomic.SwapInt32(&arena.dsNetworkUnavailable, 0) == 1 {
+		log.Printf("Opened driver station TCP socket after the field network became available.")
+		arena.resolveFault(DsNetworkUnavailableFault, "")
+		arena.markStatusChanged()
+	}
+	return l
+}
+
 // Listens for TCP connection requests to Cheesy Arena from driver stations.
 func (arena *Arena) listenForDriverStations() {
-	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", network.ServerIpAddress, driverStationTcpListenPort))
-	if err != nil {
-		log.Printf("Error opening driver station TCP socket: %v", err.Error())
-		log.Printf("Change IP address to %s and restart Cheesy Arena to fix.", network.ServerIpAddress)
-		return
+	l := arena.openDsListener()
+	for l == nil {
+		time.Sleep(time.Second * dsListenRetryPeriodSec)
+		l = arena.openDsListener()
 	}
 	defer l.Close()
 
-	log.Printf("Listening for driver stations on TCP port %d\n", driverStationTcpListenPort)
+	log.Printf("Listening for driver stations on TCP port %d\n", arena.DsTcpListenPort)
 	for {
 		tcpConn, err := l.Accept()
 		if err != nil {
@@ -306,6 +435,12 @@ func (arena *Arena) listenForDriverStations() {
 
 		// Check to see if the team is supposed to be on the field, and notify the DS accordingly.
 		assignedStation := arena.getAssignedAllianceStation(teamId)
+		prewarming := false
+		if assignedStation == "" {
+			// Teams in the next match may connect early if it is being pre-warmed.
+			assignedStation = arena.getPrewarmAllianceStation(teamId)
+			prewarming = assignedStation != ""
+		}
 		if assignedStation == "" {
 			log.Printf("Rejecting connection from Team %d, who is not in the current match, soon.", teamId)
 			go func() {
@@ -325,7 +460,7 @@ func (arena *Arena) listenForDriverStations() {
 		teamDigit2, _ := strconv.Atoi(teamDigits[2])
 		stationTeamId := teamDigit1*100 + teamDigit2
 		wrongAssignedStation := ""
-		if stationTeamId != teamId {
+		if stationTeamId != teamId && !prewarming {
 			wrongAssignedStation = arena.getAssignedAllianceStation(stationTeamId)
 			if wrongAssignedStation != "" {
 				// The team is supposed to be in this match, but is plugged into the wrong station.
@@ -338,7 +473,12 @@ func (arena *Arena) listenForDriverStations() {
 		assignmentPacket[0] = 0  // Packet size
 		assignmentPacket[1] = 3  // Packet size
 		assignmentPacket[2] = 25 // Packet type
-		log.Printf("Accepting connection from Team %d in station %s.", teamId, assignedStation)
+		if prewarming {
+			log.Printf("Accepting early connection from Team %d in station %s for the next match.", teamId,
+				assignedStation)
+		} else {
+			log.Printf("Accepting connection from Team %d in station %s.", teamId, assignedStation)
+		}
 		assignmentPacket[3] = allianceStationPositionMap[assignedStation]
 		assignmentPacket[4] = stationStatus
 		_, err = tcpConn.Write(assignmentPacket[:])
@@ -348,20 +488,63 @@ func (arena *Arena) listenForDriverStations() {
 			continue
 		}
 
-		dsConn, err := newDriverStationConnection(teamId, assignedStation, tcpConn)
-		if err != nil {
-			log.Printf("Error registering driver station connection: %v", err)
+		go arena.registerDsConn(teamId, assignedStation, wrongAssignedStation, tcpConn)
+	}
+}
+
+// Opens the connection to an accepted driver station, installs it in its alliance station and handles further TCP
+// communication with it.
+func (arena *Arena) registerDsConn(teamId int, assignedStation, wrongAssignedStation string, tcpConn net.Conn) {
+	dsConn, err := newDriverStationConnection(
+		teamId, assignedStation, tcpConn, arena.EventSettings.DsConnectRetries, arena.EventSettings.DsConnectBackoffMs,
+	)
+	if err != nil {
+		log.Printf("Error registering driver station connection: %v", err)
+		if !arena.holdPendingTcpConn(teamId, assignedStation, tcpConn) {
 			tcpConn.Close()
-			continue
 		}
-		arena.AllianceStations[assignedStation].DsConn = dsConn
+		return
+	}
+	if wrongAssignedStation != "" {
+		dsConn.WrongStation = wrongAssignedStation
+	}
+	if !arena.attachDsConn(dsConn) && !arena.holdPrewarmedDsConn(dsConn) {
+		log.Printf("Team %d was reassigned out of station %s while connecting; rejecting.", teamId, assignedStation)
+		dsConn.close()
+		return
+	}
+	dsConn.handleTcpConnection(arena)
+}
 
-		if wrongAssignedStation != "" {
-			dsConn.WrongStation = wrongAssignedStation
-		}
+// Installs the given connection in its alliance station, as long as the station is still assigned to its team; the
+// station may have been reassigned by the time a connecting driver station has been accepted. Returns true if the
+// connection was attached.
+func (arena *Arena) attachDsConn(dsConn *DriverStationConnection) bool {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation := arena.AllianceStations[dsConn.AllianceStation]
+	if allianceStation.Team == nil || allianceStation.Team.Id != dsConn.TeamId {
+		return false
+	}
+	allianceStation.DsConn = dsConn
+	arena.markStatusChanged()
+	return true
+}
+
+// Removes the given connection from its alliance station, unless it has already been replaced.
+func (arena *Arena) detachDsConn(dsConn *DriverStationConnection) {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
 
-		// Spin up a goroutine to handle further TCP communication with this driver station.
-		go dsConn.handleTcpConnection(arena)
+	allianceStation := arena.AllianceStations[dsConn.AllianceStation]
+	if allianceStation.DsConn == dsConn {
+		allianceStation.DsConn = nil
+		allianceStation.batteryEma = 0
+		arena.markStatusChanged()
+	}
+	if arena.prewarmedDsConns[dsConn.TeamId] == dsConn {
+		delete(arena.prewarmedDsConns, dsConn.TeamId)
 	}
 }
 
@@ -373,7 +556,7 @@ func (dsConn *DriverStationConnection) handleTcpConnection(arena *Arena) {
 		if err != nil {
 			log.Printf("Error reading from connection for Team %d: %v", dsConn.TeamId, err)
 			dsConn.close()
-			arena.AllianceStations[dsConn.AllianceStation].DsConn = nil
+			arena.detachDsConn(dsConn)
 			break
 		}
 
@@ -385,7 +568,17 @@ func (dsConn *DriverStationConnection) handleTcpConnection(arena *Arena) {
 			// Robot status packet.
 			var statusPacket [36]byte
 			copy(statusPacket[:], buffer[2:38])
+			arena.allianceStationsMutex.Lock()
+			previousStatus := dsConn.statusSnapshot()
 			dsConn.decodeStatusPacket(statusPacket)
+			if dsConn.statusSnapshot() != previousStatus {
+				arena.markStatusChanged()
+			}
+			arena.allianceStationsMutex.Unlock()
+			arena.tracePacket(
+				"tcpStatus", dsConn, "dsRobotTripTimeMs=%d,missedPacketCount=%d", dsConn.DsRobotTripTimeMs,
+				dsConn.MissedPacketCount,
+			)
 		}
 
 		// Log the packet if the match is in progress.
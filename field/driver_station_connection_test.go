@@ -4,7 +4,8 @@
 package field
 
 import (
-	"github.com/Team254/cheesy-arena-lite/network"
+	"errors"
+	"github.com/Team254/cheesy-arena-lite/model"
 	"github.com/stretchr/testify/assert"
 	"net"
 	"testing"
@@ -16,7 +17,7 @@ func TestEncodeControlPacket(t *testing.T) {
 
 	tcpConn := setupFakeTcpConnection(t)
 	defer tcpConn.Close()
-	dsConn, err := newDriverStationConnection(254, "R1", tcpConn)
+	dsConn, err := newDriverStationConnection(254, "R1", tcpConn, 0, 0)
 	assert.Nil(t, err)
 	defer dsConn.close()
 
@@ -136,7 +137,7 @@ func TestSendControlPacket(t *testing.T) {
 
 	tcpConn := setupFakeTcpConnection(t)
 	defer tcpConn.Close()
-	dsConn, err := newDriverStationConnection(254, "R1", tcpConn)
+	dsConn, err := newDriverStationConnection(254, "R1", tcpConn, 0, 0)
 	assert.Nil(t, err)
 	defer dsConn.close()
 
@@ -145,10 +146,27 @@ func TestSendControlPacket(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestNewDriverStationConnectionRetries(t *testing.T) {
+	startTime := time.Now()
+	_, err := newDriverStationConnection(254, "R1", &fakeRemoteAddrConn{addr: "[::1]:1234"}, 3, 50)
+	assert.NotNil(t, err)
+
+	// Check that all the attempts were made but that it didn't take unreasonably long to give up.
+	elapsed := time.Since(startTime)
+	assert.GreaterOrEqual(t, elapsed, 350*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+
+	// Without retries it should give up straight away.
+	startTime = time.Now()
+	_, err = newDriverStationConnection(254, "R1", &fakeRemoteAddrConn{addr: "[::1]:1234"}, 0, 50)
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(startTime), 50*time.Millisecond)
+}
+
 func TestDecodeStatusPacket(t *testing.T) {
 	tcpConn := setupFakeTcpConnection(t)
 	defer tcpConn.Close()
-	dsConn, err := newDriverStationConnection(254, "R1", tcpConn)
+	dsConn, err := newDriverStationConnection(254, "R1", tcpConn, 0, 0)
 	assert.Nil(t, err)
 	defer dsConn.close()
 
@@ -162,11 +180,9 @@ func TestDecodeStatusPacket(t *testing.T) {
 func TestListenForDriverStations(t *testing.T) {
 	arena := setupTestArena(t)
 
-	oldAddress := network.ServerIpAddress
-	network.ServerIpAddress = "127.0.0.1"
+	arena.DsListenIpAddress = "127.0.0.1"
 	go arena.listenForDriverStations()
 	time.Sleep(time.Millisecond * 10)
-	network.ServerIpAddress = oldAddress // Put it back to avoid affecting other tests.
 
 	// Connect with an invalid initial packet.
 	tcpConn, err := net.Dial("tcp", "127.0.0.1:1750")
@@ -222,6 +238,26 @@ func TestListenForDriverStations(t *testing.T) {
 	}
 }
 
+// Stand-in connection that only reports a fixed remote address.
+type fakeRemoteAddrConn struct {
+	net.Conn
+	addr string
+}
+
+func (conn *fakeRemoteAddrConn) RemoteAddr() net.Addr {
+	return fakeAddr(conn.addr)
+}
+
+type fakeAddr string
+
+func (addr fakeAddr) Network() string {
+	return "tcp"
+}
+
+func (addr fakeAddr) String() string {
+	return string(addr)
+}
+
 func setupFakeTcpConnection(t *testing.T) net.Conn {
 	// Set up a fake TCP endpoint and connection to it.
 	l, err := net.Listen("tcp", ":9999")
@@ -231,3 +267,80 @@ func setupFakeTcpConnection(t *testing.T) net.Conn {
 	assert.Nil(t, err)
 	return tcpConn
 }
+
+func TestDecodeLinkStatus(t *testing.T) {
+	dsConn := &DriverStationConnection{}
+
+	dsConn.decodeLinkStatus(0x00)
+	assert.False(t, dsConn.RadioLinked)
+	assert.False(t, dsConn.RobotLinked)
+	assert.False(t, dsConn.RobotCodeRunning)
+
+	dsConn.decodeLinkStatus(0x10)
+	assert.True(t, dsConn.RadioLinked)
+	assert.False(t, dsConn.RobotLinked)
+	assert.False(t, dsConn.RobotCodeRunning)
+
+	// The roboRIO is reachable but no robot code is running.
+	dsConn.decodeLinkStatus(0x18)
+	assert.True(t, dsConn.RadioLinked)
+	assert.True(t, dsConn.RobotLinked)
+	assert.False(t, dsConn.RobotCodeRunning)
+
+	dsConn.decodeLinkStatus(0x38)
+	assert.True(t, dsConn.RadioLinked)
+	assert.True(t, dsConn.RobotLinked)
+	assert.True(t, dsConn.RobotCodeRunning)
+	assert.False(t, dsConn.RobotEnabled)
+
+	dsConn.decodeLinkStatus(0x32)
+	assert.True(t, dsConn.RobotLinked)
+	assert.True(t, dsConn.RobotCodeRunning)
+	assert.True(t, dsConn.RobotEnabled)
+}
+
+// Connection whose Close always fails, for testing that such failures don't block reassignment.
+type failingCloseConn struct {
+	net.Conn
+	closed bool
+}
+
+func (conn *failingCloseConn) Close() error {
+	conn.closed = true
+	return errors.New("close failed")
+}
+
+func TestAssignTeamWithFailingClose(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	udpConn, tcpConn := &failingCloseConn{}, &failingCloseConn{}
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		udpConn: udpConn, tcpConn: tcpConn}
+
+	// Both connections should still be closed and the new team assigned despite the errors.
+	assert.Nil(t, arena.assignTeam(1114, "R1"))
+	assert.True(t, udpConn.closed)
+	assert.True(t, tcpConn.closed)
+	assert.Equal(t, 1114, arena.AllianceStations["R1"].Team.Id)
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+}
+
+func TestOpenDsListenerWithNetworkUnavailable(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.DsListenIpAddress = "192.0.2.1" // Reserved for documentation, so never assigned to this machine.
+
+	assert.Nil(t, arena.openDsListener())
+	assert.False(t, arena.DsNetworkAvailable())
+	if faults := arena.ActiveFaults(); assert.Equal(t, 1, len(faults)) {
+		assert.Equal(t, DsNetworkUnavailableFault, faults[0].Kind)
+		assert.Equal(t, "", faults[0].Station)
+	}
+
+	// The fault is listed again by the next attempt if it is cleared while the network is still down.
+	arena.ClearFaults()
+	assert.Nil(t, arena.openDsListener())
+	assert.Equal(t, 1, len(arena.ActiveFaults()))
+}
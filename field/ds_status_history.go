@@ -0,0 +1,87 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Fixed-size history of recent driver station status per alliance station, for diagnosing intermittent disconnects.
+
+package field
+
+import "time"
+
+// Number of snapshots kept per station by default; at one per driver station packet this covers the last 30 seconds.
+const defaultDsStatusHistorySize = 120
+
+type DsStatusSnapshot struct {
+	Time             time.Time
+	DsLinked         bool
+	RadioLinked      bool
+	RobotLinked      bool
+	RobotCodeRunning bool
+	BatteryVoltage   float64
+}
+
+// Ring buffer of status snapshots which is allocated once and then overwritten in place.
+type dsStatusHistory struct {
+	snapshots []DsStatusSnapshot
+	next      int
+	count     int
+}
+
+func newDsStatusHistory(size int) *dsStatusHistory {
+	return &dsStatusHistory{snapshots: make([]DsStatusSnapshot, size)}
+}
+
+func (history *dsStatusHistory) add(snapshot DsStatusSnapshot) {
+	if history == nil || len(history.snapshots) == 0 {
+		return
+	}
+	history.snapshots[history.next] = snapshot
+	history.next = (history.next + 1) % len(history.snapshots)
+	if history.count < len(history.snapshots) {
+		history.count++
+	}
+}
+
+// Returns a copy of the buffered snapshots, oldest first.
+func (history *dsStatusHistory) recent() []DsStatusSnapshot {
+	if history == nil {
+		return nil
+	}
+	snapshots := make([]DsStatusSnapshot, history.count)
+	start := history.next - history.count
+	if start < 0 {
+		start += len(history.snapshots)
+	}
+	for i := range snapshots {
+		snapshots[i] = history.snapshots[(start+i)%len(history.snapshots)]
+	}
+	return snapshots
+}
+
+// Sets the number of snapshots retained per station, discarding any existing history.
+func (arena *Arena) SetDsStatusHistorySize(size int) {
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.statusHistory = newDsStatusHistory(size)
+	}
+}
+
+// Returns the recent driver station status snapshots for the given station, oldest first, or nil if the station is
+// invalid.
+func (arena *Arena) RecentStatus(station string) []DsStatusSnapshot {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return nil
+	}
+	return allianceStation.statusHistory.recent()
+}
+
+// Records the station's current driver station status in its history.
+func (allianceStation *AllianceStation) recordStatus() {
+	snapshot := DsStatusSnapshot{Time: time.Now()}
+	if dsConn := allianceStation.DsConn; dsConn != nil {
+		snapshot.DsLinked = dsConn.DsLinked
+		snapshot.RadioLinked = dsConn.RadioLinked
+		snapshot.RobotLinked = dsConn.RobotLinked
+		snapshot.RobotCodeRunning = dsConn.RobotCodeRunning
+		snapshot.BatteryVoltage = dsConn.BatteryVoltage
+	}
+	allianceStation.statusHistory.add(snapshot)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDsStatusHistory(t *testing.T) {
+	history := newDsStatusHistory(3)
+	assert.Equal(t, []DsStatusSnapshot{}, history.recent())
+
+	history.add(DsStatusSnapshot{BatteryVoltage: 1})
+	history.add(DsStatusSnapshot{BatteryVoltage: 2})
+	assert.Equal(t, []DsStatusSnapshot{{BatteryVoltage: 1}, {BatteryVoltage: 2}}, history.recent())
+
+	// Check that the oldest snapshots are overwritten in place once the buffer is full.
+	history.add(DsStatusSnapshot{BatteryVoltage: 3})
+	history.add(DsStatusSnapshot{BatteryVoltage: 4})
+	history.add(DsStatusSnapshot{BatteryVoltage: 5})
+	assert.Equal(t, []DsStatusSnapshot{{BatteryVoltage: 3}, {BatteryVoltage: 4}, {BatteryVoltage: 5}}, history.recent())
+	assert.Equal(t, 3, cap(history.snapshots))
+}
+
+func TestArenaRecentStatus(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.SetDsStatusHistorySize(2)
+
+	arena.sendDsPacket(false, false)
+	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{DsLinked: true, RobotLinked: true,
+		BatteryVoltage: 12.5, lastPacketTime: time.Now()}
+	arena.sendDsPacket(false, false)
+	arena.AllianceStations["R2"].DsConn.RobotLinked = false
+	arena.sendDsPacket(false, false)
+
+	recentStatus := arena.RecentStatus("R2")
+	if assert.Equal(t, 2, len(recentStatus)) {
+		assert.True(t, recentStatus[0].RobotLinked)
+		assert.Equal(t, 12.5, recentStatus[0].BatteryVoltage)
+		assert.True(t, recentStatus[1].DsLinked)
+		assert.False(t, recentStatus[1].RobotLinked)
+		assert.False(t, recentStatus[1].Time.Before(recentStatus[0].Time))
+	}
+	assert.Equal(t, 2, len(arena.RecentStatus("B3")))
+	assert.False(t, arena.RecentStatus("B3")[1].DsLinked)
+	assert.Nil(t, arena.RecentStatus("R4"))
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Safety check for robots that report being enabled while the arena is commanding them to be disabled.
+
+package field
+
+import (
+	"log"
+	"time"
+)
+
+// Time allowed after a robot is commanded to disable for its driver station to report the change, to avoid
+// tripping on status packets that were already in flight.
+const enabledFaultGracePeriodMs = 1000
+
+// Returns true if the driver station reports that its robot is enabled even though the arena has been commanding it
+// to be disabled for longer than the grace period.
+func (allianceStation *AllianceStation) isEnabledWhenDisabled() bool {
+	dsConn := allianceStation.DsConn
+	if dsConn == nil || dsConn.Enabled || !dsConn.RobotLinked || !dsConn.RobotEnabled {
+		return false
+	}
+	return dsConn.lastEnabledTime.IsZero() ||
+		time.Since(dsConn.lastEnabledTime).Milliseconds() >= enabledFaultGracePeriodMs
+}
+
+// Latches an enabled fault for any station whose robot is ignoring the disable command, and responds to a new fault
+// by emergency stopping the entire field.
+func (arena *Arena) checkEnabledFaults() {
+	arena.allianceStationsMutex.Lock()
+	newFault := false
+	for station, allianceStation := range arena.AllianceStations {
+		if !allianceStation.EnabledFault && allianceStation.isEnabledWhenDisabled() {
+			log.Printf(
+				"CRITICAL: Robot at station %s reports being enabled while commanded disabled; stopping the field.",
+				station,
+			)
+			allianceStation.EnabledFault = true
+			newFault = true
+			arena.raiseFault(
+				EnabledWhenDisabledFault, CriticalFault, station,
+				"Robot reported being enabled while commanded disabled; the field was stopped.",
+			)
+		}
+	}
+	if newFault {
+		for _, allianceStation := range arena.AllianceStations {
+			allianceStation.Estop = true
+		}
+	}
+	arena.allianceStationsMutex.Unlock()
+	if !newFault {
+		return
+	}
+
+	arena.markStatusChanged()
+	if arena.MatchTimeSec() > 0 && !arena.matchAborted {
+		arena.AbortMatch()
+	}
+}
+
+// Returns true if any station has a latched enabled fault.
+func (arena *Arena) hasEnabledFault() bool {
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.EnabledFault {
+			return true
+		}
+	}
+	return false
+}
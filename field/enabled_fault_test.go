@@ -0,0 +1,66 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEnabledFault(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	arena.AllianceStations["R3"].Bypass = false
+	dsConn := &DriverStationConnection{
+		DsLinked: true, RobotLinked: true, RobotCodeRunning: true, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["R3"].DsConn = dsConn
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.True(t, dsConn.Enabled)
+
+	// A robot reporting enabled while it is supposed to be enabled is fine.
+	dsConn.RobotEnabled = true
+	arena.sendDsPacket(true, true)
+	assert.False(t, arena.AllianceStations["R3"].EnabledFault)
+
+	// A status reported shortly after disabling shouldn't trip the fault.
+	arena.sendDsPacket(true, false)
+	assert.False(t, arena.AllianceStations["R3"].EnabledFault)
+
+	// Once the grace period has passed, the fault should latch and stop the whole field.
+	dsConn.lastEnabledTime = time.Now().Add(-enabledFaultGracePeriodMs * time.Millisecond)
+	dsConn.lastPacketTime = time.Now()
+	arena.sendDsPacket(true, false)
+	assert.True(t, arena.AllianceStations["R3"].EnabledFault)
+	assert.True(t, arena.hasEnabledFault())
+	assert.Equal(t, PostMatch, arena.MatchState)
+	for _, allianceStation := range arena.AllianceStations {
+		assert.True(t, allianceStation.Estop)
+	}
+
+	// Check that the e-stops stay latched after the match even though the stations aren't requesting them.
+	arena.Update()
+	arena.handleEstop("R1", false)
+	assert.True(t, arena.AllianceStations["R1"].Estop)
+
+	// Check that the fault blocks the next match until a new match is loaded.
+	assert.Nil(t, arena.ResetMatch())
+	arena.handleEstop("R1", false)
+	assert.True(t, arena.AllianceStations["R1"].Estop)
+	err := arena.checkCanStartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "a robot has reported being enabled when it should be disabled")
+	}
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.False(t, arena.hasEnabledFault())
+	arena.handleEstop("R1", false)
+	assert.False(t, arena.AllianceStations["R1"].Estop)
+}
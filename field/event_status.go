@@ -7,7 +7,6 @@ package field
 
 import (
 	"fmt"
-	"math"
 	"time"
 )
 
@@ -48,23 +47,43 @@ func (arena *Arena) updateEarlyLateMessage() {
 
 // Updates the string that indicates how early or late the event is running.
 func (arena *Arena) getEarlyLateMessage() string {
+	delta, ok := arena.ScheduleDelta()
+	if !ok {
+		return ""
+	}
+	minutesLate := delta.Minutes()
+	if minutesLate > earlyLateThresholdMin {
+		return fmt.Sprintf("Event is running %d minutes late", int(minutesLate))
+	} else if minutesLate < -earlyLateThresholdMin {
+		return fmt.Sprintf("Event is running %d minutes early", int(-minutesLate))
+	}
+	return "Event is running on schedule"
+}
+
+// Returns how far behind schedule the event is running, based on the scheduled and actual start times of the current
+// match and the ones adjacent to it. The delta is negative if the event is running ahead of schedule. Returns false if
+// the current match doesn't follow a strict schedule or has no scheduled time.
+func (arena *Arena) ScheduleDelta() (time.Duration, bool) {
 	currentMatch := arena.CurrentMatch
 	if currentMatch.Type != "practice" && currentMatch.Type != "qualification" {
 		// Only practice and qualification matches have a strict schedule.
-		return ""
+		return 0, false
 	}
 	if currentMatch.IsComplete() {
 		// This is a replay or otherwise unpredictable situation.
-		return ""
+		return 0, false
+	}
+	if currentMatch.Time.IsZero() {
+		return 0, false
 	}
 
-	var minutesLate float64
+	var delta time.Duration
 	if arena.MatchState > PreMatch && arena.MatchState < PostMatch {
 		// The match is in progress; simply calculate lateness from its start time.
-		minutesLate = currentMatch.StartedAt.Sub(currentMatch.Time).Minutes()
+		delta = currentMatch.StartedAt.Sub(currentMatch.Time)
 	} else {
 		// We need to check the adjacent matches to accurately determine lateness.
-		matches, _ := arena.Database.GetMatchesByType(currentMatch.Type)
+		matches, _ := arena.Datastore.GetMatchesByType(currentMatch.Type)
 
 		previousMatchIndex := -1
 		nextMatchIndex := len(matches)
@@ -77,31 +96,32 @@ func (arena *Arena) getEarlyLateMessage() string {
 		}
 
 		if arena.MatchState == PreMatch {
-			currentMinutesLate := time.Now().Sub(currentMatch.Time).Minutes()
+			currentDelta := arena.now().Sub(currentMatch.Time)
 			if previousMatchIndex >= 0 &&
 				currentMatch.Time.Sub(matches[previousMatchIndex].Time).Minutes() <= MaxMatchGapMin {
 				previousMatch := matches[previousMatchIndex]
-				previousMinutesLate := previousMatch.StartedAt.Sub(previousMatch.Time).Minutes()
-				minutesLate = math.Max(previousMinutesLate, currentMinutesLate)
+				previousDelta := previousMatch.StartedAt.Sub(previousMatch.Time)
+				delta = maxDuration(previousDelta, currentDelta)
 			} else {
-				minutesLate = math.Max(currentMinutesLate, 0)
+				delta = maxDuration(currentDelta, 0)
 			}
 		} else if arena.MatchState == PostMatch {
-			currentMinutesLate := currentMatch.StartedAt.Sub(currentMatch.Time).Minutes()
+			currentDelta := currentMatch.StartedAt.Sub(currentMatch.Time)
 			if nextMatchIndex < len(matches) {
 				nextMatch := matches[nextMatchIndex]
-				nextMinutesLate := time.Now().Sub(nextMatch.Time).Minutes()
-				minutesLate = math.Max(currentMinutesLate, nextMinutesLate)
+				nextDelta := arena.now().Sub(nextMatch.Time)
+				delta = maxDuration(currentDelta, nextDelta)
 			} else {
-				minutesLate = currentMinutesLate
+				delta = currentDelta
 			}
 		}
 	}
+	return delta, true
+}
 
-	if minutesLate > earlyLateThresholdMin {
-		return fmt.Sprintf("Event is running %d minutes late", int(minutesLate))
-	} else if minutesLate < -earlyLateThresholdMin {
-		return fmt.Sprintf("Event is running %d minutes early", int(-minutesLate))
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
 	}
-	return "Event is running on schedule"
+	return b
 }
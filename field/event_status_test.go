@@ -115,6 +115,51 @@ func TestEarlyLateMessage(t *testing.T) {
 	assert.Equal(t, "", arena.getEarlyLateMessage())
 }
 
+func TestScheduleDelta(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+
+	arena.LoadTestMatch()
+	delta, ok := arena.ScheduleDelta()
+	assert.False(t, ok)
+	assert.Equal(t, time.Duration(0), delta)
+
+	// A scheduled match without a time can't be compared against the schedule.
+	arena.CurrentMatch = &model.Match{Type: "qualification"}
+	_, ok = arena.ScheduleDelta()
+	assert.False(t, ok)
+
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "1"})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "2"})
+	matches, _ := arena.Database.GetMatchesByType("qualification")
+	setMatch(arena.Database, &matches[0], currentTime.Add(-10*time.Minute), currentTime.Add(-3*time.Minute), false)
+	setMatch(arena.Database, &matches[1], currentTime.Add(-4*time.Minute), time.Time{}, false)
+
+	// While a match is running, the delta is taken from its actual start time.
+	arena.CurrentMatch = &matches[0]
+	arena.MatchState = TeleopPeriod
+	delta, ok = arena.ScheduleDelta()
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Minute, delta)
+
+	// Before a match starts, it is the later of the previous match's lateness and the time past its own slot.
+	arena.CurrentMatch = &matches[1]
+	arena.MatchState = PreMatch
+	delta, _ = arena.ScheduleDelta()
+	assert.Equal(t, 7*time.Minute, delta)
+	currentTime = currentTime.Add(5 * time.Minute)
+	delta, _ = arena.ScheduleDelta()
+	assert.Equal(t, 9*time.Minute, delta)
+
+	// Running ahead of schedule gives a negative delta.
+	setMatch(arena.Database, &matches[1], currentTime.Add(5*time.Minute), currentTime.Add(-time.Minute), false)
+	arena.MatchState = AutoPeriod
+	delta, ok = arena.ScheduleDelta()
+	assert.True(t, ok)
+	assert.Equal(t, -6*time.Minute, delta)
+}
+
 func setMatch(database *model.Database, match *model.Match, matchTime time.Time, startedAt time.Time, isComplete bool) {
 	match.Time = matchTime
 	match.StartedAt = startedAt
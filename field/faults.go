@@ -0,0 +1,133 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Central list of the fault conditions detected by the arena, for display to and acknowledgement by the FTA.
+
+package field
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Battery voltage below which a linked robot is considered to be browning out.
+const brownoutVoltage = 6.8
+
+type FaultSeverity int
+
+const (
+	// Condition that clears itself once it is resolved.
+	TransientFault FaultSeverity = iota
+	// Condition that stopped the field and stays listed until cleared by the FTA.
+	CriticalFault
+)
+
+type FaultKind string
+
+const (
+	DsDisconnectedFault       FaultKind = "dsDisconnected"
+	RobotLinkLostFault        FaultKind = "robotLinkLost"
+	BrownoutFault             FaultKind = "brownout"
+	EnabledWhenDisabledFault  FaultKind = "enabledWhenDisabled"
+	LoopOverrunFault          FaultKind = "loopOverrun"
+	TotalRobotLossFault       FaultKind = "totalRobotLoss"
+	DsNetworkUnavailableFault FaultKind = "dsNetworkUnavailable"
+)
+
+// A fault condition and when it was first detected. Station is blank for faults affecting the whole field.
+type Fault struct {
+	Kind        FaultKind
+	Severity    FaultSeverity
+	Station     string
+	Description string
+	Time        time.Time
+}
+
+// Returns the currently listed faults, oldest first.
+func (arena *Arena) ActiveFaults() []Fault {
+	arena.faultsMutex.Lock()
+	defer arena.faultsMutex.Unlock()
+	faults := make([]Fault, len(arena.faults))
+	copy(faults, arena.faults)
+	sort.SliceStable(faults, func(i, j int) bool { return faults[i].Time.Before(faults[j].Time) })
+	return faults
+}
+
+// Acknowledges and removes every listed fault. Transient faults whose condition persists are listed again the next
+// time the stations are checked.
+func (arena *Arena) ClearFaults() {
+	arena.faultsMutex.Lock()
+	arena.faults = nil
+	arena.faultsMutex.Unlock()
+}
+
+// Lists a fault of the given kind for the given station, unless one is already listed.
+func (arena *Arena) raiseFault(kind FaultKind, severity FaultSeverity, station, format string, args ...interface{}) {
+	arena.faultsMutex.Lock()
+	defer arena.faultsMutex.Unlock()
+	if arena.findFault(kind, station) >= 0 {
+		return
+	}
+	arena.faults = append(
+		arena.faults, Fault{kind, severity, station, fmt.Sprintf(format, args...), arena.now()},
+	)
+}
+
+// Removes the listed transient fault of the given kind for the given station, if there is one.
+func (arena *Arena) resolveFault(kind FaultKind, station string) {
+	arena.faultsMutex.Lock()
+	defer arena.faultsMutex.Unlock()
+	if i := arena.findFault(kind, station); i >= 0 && arena.faults[i].Severity == TransientFault {
+		arena.faults = append(arena.faults[:i], arena.faults[i+1:]...)
+	}
+}
+
+// Returns the index of the listed fault of the given kind for the given station, or -1 if there is none. Must be
+// called with faultsMutex held.
+func (arena *Arena) findFault(kind FaultKind, station string) int {
+	for i, fault := range arena.faults {
+		if fault.Kind == kind && fault.Station == station {
+			return i
+		}
+	}
+	return -1
+}
+
+// Raises or resolves the transient faults of each station according to its current driver station status. Must be
+// called with allianceStationsMutex held, since it reads the driver station connections.
+func (arena *Arena) updateTransientFaults() {
+	for _, station := range arena.controlledStationKeys() {
+		allianceStation := arena.AllianceStations[station]
+		dsConn := allianceStation.DsConn
+		if allianceStation.Team == nil || allianceStation.Bypass || dsConn == nil {
+			arena.resolveFault(DsDisconnectedFault, station)
+			arena.resolveFault(RobotLinkLostFault, station)
+			arena.resolveFault(BrownoutFault, station)
+			continue
+		}
+		teamId := allianceStation.Team.Id
+
+		if !dsConn.DsLinked && !dsConn.lastPacketTime.IsZero() {
+			arena.raiseFault(
+				DsDisconnectedFault, TransientFault, station, "Driver station for team %d has lost its link.", teamId,
+			)
+		} else {
+			arena.resolveFault(DsDisconnectedFault, station)
+		}
+		if !dsConn.RobotLinked && !allianceStation.LastDroppedAt.IsZero() {
+			arena.raiseFault(
+				RobotLinkLostFault, TransientFault, station, "Robot for team %d has lost its link.", teamId,
+			)
+		} else {
+			arena.resolveFault(RobotLinkLostFault, station)
+		}
+		if dsConn.RobotLinked && dsConn.BatteryVoltage > 0 && dsConn.BatteryVoltage < brownoutVoltage {
+			arena.raiseFault(
+				BrownoutFault, TransientFault, station, "Robot for team %d is browning out at %.1f V.", teamId,
+				dsConn.BatteryVoltage,
+			)
+		} else {
+			arena.resolveFault(BrownoutFault, station)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTransientFaults(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	arena.AllianceStations["R1"].Team = &model.Team{Id: 254}
+	dsConn := &DriverStationConnection{
+		DsLinked: true, RobotLinked: true, BatteryVoltage: 12.5, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["R1"].DsConn = dsConn
+	arena.sendDsPacket(false, false)
+	assert.Empty(t, arena.ActiveFaults())
+
+	// Faults should accumulate as conditions arise, oldest first.
+	dsConn.BatteryVoltage = 6.2
+	arena.sendDsPacket(false, false)
+	currentTime = currentTime.Add(time.Second)
+	dsConn.RobotLinked = false
+	dsConn.lastPacketTime = time.Now()
+	arena.sendDsPacket(false, false)
+	faults := arena.ActiveFaults()
+	if assert.Equal(t, 1, len(faults)) {
+		assert.Equal(t, RobotLinkLostFault, faults[0].Kind)
+	}
+	dsConn.RobotLinked = true
+	dsConn.lastPacketTime = time.Now()
+	arena.sendDsPacket(false, false)
+	dsConn.RobotLinked = false
+	dsConn.lastPacketTime = time.Now()
+	arena.sendDsPacket(false, false)
+	dsConn.lastPacketTime = time.Now().Add(-time.Minute)
+	arena.sendDsPacket(false, false)
+	faults = arena.ActiveFaults()
+	if assert.Equal(t, 2, len(faults)) {
+		assert.Equal(t, RobotLinkLostFault, faults[0].Kind)
+		assert.Equal(t, DsDisconnectedFault, faults[1].Kind)
+		assert.Equal(t, TransientFault, faults[1].Severity)
+		assert.Equal(t, "R1", faults[1].Station)
+		assert.Equal(t, "Driver station for team 254 has lost its link.", faults[1].Description)
+		assert.Equal(t, currentTime, faults[1].Time)
+	}
+
+	// Transient faults should clear themselves once resolved.
+	dsConn.DsLinked = true
+	dsConn.RobotLinked = true
+	dsConn.BatteryVoltage = 6.2
+	dsConn.lastPacketTime = time.Now()
+	arena.sendDsPacket(false, false)
+	faults = arena.ActiveFaults()
+	if assert.Equal(t, 1, len(faults)) {
+		assert.Equal(t, BrownoutFault, faults[0].Kind)
+		assert.Equal(t, "Robot for team 254 is browning out at 6.2 V.", faults[0].Description)
+	}
+
+	// Clearing a transient fault whose condition persists only lists it again.
+	arena.ClearFaults()
+	assert.Empty(t, arena.ActiveFaults())
+	arena.sendDsPacket(false, false)
+	assert.Equal(t, 1, len(arena.ActiveFaults()))
+
+	// Bypassing the station resolves its faults.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.sendDsPacket(false, false)
+	assert.Empty(t, arena.ActiveFaults())
+}
+
+func TestCriticalFaults(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.MatchState = TeleopPeriod
+	loopTime := time.Now()
+	for i := 0; i < 20; i++ {
+		loopTime = loopTime.Add(150 * time.Millisecond)
+		arena.checkLoopOverrun(loopTime)
+	}
+	assert.Equal(t, PostMatch, arena.MatchState)
+	faults := arena.ActiveFaults()
+	if assert.Equal(t, 1, len(faults)) {
+		assert.Equal(t, LoopOverrunFault, faults[0].Kind)
+		assert.Equal(t, CriticalFault, faults[0].Severity)
+		assert.Equal(t, "", faults[0].Station)
+	}
+
+	// Critical faults stay listed after the condition is gone, until they are cleared.
+	arena.sendDsPacket(false, false)
+	arena.resolveFault(LoopOverrunFault, "")
+	assert.Equal(t, 1, len(arena.ActiveFaults()))
+
+	dsConn := &DriverStationConnection{
+		DsLinked: true, RobotLinked: true, RobotEnabled: true, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["B2"].DsConn = dsConn
+	arena.sendDsPacket(false, false)
+	faults = arena.ActiveFaults()
+	if assert.Equal(t, 2, len(faults)) {
+		assert.Equal(t, EnabledWhenDisabledFault, faults[1].Kind)
+		assert.Equal(t, CriticalFault, faults[1].Severity)
+		assert.Equal(t, "B2", faults[1].Station)
+	}
+	dsConn.RobotEnabled = false
+	arena.sendDsPacket(false, false)
+	assert.Equal(t, 2, len(arena.ActiveFaults()))
+
+	arena.ClearFaults()
+	assert.Empty(t, arena.ActiveFaults())
+	arena.sendDsPacket(false, false)
+	assert.Empty(t, arena.ActiveFaults())
+}
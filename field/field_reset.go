@@ -0,0 +1,31 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Optional gate requiring the FTA to confirm that the physical field has been reset before the next match starts.
+
+package field
+
+import "log"
+
+// Records that the field has been reset for the next match, which is required before it can start if the event
+// settings call for it. The confirmation is cleared whenever a match ends.
+func (arena *Arena) ConfirmFieldReset() error {
+	if arena.MatchInProgress() {
+		return newArenaError(InvalidStateError, "Cannot confirm the field reset while a match is in progress.")
+	}
+	if !arena.fieldResetConfirmed {
+		log.Println("Field reset confirmed.")
+	}
+	arena.fieldResetConfirmed = true
+	arena.notifyStatusChanged()
+	return nil
+}
+
+// Returns an error if the event requires the field reset to be confirmed and it hasn't been since the last match.
+// Test matches don't require it.
+func (arena *Arena) checkFieldResetConfirmed() error {
+	if arena.EventSettings.RequireFieldResetConfirm && !arena.fieldResetConfirmed &&
+		arena.CurrentMatch.Type != "test" {
+		return newArenaError(NotReadyError, "Cannot start match until the field reset has been confirmed.")
+	}
+	return nil
+}
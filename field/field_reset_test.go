@@ -0,0 +1,53 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfirmFieldReset(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	bypassAll := func() {
+		for _, allianceStation := range arena.AllianceStations {
+			allianceStation.Bypass = true
+		}
+	}
+	match := model.Match{Type: "practice", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+
+	// The confirmation isn't needed unless the event settings require it.
+	assert.Nil(t, arena.checkCanStartMatch())
+	arena.EventSettings.RequireFieldResetConfirm = true
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		assert.Equal(t, "Cannot start match until the field reset has been confirmed.", err.Error())
+	}
+
+	// Test matches don't require it.
+	assert.Nil(t, arena.LoadTestMatch())
+	bypassAll()
+	assert.Nil(t, arena.checkCanStartMatch())
+
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+	assert.Nil(t, arena.ConfirmFieldReset())
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.True(t, IsArenaErrorCode(arena.ConfirmFieldReset(), InvalidStateError))
+
+	// Ending the match clears the confirmation, including across loading the next match.
+	assert.Nil(t, arena.AbortMatch())
+	assert.Nil(t, arena.ForceReset())
+	assert.Nil(t, arena.LoadMatch(&match))
+	bypassAll()
+	assert.NotNil(t, arena.StartMatch())
+	assert.Nil(t, arena.ConfirmFieldReset())
+	assert.Nil(t, arena.StartMatch())
+}
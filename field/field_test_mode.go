@@ -0,0 +1,54 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Functions for the pre-event field test mode, in which disabled packets are sent to all connected robots to verify
+// their links without running a match.
+
+package field
+
+// Enters field test mode. Only allowed while no match is under way.
+func (arena *Arena) StartFieldTest() error {
+	if arena.MatchState != PreMatch {
+		return newArenaError(InvalidStateError, "Cannot start field test while there is a match in progress.")
+	}
+	arena.FieldTestMode = true
+	arena.fieldTestLinkedStations = make(map[string]bool)
+	for station := range arena.AllianceStations {
+		arena.fieldTestLinkedStations[station] = false
+	}
+	arena.ArenaStatusNotifier.Notify()
+	return nil
+}
+
+// Loads the next match and immediately enters field test mode for it, so that the links of all six robots can be
+// confirmed before the match is queued. Calling StopFieldTest afterwards leaves the match loaded and ready to start.
+func (arena *Arena) LoadNextMatchAndTest() error {
+	if err := arena.LoadNextMatch(); err != nil {
+		return err
+	}
+	return arena.StartFieldTest()
+}
+
+// Exits field test mode, returning the arena to its normal pre-match behavior.
+func (arena *Arena) StopFieldTest() {
+	arena.FieldTestMode = false
+	arena.ArenaStatusNotifier.Notify()
+}
+
+// Returns, for each alliance station, whether its robot has been linked at any point during the current or most
+// recent field test.
+func (arena *Arena) FieldTestResults() map[string]bool {
+	results := make(map[string]bool, len(arena.fieldTestLinkedStations))
+	for station, linked := range arena.fieldTestLinkedStations {
+		results[station] = linked
+	}
+	return results
+}
+
+// Records the link status of each station; called after each packet sent during the field test.
+func (arena *Arena) updateFieldTestResults() {
+	for station, allianceStation := range arena.AllianceStations {
+		if allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked {
+			arena.fieldTestLinkedStations[station] = true
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFieldTestMode(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "test", Red1: 254, Blue2: 1114}))
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254}
+	arena.AllianceStations["B2"].DsConn = &DriverStationConnection{TeamId: 1114}
+
+	assert.Nil(t, arena.StartFieldTest())
+	assert.True(t, arena.FieldTestMode)
+	assert.Equal(t, 6, len(arena.FieldTestResults()))
+
+	// Check that disabled packets are sent and that links are collected.
+	arena.AllianceStations["R1"].DsConn.lastPacketTime = time.Now()
+	arena.AllianceStations["R1"].DsConn.RobotLinked = true
+	arena.AllianceStations["R1"].DsConn.RobotCodeRunning = true
+	arena.lastDsPacketTime = time.Unix(0, 0) // Force a DS packet.
+	arena.Update()
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Equal(t, false, arena.AllianceStations["R1"].DsConn.Enabled)
+	assert.Equal(t, false, arena.AllianceStations["R1"].DsConn.Auto)
+	assert.Equal(t, 0.0, arena.MatchTimeSec())
+	results := arena.FieldTestResults()
+	assert.True(t, results["R1"])
+	assert.False(t, results["B2"])
+	assert.False(t, results["R2"])
+
+	// Check that a match can't be started during the field test.
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "field test mode is active")
+	}
+
+	// Check that the results remain available after exiting and that a match can then be started.
+	arena.StopFieldTest()
+	assert.False(t, arena.FieldTestMode)
+	assert.True(t, arena.FieldTestResults()["R1"])
+	assert.Nil(t, arena.StartMatch())
+
+	// Check that the field test can't be started during a match.
+	arena.Update()
+	err = arena.StartFieldTest()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Cannot start field test while")
+	}
+	assert.False(t, arena.FieldTestMode)
+}
+
+func TestLoadNextMatchAndTest(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	match := model.Match{Type: "practice", DisplayName: "1", Red1: 254}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+
+	assert.Nil(t, arena.LoadNextMatchAndTest())
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	assert.True(t, arena.FieldTestMode)
+	assert.Equal(t, 254, arena.AllianceStations["R1"].Team.Id)
+
+	// Stopping the test should leave the match loaded and startable.
+	arena.StopFieldTest()
+	assert.False(t, arena.FieldTestMode)
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	arena.AllianceStations["R1"].Bypass = false
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, RobotLinked: true, RobotCodeRunning: true}
+	assert.Nil(t, arena.StartMatch())
+
+	// Check that nothing is loaded or tested while a match is in progress.
+	err := arena.LoadNextMatchAndTest()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+	assert.False(t, arena.FieldTestMode)
+}
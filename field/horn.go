@@ -0,0 +1,19 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Hook for driving an external field horn from the match flow.
+
+package field
+
+// Events passed to the OnHorn callback.
+const (
+	HornMatchStart = "start"
+	HornMatchEnd   = "end"
+	HornMatchAbort = "abort"
+)
+
+// Invokes the OnHorn callback with the given event, if one is set.
+func (arena *Arena) soundHorn(event string) {
+	if arena.OnHorn != nil {
+		arena.OnHorn(event)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnHorn(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	var events []string
+	arena.OnHorn = func(event string) { events = append(events, event) }
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	startMatch := func() {
+		for _, station := range stationKeys {
+			arena.AllianceStations[station].Bypass = true
+		}
+		assert.Nil(t, arena.StartMatch())
+		arena.Update()
+	}
+
+	// A match run to completion sounds the start and end horns once each.
+	startMatch()
+	assert.Equal(t, []string{HornMatchStart}, events)
+	for arena.MatchState != PostMatch {
+		currentTime = currentTime.Add(100 * time.Millisecond)
+		arena.Update()
+	}
+	for i := 0; i < 10; i++ {
+		currentTime = currentTime.Add(100 * time.Millisecond)
+		arena.Update()
+	}
+	assert.Equal(t, []string{HornMatchStart, HornMatchEnd}, events)
+
+	// An aborted match sounds the abort horn instead of the end horn.
+	events = nil
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "test"}))
+	startMatch()
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec+1) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.Nil(t, arena.AbortMatch())
+	currentTime = currentTime.Add(arena.MatchTiming.GetDurationToTeleopEnd())
+	arena.Update()
+	assert.Equal(t, []string{HornMatchStart, HornMatchAbort}, events)
+
+	// Timeouts don't sound the horn.
+	events = nil
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.StartTimeout(1))
+	arena.Update()
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Empty(t, events)
+}
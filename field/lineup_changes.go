@@ -0,0 +1,61 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Detection of differences between the loaded lineup and the one originally scheduled, so that scorekeepers notice
+// substitutions that were made and forgotten.
+
+package field
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// A station whose team differs from the one scheduled for it. A team ID of zero means the station is empty.
+type LineupChange struct {
+	Station         string
+	ScheduledTeamId int
+	TeamId          int
+}
+
+// Records the current lineup as the scheduled one, unless it has already been recorded. Must be called before the
+// first substitution changes the lineup.
+func (arena *Arena) recordScheduledLineup() {
+	match := arena.CurrentMatch
+	if match.Type == "test" || match.ScheduledTeams != nil {
+		return
+	}
+	for _, station := range stationKeys {
+		match.ScheduledTeams = append(match.ScheduledTeams, *stationMatchTeamFields[station](match))
+	}
+}
+
+// Compares the lineup of the current match against its scheduled lineup and logs a warning if they differ.
+func (arena *Arena) checkLineupChanges() {
+	arena.lineupChanges = nil
+	match := arena.CurrentMatch
+	if len(match.ScheduledTeams) != len(stationKeys) {
+		return
+	}
+	var descriptions []string
+	for i, station := range stationKeys {
+		teamId := *stationMatchTeamFields[station](match)
+		if teamId != match.ScheduledTeams[i] {
+			arena.lineupChanges = append(arena.lineupChanges, LineupChange{station, match.ScheduledTeams[i], teamId})
+			descriptions = append(descriptions, fmt.Sprintf("%s %d -> %d", station, match.ScheduledTeams[i], teamId))
+		}
+	}
+	if len(descriptions) > 0 {
+		log.Printf(
+			"WARNING: Lineup of match %s differs from its schedule: %s.", match.DisplayName,
+			strings.Join(descriptions, ", "),
+		)
+	}
+}
+
+// Returns the stations of the current match whose team differs from the scheduled one, in station order.
+func (arena *Arena) LineupChanges() []LineupChange {
+	lineupChanges := make([]LineupChange, len(arena.lineupChanges))
+	copy(lineupChanges, arena.lineupChanges)
+	return lineupChanges
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLineupChanges(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106, 107} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	match := model.Match{Type: "practice", DisplayName: "1", Red1: 101, Red2: 102, Red3: 103, Blue1: 104,
+		Blue2: 105, Blue3: 106}
+	arena.Database.CreateMatch(&match)
+	otherMatch := model.Match{Type: "practice", DisplayName: "2", Red1: 106, Red2: 105, Red3: 104, Blue1: 103,
+		Blue2: 102, Blue3: 101}
+	arena.Database.CreateMatch(&otherMatch)
+
+	// An unmodified lineup produces no warning.
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Empty(t, arena.LineupChanges())
+
+	assert.Nil(t, arena.SubstituteTeam(107, "R2"))
+	assert.Nil(t, arena.SubstituteTeam(0, "B3"))
+	assert.Equal(t, []LineupChange{{"R2", 102, 107}, {"B3", 106, 0}}, arena.LineupChanges())
+
+	// The scheduled lineup is persisted so that the changes are still flagged when the match is reloaded.
+	reloadedMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, []int{101, 102, 103, 104, 105, 106}, reloadedMatch.ScheduledTeams)
+	assert.Nil(t, arena.LoadMatch(&otherMatch))
+	assert.Empty(t, arena.LineupChanges())
+	assert.Nil(t, arena.LoadMatch(reloadedMatch))
+	assert.Equal(t, 2, len(arena.LineupChanges()))
+	getStatus := func() map[string]interface{} {
+		var status map[string]interface{}
+		data, _ := json.Marshal(arena.generateArenaStatusMessage())
+		assert.Nil(t, json.Unmarshal(data, &status))
+		return status
+	}
+	assert.Equal(t, 2, len(getStatus()["LineupChanges"].([]interface{})))
+
+	// Reverting the substitutions clears the warning.
+	assert.Nil(t, arena.SubstituteTeam(102, "R2"))
+	assert.Nil(t, arena.SubstituteTeam(106, "B3"))
+	assert.Empty(t, arena.LineupChanges())
+	assert.Empty(t, getStatus()["LineupChanges"])
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Safety net that stops the field if the arena loop falls persistently behind during a match, since the robots would
+// otherwise keep acting on stale commands.
+
+package field
+
+import (
+	"log"
+	"time"
+)
+
+type loopOverrunMonitor struct {
+	lastLoopTime time.Time
+	overrunSince time.Time
+}
+
+// Records an iteration of the arena loop at the given time and, if every interval between iterations has exceeded the
+// configured threshold for at least the configured window during a match, emergency stops the field.
+func (arena *Arena) checkLoopOverrun(now time.Time) {
+	monitor := &arena.loopOverrunMonitor
+	lastLoopTime := monitor.lastLoopTime
+	monitor.lastLoopTime = now
+	thresholdMs := arena.EventSettings.LoopOverrunEstopThresholdMs
+	if thresholdMs <= 0 || lastLoopTime.IsZero() || !arena.MatchInProgress() ||
+		now.Sub(lastLoopTime).Milliseconds() <= int64(thresholdMs) {
+		monitor.overrunSince = time.Time{}
+		return
+	}
+
+	if monitor.overrunSince.IsZero() {
+		monitor.overrunSince = lastLoopTime
+	}
+	if now.Sub(monitor.overrunSince).Milliseconds() < int64(arena.EventSettings.LoopOverrunEstopWindowMs) {
+		return
+	}
+	log.Printf(
+		"CRITICAL: Arena loop intervals have exceeded %d ms for the last %d ms; stopping the field.", thresholdMs,
+		now.Sub(monitor.overrunSince).Milliseconds(),
+	)
+	arena.raiseFault(
+		LoopOverrunFault, CriticalFault, "", "Arena loop intervals exceeded %d ms for %d ms; the field was stopped.",
+		thresholdMs, now.Sub(monitor.overrunSince).Milliseconds(),
+	)
+	monitor.overrunSince = time.Time{}
+	arena.allianceStationsMutex.Lock()
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Estop = true
+	}
+	arena.allianceStationsMutex.Unlock()
+	arena.markStatusChanged()
+	arena.AbortMatch()
+}
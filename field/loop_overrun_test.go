@@ -0,0 +1,53 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCheckLoopOverrun(t *testing.T) {
+	arena := setupTestArena(t)
+	loopTime := time.Now()
+	step := func(intervalMs int) {
+		loopTime = loopTime.Add(time.Duration(intervalMs) * time.Millisecond)
+		arena.checkLoopOverrun(loopTime)
+	}
+
+	// Slow iterations outside of a match should be ignored.
+	for i := 0; i < 30; i++ {
+		step(150)
+	}
+	assert.Equal(t, PreMatch, arena.MatchState)
+
+	// A single normal iteration should restart the window.
+	arena.MatchState = TeleopPeriod
+	for i := 0; i < 13; i++ {
+		step(150)
+	}
+	step(10)
+	for i := 0; i < 13; i++ {
+		step(150)
+	}
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.False(t, arena.AllianceStations["R1"].Estop)
+
+	// Overruns sustained for the whole window should stop the field.
+	step(150)
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.True(t, arena.matchAborted)
+	for _, allianceStation := range arena.AllianceStations {
+		assert.True(t, allianceStation.Estop)
+	}
+
+	// The check should be skipped entirely when disabled.
+	arena.EventSettings.LoopOverrunEstopThresholdMs = 0
+	arena.MatchState = AutoPeriod
+	arena.matchAborted = false
+	for i := 0; i < 30; i++ {
+		step(500)
+	}
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+}
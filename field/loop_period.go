@@ -0,0 +1,57 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Runtime control of how often the arena loop runs.
+
+package field
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Bounds on the arena loop period. Shorter periods reduce the latency of the match flow at the cost of CPU time, while
+// the longest must still leave the loop several chances to send each periodic driver station packet on time.
+const (
+	minArenaLoopPeriodMs = 1
+	maxArenaLoopPeriodMs = dsPacketPeriodMs / 5
+)
+
+// Returns the number of milliseconds the arena loop sleeps between iterations.
+func (arena *Arena) LoopPeriod() int {
+	return int(atomic.LoadInt32(&arena.loopPeriodMs))
+}
+
+// Sets the number of milliseconds the arena loop sleeps between iterations, taking effect from the next iteration.
+func (arena *Arena) SetLoopPeriod(ms int) error {
+	if ms < minArenaLoopPeriodMs || ms > maxArenaLoopPeriodMs {
+		return newArenaError(
+			InvalidDurationError, "Loop period must be between %d and %d ms.", minArenaLoopPeriodMs,
+			maxArenaLoopPeriodMs,
+		)
+	}
+	atomic.StoreInt32(&arena.loopPeriodMs, int32(ms))
+	log.Printf("Arena loop period set to %d ms.", ms)
+	return nil
+}
+
+// Runs the arena loop until the given channel is closed, or forever if it is nil. Returns the number of iterations
+// run.
+func (arena *Arena) runLoop(done <-chan struct{}) int {
+	iterations := 0
+	for {
+		arena.checkLoopOverrun(time.Now())
+		arena.Update()
+		if time.Since(arena.lastPeriodicTaskTime).Seconds() >= periodicTaskPeriodSec {
+			arena.lastPeriodicTaskTime = time.Now()
+			go arena.runPeriodicTasks()
+		}
+		iterations++
+
+		select {
+		case <-done:
+			return iterations
+		case <-time.After(time.Duration(arena.LoopPeriod()) * time.Millisecond):
+		}
+	}
+}
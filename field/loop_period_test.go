@@ -0,0 +1,42 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetLoopPeriod(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Equal(t, defaultArenaLoopPeriodMs, arena.LoopPeriod())
+
+	assert.Nil(t, arena.SetLoopPeriod(25))
+	assert.Equal(t, 25, arena.LoopPeriod())
+	for _, ms := range []int{0, -10, maxArenaLoopPeriodMs + 1} {
+		err := arena.SetLoopPeriod(ms)
+		if assert.NotNil(t, err) {
+			assert.True(t, IsArenaErrorCode(err, InvalidDurationError))
+			assert.Equal(t, "Loop period must be between 1 and 50 ms.", err.Error())
+		}
+	}
+	assert.Equal(t, 25, arena.LoopPeriod())
+}
+
+func TestLoopPeriodCadence(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.lastPeriodicTaskTime = time.Now()
+	runFor := func(duration time.Duration) int {
+		done := make(chan struct{})
+		time.AfterFunc(duration, func() { close(done) })
+		return arena.runLoop(done)
+	}
+
+	assert.Nil(t, arena.SetLoopPeriod(maxArenaLoopPeriodMs))
+	slowIterations := runFor(300 * time.Millisecond)
+	assert.Nil(t, arena.SetLoopPeriod(minArenaLoopPeriodMs))
+	fastIterations := runFor(300 * time.Millisecond)
+	assert.LessOrEqual(t, slowIterations, 8)
+	assert.Greater(t, fastIterations, 4*slowIterations)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Manual scoring mode, in which the result of a match is only decided once the scorekeeper finalizes the scores.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"log"
+)
+
+// Returns true if manual scoring is turned on in the event settings. While it is on, the scores of a match stay open
+// for editing after it ends and its results can't be committed until FinalizeScores has been called.
+func (arena *Arena) ManualScoring() bool {
+	return arena.EventSettings.ManualScoring
+}
+
+// Locks the scores of the match that has just ended and decides its winner from them, allowing its results to be
+// committed. Only used in manual scoring mode.
+func (arena *Arena) FinalizeScores() error {
+	if !arena.ManualScoring() {
+		return newArenaError(InvalidStateError, "Scores are only finalized in manual scoring mode.")
+	}
+	if arena.MatchState != PostMatch {
+		return newArenaError(InvalidStateError, "Cannot finalize the scores until the match has ended.")
+	}
+
+	arena.scoreMutex.Lock()
+	if arena.scoresFinalized {
+		arena.scoreMutex.Unlock()
+		return newArenaError(InvalidStateError, "The scores of match %s have already been finalized.",
+			arena.CurrentMatch.DisplayName)
+	}
+	finalizedStatus := arena.winner()
+	arena.finalizedStatus = finalizedStatus
+	arena.scoresFinalized = true
+	arena.scoreMutex.Unlock()
+	log.Printf("Scores of match %s finalized with result %q.", arena.CurrentMatch.DisplayName, finalizedStatus)
+	arena.RealtimeScoreNotifier.Notify()
+	return nil
+}
+
+// Returns the result decided when the scores of the current match were finalized, or false if they haven't been.
+func (arena *Arena) FinalizedStatus() (game.MatchStatus, bool) {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
+	return arena.finalizedStatus, arena.scoresFinalized
+}
+
+// Returns an error if the scores of the current match have been finalized and can no longer be edited.
+func (arena *Arena) CheckScoresEditable() error {
+	arena.scoreMutex.Lock()
+	defer arena.scoreMutex.Unlock()
+	return arena.checkScoresEditable()
+}
+
+// Returns an error if the results of the current match can't be committed yet because they are awaiting
+// finalization.
+func (arena *Arena) CheckCanCommitResults() error {
+	if _, finalized := arena.FinalizedStatus(); arena.ManualScoring() && !finalized {
+		return newArenaError(InvalidStateError, "The scores of match %s must be finalized before they are committed.",
+			arena.CurrentMatch.DisplayName)
+	}
+	return nil
+}
+
+// Must be called with scoreMutex held.
+func (arena *Arena) checkScoresEditable() error {
+	if arena.scoresFinalized {
+		return newArenaError(InvalidStateError, "The scores of match %s have been finalized.",
+			arena.CurrentMatch.DisplayName)
+	}
+	return nil
+}
+
+// Reopens the scores for a new match. Must be called with scoreMutex held.
+func (arena *Arena) resetFinalizedScores() {
+	arena.scoresFinalized = false
+	arena.finalizedStatus = game.MatchNotPlayed
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestManualScoring(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.False(t, arena.ManualScoring())
+	assert.Nil(t, arena.CheckCanCommitResults())
+	err := arena.FinalizeScores()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Scores are only finalized in manual scoring mode.", err.Error())
+	}
+
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "practice", DisplayName: "P1"}))
+	arena.EventSettings.ManualScoring = true
+	assert.True(t, arena.ManualScoring())
+	err = arena.FinalizeScores()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+		assert.Equal(t, "Cannot finalize the scores until the match has ended.", err.Error())
+	}
+
+	// The result shouldn't be decided when the match ends.
+	arena.MatchState = TeleopPeriod
+	assert.Nil(t, arena.AddRedScore("teleop", 10))
+	arena.endMatch()
+	_, finalized := arena.FinalizedStatus()
+	assert.False(t, finalized)
+	err = arena.CheckCanCommitResults()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The scores of match P1 must be finalized before they are committed.", err.Error())
+	}
+
+	// The scorekeeper can keep editing the scores until they are finalized.
+	assert.Nil(t, arena.CheckScoresEditable())
+	assert.Nil(t, arena.AddBlueScore("teleop", 15))
+	assert.Nil(t, arena.FinalizeScores())
+	status, finalized := arena.FinalizedStatus()
+	assert.True(t, finalized)
+	assert.Equal(t, game.BlueWonMatch, status)
+	assert.Nil(t, arena.CheckCanCommitResults())
+	assert.NotNil(t, arena.CheckScoresEditable())
+	assert.NotNil(t, arena.AddRedScore("teleop", 10))
+	assert.Equal(t, 10, arena.RedScore.TeleopPoints)
+	assert.NotNil(t, arena.FinalizeScores())
+
+	// Loading the next match reopens the scores.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "practice", DisplayName: "P2"}))
+	_, finalized = arena.FinalizedStatus()
+	assert.False(t, finalized)
+	assert.Nil(t, arena.AddRedScore("teleop", 10))
+	assert.NotNil(t, arena.CheckCanCommitResults())
+	arena.EventSettings.ManualScoring = false
+	assert.Nil(t, arena.CheckCanCommitResults())
+}
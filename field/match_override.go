@@ -0,0 +1,65 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Head referee override of a committed match result, e.g. following a post-match review.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/tournament"
+	"log"
+	"strings"
+)
+
+// Passed as the winner to OverrideMatchResult to record the match as a tie.
+const NoWinner = -1
+
+// Records that the head referee has overridden the result of the given completed match, making the given alliance
+// (RedAlliance, BlueAlliance or NoWinner) the winner for the given reason. The result computed from the scores is
+// kept on the match for audit, and the rankings are recalculated to reflect the override. Playoff results can't be
+// overridden since the bracket advances from the computed result.
+func (arena *Arena) OverrideMatchResult(matchId int, winner int, reason string) error {
+	match, err := arena.Datastore.GetMatchById(matchId)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		return newArenaError(MatchNotFoundError, "No match with ID %d exists.", matchId)
+	}
+	if !match.IsComplete() {
+		return newArenaError(InvalidStateError, "Cannot override the result of match %s before it has been played.",
+			match.DisplayName)
+	}
+	if match.ShouldUpdateEliminationMatches() {
+		return newArenaError(InvalidStateError, "Cannot override the result of playoff match %s.", match.DisplayName)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return newArenaError(InvalidOverrideError, "A reason is required to override a match result.")
+	}
+
+	switch winner {
+	case RedAlliance:
+		match.OverrideStatus = game.RedWonMatch
+	case BlueAlliance:
+		match.OverrideStatus = game.BlueWonMatch
+	case NoWinner:
+		match.OverrideStatus = game.TieMatch
+	default:
+		return newArenaError(InvalidOverrideError, "Invalid winner %d.", winner)
+	}
+	match.OverrideReason = reason
+	if err = arena.Datastore.UpdateMatch(match); err != nil {
+		return err
+	}
+	log.Printf("Result of match %s overridden from %q to %q: %s", match.DisplayName, match.Status,
+		match.OverrideStatus, reason)
+
+	rankingMatchTypes := arena.EventSettings.GetRankingMatchTypes()
+	if match.ShouldUpdateRankings(rankingMatchTypes) {
+		if _, err = tournament.CalculateRankings(arena.Datastore, rankingMatchTypes, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOverrideMatchResult(t *testing.T) {
+	arena := setupTestArena(t)
+
+	err := arena.OverrideMatchResult(12, RedAlliance, "Review")
+	assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6}
+	arena.Database.CreateMatch(&match)
+	err = arena.OverrideMatchResult(match.Id, RedAlliance, "Review")
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+
+	match.Status = game.BlueWonMatch
+	arena.Database.UpdateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedScore, matchResult.BlueScore = matchResult.BlueScore, matchResult.RedScore
+	arena.Database.CreateMatchResult(matchResult)
+	if err = arena.OverrideMatchResult(match.Id, RedAlliance, " "); assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidOverrideError))
+		assert.Equal(t, "A reason is required to override a match result.", err.Error())
+	}
+	if err = arena.OverrideMatchResult(match.Id, 2, "Review"); assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidOverrideError))
+		assert.Equal(t, "Invalid winner 2.", err.Error())
+	}
+
+	assert.Nil(t, arena.OverrideMatchResult(match.Id, RedAlliance, " Blue robot left the field early "))
+	overriddenMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.True(t, overriddenMatch.IsResultOverridden())
+	assert.Equal(t, game.RedWonMatch, overriddenMatch.OverrideStatus)
+	assert.Equal(t, "Blue robot left the field early", overriddenMatch.OverrideReason)
+	assert.Equal(t, game.BlueWonMatch, overriddenMatch.Status)
+	ranking, _ := arena.Database.GetRankingForTeam(1)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 1, ranking.Wins)
+		assert.Equal(t, 2, ranking.RankingPoints)
+	}
+	ranking, _ = arena.Database.GetRankingForTeam(4)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 1, ranking.Losses)
+	}
+	summary, err := arena.LoadResult(match.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, game.RedWonMatch, summary.Winner)
+
+	assert.Nil(t, arena.OverrideMatchResult(match.Id, NoWinner, "Field fault"))
+	overriddenMatch, _ = arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, game.TieMatch, overriddenMatch.OverrideStatus)
+	ranking, _ = arena.Database.GetRankingForTeam(4)
+	assert.Equal(t, 1, ranking.Ties)
+
+	playoffMatch := model.Match{Type: "elimination", DisplayName: "F-1", Status: game.RedWonMatch}
+	arena.Database.CreateMatch(&playoffMatch)
+	err = arena.OverrideMatchResult(playoffMatch.Id, BlueAlliance, "Review")
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+}
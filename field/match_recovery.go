@@ -0,0 +1,75 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Functions for persisting the progress of the current match and recovering it after a crash.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"log"
+)
+
+// Saves the current match state to the database so that it can be recovered if the process crashes, or clears it if
+// there is no longer a match in flight.
+func (arena *Arena) saveMatchProgress() {
+	var err error
+	if arena.CurrentMatch.Type == "test" || arena.MatchState == PreMatch || arena.MatchState == TimeoutActive ||
+		arena.MatchState == PostTimeout {
+		// Only scheduled matches can be reloaded after a restart.
+		err = arena.Datastore.ClearMatchProgress()
+	} else {
+		err = arena.Datastore.SaveMatchProgress(
+			&model.MatchProgress{
+				MatchId:        arena.CurrentMatch.Id,
+				MatchState:     int(arena.MatchState),
+				MatchStartTime: arena.MatchStartTime,
+				MatchAborted:   arena.matchAborted,
+			},
+		)
+	}
+	if err != nil {
+		log.Printf("Failed to save match progress: %v", err)
+	}
+}
+
+// Checks for a match that was in flight when the arena last stopped and, if there is one, reloads it. The match is
+// never resumed: if it was still being played, it is treated as aborted and left in the post-match state with the
+// robots disabled, so that the operator can decide whether to discard it and replay. A match that had already ended
+// is likewise restored to the post-match state so that its results can still be committed, and stays aborted if it
+// was.
+func (arena *Arena) RecoverInProgressMatch() error {
+	matchProgress, err := arena.Datastore.GetMatchProgress()
+	if err != nil {
+		return err
+	}
+	if matchProgress == nil {
+		return nil
+	}
+
+	match, err := arena.Datastore.GetMatchById(matchProgress.MatchId)
+	if err != nil {
+		return err
+	}
+	if match == nil || match.IsComplete() {
+		// The saved progress is stale; there is nothing to recover.
+		return arena.Datastore.ClearMatchProgress()
+	}
+	if err = arena.LoadMatch(match); err != nil {
+		return err
+	}
+
+	arena.MatchStartTime = matchProgress.MatchStartTime
+	arena.setMatchState(PostMatch)
+	if MatchState(matchProgress.MatchState) != PostMatch {
+		arena.matchAborted = true
+		log.Printf("Recovered match %s, which was interrupted while in progress; treating it as aborted.",
+			match.DisplayName)
+	} else if matchProgress.MatchAborted {
+		arena.matchAborted = true
+		log.Printf("Recovered match %s, which had been aborted and whose results were not committed.",
+			match.DisplayName)
+	} else {
+		log.Printf("Recovered match %s, which had ended but whose results were not committed.", match.DisplayName)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSaveMatchProgress(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Test matches aren't saved.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].Bypass = true
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	matchProgress, _ := arena.Database.GetMatchProgress()
+	assert.Nil(t, matchProgress)
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Nil(t, arena.ForceReset())
+
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	arena.Database.CreateMatch(&match)
+	assert.Nil(t, arena.LoadMatch(&match))
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["R2"].Bypass = true
+	arena.AllianceStations["R3"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	arena.AllianceStations["B2"].Bypass = true
+	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{RobotLinked: true, RobotCodeRunning: true}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	matchProgress, _ = arena.Database.GetMatchProgress()
+	if assert.NotNil(t, matchProgress) {
+		assert.Equal(t, match.Id, matchProgress.MatchId)
+		assert.Equal(t, int(WarmupPeriod), matchProgress.MatchState)
+		assert.True(t, arena.MatchStartTime.Equal(matchProgress.MatchStartTime))
+	}
+	arena.MatchStartTime = time.Now().Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	matchProgress, _ = arena.Database.GetMatchProgress()
+	if assert.NotNil(t, matchProgress) {
+		assert.Equal(t, int(AutoPeriod), matchProgress.MatchState)
+	}
+
+	// Check that the progress is cleared once the match is reset.
+	assert.Nil(t, arena.AbortMatch())
+	arena.Update()
+	assert.Nil(t, arena.ForceReset())
+	arena.Update()
+	matchProgress, _ = arena.Database.GetMatchProgress()
+	assert.Nil(t, matchProgress)
+}
+
+func TestRecoverInProgressMatch(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Check that nothing happens if there is nothing to recover.
+	assert.Nil(t, arena.RecoverInProgressMatch())
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Equal(t, "test", arena.CurrentMatch.Type)
+
+	// Check that a match interrupted while robots were enabled is never resumed.
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	match := model.Match{Type: "qualification", DisplayName: "12", Red1: 254}
+	arena.Database.CreateMatch(&match)
+	startTime := time.Now().Add(-20 * time.Second)
+	arena.Database.SaveMatchProgress(
+		&model.MatchProgress{MatchId: match.Id, MatchState: int(TeleopPeriod), MatchStartTime: startTime},
+	)
+	assert.Nil(t, arena.RecoverInProgressMatch())
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	assert.Equal(t, 254, arena.AllianceStations["R1"].Team.Id)
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.True(t, arena.matchAborted)
+	assert.True(t, startTime.Equal(arena.MatchStartTime))
+
+	// Check that the match stays aborted if the arena stops again after recovering it.
+	arena.Update()
+	matchProgress, _ := arena.Database.GetMatchProgress()
+	if assert.NotNil(t, matchProgress) {
+		assert.Equal(t, int(PostMatch), matchProgress.MatchState)
+		assert.True(t, matchProgress.MatchAborted)
+	}
+	arena.MatchState = PreMatch
+	arena.matchAborted = false
+	assert.Nil(t, arena.RecoverInProgressMatch())
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.True(t, arena.matchAborted)
+
+	// Check that a match that had already ended is restored without being marked as aborted.
+	arena = setupTestArena(t)
+	match = model.Match{Type: "qualification", DisplayName: "13"}
+	arena.Database.CreateMatch(&match)
+	arena.Database.SaveMatchProgress(
+		&model.MatchProgress{MatchId: match.Id, MatchState: int(PostMatch), MatchStartTime: startTime},
+	)
+	assert.Nil(t, arena.RecoverInProgressMatch())
+	assert.Equal(t, match.Id, arena.CurrentMatch.Id)
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.False(t, arena.matchAborted)
+
+	// Check that stale progress for a match that has since been committed is discarded.
+	arena = setupTestArena(t)
+	match = model.Match{Type: "qualification", DisplayName: "14", Status: game.RedWonMatch}
+	arena.Database.CreateMatch(&match)
+	arena.Database.SaveMatchProgress(
+		&model.MatchProgress{MatchId: match.Id, MatchState: int(AutoPeriod), MatchStartTime: startTime},
+	)
+	assert.Nil(t, arena.RecoverInProgressMatch())
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Equal(t, "test", arena.CurrentMatch.Type)
+	matchProgress, _ = arena.Database.GetMatchProgress()
+	assert.Nil(t, matchProgress)
+}
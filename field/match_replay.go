@@ -0,0 +1,252 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Offline replay of a recorded driver station packet trace through the match state machine, for validating the timing
+// engine against real recordings and for training new volunteers.
+
+package field
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Differences between the recorded and replayed robot commands this close to a replayed state transition are ignored,
+// since the match start time can only be inferred from the trace to within one arena loop.
+const replayTransitionToleranceMs = 50
+
+// A single entry in the timeline reconstructed from a packet trace. MatchTimeSec is measured from the inferred start
+// of the match and is negative for anything that happened before it.
+type ReplayEvent struct {
+	Time         time.Time
+	MatchTimeSec float64
+	Description  string
+}
+
+type packetTraceRecord struct {
+	time      time.Time
+	direction string
+	station   string
+	teamId    int
+	fields    map[string]string
+}
+
+func (event ReplayEvent) String() string {
+	return fmt.Sprintf("T%+8.3f  %s", event.MatchTimeSec, event.Description)
+}
+
+// Replays a packet trace written by SetPacketTrace through a separate, offline arena that uses the current event
+// settings and match timing, and returns the reconstructed timeline of the match: the state transitions, the driver
+// station and robot link changes and any robot commands that differ from the recorded ones. The match start is
+// inferred from the first packet enabling robots in autonomous, and replay stops once the match ends. Stops during
+// autonomous aren't recorded in the trace and so show up as differences. The live arena isn't affected.
+func (arena *Arena) ReplayPacketTrace(trace io.Reader) ([]ReplayEvent, error) {
+	records, err := parsePacketTrace(trace)
+	if err != nil {
+		return nil, err
+	}
+	var startTime time.Time
+	for _, record := range records {
+		if record.direction == "sent" && record.fields["auto"] == "true" && record.fields["enabled"] == "true" {
+			startTime = record.time.Add(-time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+			break
+		}
+	}
+	if startTime.IsZero() {
+		return nil, newArenaError(InvalidStateError, "The packet trace doesn't contain the start of a match.")
+	}
+
+	dbDir, err := os.MkdirTemp("", "cheesy-arena-replay")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dbDir)
+	replay, err := NewArena(filepath.Join(dbDir, "replay.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer replay.Database.Close()
+	// Keep the offline arena from touching the field network.
+	eventSettings := *arena.EventSettings
+	eventSettings.NetworkSecurityEnabled = false
+	replay.EventSettings = &eventSettings
+	replay.MatchTiming = arena.MatchTiming
+	replay.MatchSounds = game.NewMatchSounds(replay.MatchTiming)
+	currentTime := records[0].time
+	replay.now = func() time.Time { return currentTime }
+
+	// Stand up a simulated driver station for every station that appears in the trace and bypass the rest.
+	lastStatusTimes := make(map[string]time.Time)
+	replay.allianceStationsMutex.Lock()
+	for _, record := range records {
+		allianceStation, ok := replay.AllianceStations[record.station]
+		if !ok || allianceStation.DsConn != nil {
+			continue
+		}
+		allianceStation.Team = &model.Team{Id: record.teamId}
+		allianceStation.DsConn = &DriverStationConnection{TeamId: record.teamId, AllianceStation: record.station}
+	}
+	for _, allianceStation := range replay.AllianceStations {
+		allianceStation.Bypass = allianceStation.DsConn == nil
+	}
+	replay.allianceStationsMutex.Unlock()
+
+	var events, mismatches []ReplayEvent
+	addEvent := func(timeline *[]ReplayEvent, format string, args ...interface{}) {
+		*timeline = append(
+			*timeline,
+			ReplayEvent{currentTime, currentTime.Sub(startTime).Seconds(), fmt.Sprintf(format, args...)},
+		)
+	}
+	for _, record := range records {
+		if replay.MatchState == PreMatch && !record.time.Before(startTime) {
+			currentTime = startTime
+			replay.MatchState = StartMatch
+			addEvent(&events, "Match started")
+			replay.Update()
+			addEvent(&events, "Match entered %s", replay.MatchState)
+		}
+		currentTime = record.time
+		allianceStation := replay.AllianceStations[record.station]
+		if allianceStation == nil {
+			continue
+		}
+		dsConn := allianceStation.DsConn
+
+		// The simulated driver station updates the stations the same way the real ones do, under the mutex.
+		replay.allianceStationsMutex.Lock()
+		switch record.direction {
+		case "udpStatus":
+			if _, ok := lastStatusTimes[record.station]; !ok {
+				addEvent(&events, "Driver station for team %d connected in %s", dsConn.TeamId, record.station)
+			}
+			lastStatusTimes[record.station] = record.time
+			wasRobotLinked := dsConn.RobotLinked
+			dsConn.DsLinked = true
+			dsConn.RadioLinked = record.fields["radioLinked"] == "true"
+			dsConn.RobotLinked = record.fields["robotLinked"] == "true"
+			dsConn.RobotCodeRunning = record.fields["robotCodeRunning"] == "true"
+			dsConn.RobotEnabled = record.fields["robotEnabled"] == "true"
+			dsConn.BatteryVoltage, _ = strconv.ParseFloat(record.fields["batteryVoltage"], 64)
+			if dsConn.RobotLinked {
+				dsConn.lastRobotLinkedTime = time.Now()
+			}
+			if dsConn.RobotLinked != wasRobotLinked {
+				if dsConn.RobotLinked {
+					addEvent(&events, "Robot for team %d in %s linked", dsConn.TeamId, record.station)
+				} else {
+					addEvent(&events, "Robot for team %d in %s lost its link", dsConn.TeamId, record.station)
+				}
+			}
+		case "sent":
+			if record.fields["estop"] == "true" && !allianceStation.Estop {
+				allianceStation.Estop = true
+				addEvent(&events, "Team %d in %s was emergency stopped", dsConn.TeamId, record.station)
+			}
+		}
+
+		// The link timeout is measured against the real clock, so age the last status by its age in the trace.
+		for station, lastStatusTime := range lastStatusTimes {
+			replay.AllianceStations[station].DsConn.lastPacketTime = time.Now().Add(-currentTime.Sub(lastStatusTime))
+		}
+		replay.allianceStationsMutex.Unlock()
+
+		previousState := replay.MatchState
+		replay.lastDsPacketTime = time.Time{}
+		replay.Update()
+		if replay.MatchState != previousState {
+			addEvent(&events, "Match entered %s", replay.MatchState)
+		}
+
+		if record.direction == "sent" {
+			recordedAuto, recordedEnabled := record.fields["auto"] == "true", record.fields["enabled"] == "true"
+			if dsConn.Auto != recordedAuto || dsConn.Enabled != recordedEnabled {
+				addEvent(
+					&mismatches, "Replay commanded team %d in %s with auto=%v, enabled=%v but the recording has "+
+						"auto=%v, enabled=%v", dsConn.TeamId, record.station, dsConn.Auto, dsConn.Enabled, recordedAuto,
+					recordedEnabled,
+				)
+			}
+		}
+		if replay.MatchState == PostMatch {
+			break
+		}
+	}
+
+	return mergeReplayMismatches(events, mismatches), nil
+}
+
+// Parses every line of a packet trace, in the format written by tracePacket.
+func parsePacketTrace(trace io.Reader) ([]packetTraceRecord, error) {
+	var records []packetTraceRecord
+	scanner := bufio.NewScanner(trace)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, ",")
+		if len(parts) < 4 {
+			return nil, fmt.Errorf("Invalid packet trace line %d: %q.", lineNumber, line)
+		}
+		recordTime, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid time on packet trace line %d: %v.", lineNumber, err)
+		}
+		teamId, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid team on packet trace line %d: %v.", lineNumber, err)
+		}
+		record := packetTraceRecord{recordTime, parts[1], parts[2], teamId, make(map[string]string)}
+		for _, field := range parts[4:] {
+			if key, value, ok := strings.Cut(field, "="); ok {
+				record.fields[key] = value
+			}
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("Packet trace is empty.")
+	}
+	return records, nil
+}
+
+// Adds the command differences to the timeline, leaving out those close enough to a state transition to be explained
+// by the uncertainty in the inferred match start time.
+func mergeReplayMismatches(events, mismatches []ReplayEvent) []ReplayEvent {
+	var transitionTimes []time.Time
+	for _, event := range events {
+		if strings.HasPrefix(event.Description, "Match entered ") {
+			transitionTimes = append(transitionTimes, event.Time)
+		}
+	}
+	for _, mismatch := range mismatches {
+		nearTransition := false
+		for _, transitionTime := range transitionTimes {
+			offset := mismatch.Time.Sub(transitionTime)
+			if offset < 0 {
+				offset = -offset
+			}
+			if offset.Milliseconds() <= replayTransitionToleranceMs {
+				nearTransition = true
+				break
+			}
+		}
+		if !nearTransition {
+			events = append(events, mismatch)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
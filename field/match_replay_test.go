@@ -0,0 +1,113 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayPacketTrace(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.MatchTiming.WarmupDurationSec = 1
+	arena.MatchTiming.AutoDurationSec = 3
+	arena.MatchTiming.PauseDurationSec = 1
+	arena.MatchTiming.TeleopDurationSec = 5
+
+	// Synthesize the trace of a match for a single team whose robot briefly loses its link during teleop.
+	startTime := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	var trace strings.Builder
+	traceLine := func(offset time.Duration, direction, fields string) {
+		trace.WriteString(
+			fmt.Sprintf("%s,%s,R1,254,%s\n", startTime.Add(offset).Format(time.RFC3339Nano), direction, fields),
+		)
+	}
+	commanded := func(offset time.Duration) (bool, bool) {
+		matchTimeSec := offset.Seconds()
+		switch {
+		case matchTimeSec < 1:
+			return true, false
+		case matchTimeSec < 4:
+			return true, true
+		case matchTimeSec < 5:
+			return false, false
+		case matchTimeSec < 10:
+			return false, true
+		default:
+			return false, false
+		}
+	}
+	transitions := map[time.Duration]bool{
+		1003 * time.Millisecond: true, 4003 * time.Millisecond: true, 5003 * time.Millisecond: true,
+		10003 * time.Millisecond: true,
+	}
+	for offset := -time.Second; offset <= 11*time.Second; offset += time.Millisecond {
+		auto, enabled := commanded(offset)
+		robotLinked := offset < 6*time.Second || offset >= 7500*time.Millisecond
+		if offset%(50*time.Millisecond) == 0 {
+			traceLine(
+				offset, "udpStatus", fmt.Sprintf("radioLinked=true,robotLinked=%v,robotCodeRunning=%v,"+
+					"robotEnabled=%v,batteryVoltage=12.50", robotLinked, robotLinked, enabled && robotLinked),
+			)
+		}
+		if offset == 7*time.Second {
+			// A stop that the replay has no way of knowing about.
+			enabled = false
+		}
+		if offset%(250*time.Millisecond) == 0 || transitions[offset] {
+			traceLine(offset, "sent", fmt.Sprintf("auto=%v,enabled=%v,estop=false,error=false", auto, enabled))
+		}
+	}
+
+	events, err := arena.ReplayPacketTrace(strings.NewReader(trace.String()))
+	assert.Nil(t, err)
+	var descriptions []string
+	for _, event := range events {
+		descriptions = append(descriptions, fmt.Sprintf("%.2f %s", event.MatchTimeSec, event.Description))
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"-1.00 Driver station for team 254 connected in R1",
+			"-1.00 Robot for team 254 in R1 linked",
+			"0.00 Match started",
+			"0.00 Match entered WARMUP_PERIOD",
+			"1.00 Match entered AUTO_PERIOD",
+			"4.00 Match entered PAUSE_PERIOD",
+			"5.00 Match entered TELEOP_PERIOD",
+			"6.00 Robot for team 254 in R1 lost its link",
+			"7.00 Replay commanded team 254 in R1 with auto=false, enabled=true but the recording has " +
+				"auto=false, enabled=false",
+			"7.50 Robot for team 254 in R1 linked",
+			"10.00 Match entered POST_MATCH",
+		},
+		descriptions,
+	)
+	assert.Equal(t, "T  +1.000  Match entered AUTO_PERIOD", events[4].String())
+
+	// The live arena should be untouched.
+	assert.Equal(t, PreMatch, arena.MatchState)
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+}
+
+func TestReplayPacketTraceErrors(t *testing.T) {
+	arena := setupTestArena(t)
+
+	_, err := arena.ReplayPacketTrace(strings.NewReader(""))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Packet trace is empty.", err.Error())
+	}
+	_, err = arena.ReplayPacketTrace(strings.NewReader("garbage\n"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid packet trace line 1: \"garbage\".", err.Error())
+	}
+	_, err = arena.ReplayPacketTrace(
+		strings.NewReader("2026-03-14T10:00:00Z,sent,R1,254,auto=true,enabled=false,estop=false,error=false\n"),
+	)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Read-only lookup of a completed match's result, for displaying past results without disturbing the live match.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+)
+
+type MatchResultSummary struct {
+	Match            *model.Match
+	MatchResult      *model.MatchResult
+	RedScoreSummary  *game.ScoreSummary
+	BlueScoreSummary *game.ScoreSummary
+	Winner           game.MatchStatus
+}
+
+// Returns the final result of the given completed match, including the lineup, each alliance's score breakdown and
+// the winner. Unlike LoadMatch, this doesn't assign any teams or otherwise change the state of the arena.
+func (arena *Arena) LoadResult(matchId int) (*MatchResultSummary, error) {
+	match, err := arena.Datastore.GetMatchById(matchId)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, newArenaError(MatchNotFoundError, "No match with ID %d exists.", matchId)
+	}
+	if !match.IsComplete() {
+		return nil, newArenaError(InvalidStateError, "Match %s has not been completed yet.", match.DisplayName)
+	}
+	matchResult, err := arena.Datastore.GetMatchResultForMatch(matchId)
+	if err != nil {
+		return nil, err
+	}
+	if matchResult == nil {
+		return nil, newArenaError(MatchNotFoundError, "No result exists for match %s.", match.DisplayName)
+	}
+
+	return &MatchResultSummary{
+		Match:            match,
+		MatchResult:      matchResult,
+		RedScoreSummary:  matchResult.RedScoreSummary(),
+		BlueScoreSummary: matchResult.BlueScoreSummary(),
+		Winner:           match.OfficialStatus(),
+	}, nil
+}
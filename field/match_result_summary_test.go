@@ -0,0 +1,49 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLoadResult(t *testing.T) {
+	arena := setupTestArena(t)
+
+	_, err := arena.LoadResult(123)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+	}
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 254, Blue1: 1114}
+	arena.Database.CreateMatch(&match)
+	_, err = arena.LoadResult(match.Id)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Match 1 has not been completed yet.", err.Error())
+	}
+
+	match.Status = game.BlueWonMatch
+	arena.Database.UpdateMatch(&match)
+	_, err = arena.LoadResult(match.Id)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+	}
+
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	arena.Database.CreateMatchResult(matchResult)
+	result, err := arena.LoadResult(match.Id)
+	assert.Nil(t, err)
+	if assert.NotNil(t, result) {
+		assert.Equal(t, match.Id, result.Match.Id)
+		assert.Equal(t, 254, result.Match.Red1)
+		assert.Equal(t, matchResult.RedScoreSummary(), result.RedScoreSummary)
+		assert.Equal(t, matchResult.BlueScoreSummary(), result.BlueScoreSummary)
+		assert.Equal(t, game.BlueWonMatch, result.Winner)
+	}
+
+	// The live arena state should be left alone.
+	assert.Equal(t, "test", arena.CurrentMatch.Type)
+	assert.Nil(t, arena.AllianceStations["R1"].Team)
+}
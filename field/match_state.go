@@ -0,0 +1,37 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Human-readable names for the match states, for logs and tooling.
+
+package field
+
+import "fmt"
+
+var matchStateNames = map[MatchState]string{
+	PreMatch:      "PRE_MATCH",
+	StartMatch:    "START_MATCH",
+	WarmupPeriod:  "WARMUP_PERIOD",
+	AutoPeriod:    "AUTO_PERIOD",
+	PausePeriod:   "PAUSE_PERIOD",
+	TeleopPeriod:  "TELEOP_PERIOD",
+	PostMatch:     "POST_MATCH",
+	TimeoutActive: "TIMEOUT_ACTIVE",
+	PostTimeout:   "POST_TIMEOUT",
+}
+
+// Returns the name of the match state, e.g. "AUTO_PERIOD". The numeric value is still what is sent to the displays.
+func (state MatchState) String() string {
+	if name, ok := matchStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("MatchState(%d)", int(state))
+}
+
+// Returns the match state with the given name, as produced by String.
+func ParseMatchState(name string) (MatchState, error) {
+	for state, stateName := range matchStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid match state '%s'.", name)
+}
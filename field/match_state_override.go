@@ -0,0 +1,54 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Direct positioning of the match clock for tests and recovery tooling, bypassing the normal match flow.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"log"
+	"time"
+)
+
+// Period of the match clock that each timed match state corresponds to.
+var matchStatePeriods = map[MatchState]int{
+	WarmupPeriod: game.PeriodWarmup,
+	AutoPeriod:   game.PeriodAuto,
+	PausePeriod:  game.PeriodPause,
+	TeleopPeriod: game.PeriodTeleop,
+}
+
+// Puts the arena directly into the given timed match state as if the given number of seconds had elapsed since the
+// start of the match, by backdating the match start time against the arena clock. The arena loop carries on from
+// there. This is NOT for normal operation: none of the pre-start checks or start-of-match bookkeeping are performed,
+// so it is only allowed while the test match is loaded. The elapsed time must fall within the given state's period.
+func (arena *Arena) SetState(state MatchState, elapsedSec float64) error {
+	if arena.CurrentMatch.Type != "test" {
+		return newArenaError(InvalidStateError, "Cannot set the match state directly unless the test match is loaded.")
+	}
+	if arena.MatchState == TimeoutActive || arena.MatchState == PostTimeout {
+		return newArenaError(InvalidStateError, "Cannot set the match state directly during a timeout.")
+	}
+	period, ok := matchStatePeriods[state]
+	if !ok {
+		return newArenaError(InvalidStateError, "Cannot set the match state directly to %s.", state)
+	}
+	timing := arena.MatchTiming
+	if game.PeriodAtTime(elapsedSec, timing) != period {
+		return newArenaError(
+			InvalidDurationError, "An elapsed time of %.3f seconds doesn't fall within %s.", elapsedSec, state,
+		)
+	}
+
+	arena.MatchStartTime = arena.now().Add(-time.Duration(elapsedSec * float64(time.Second)))
+	arena.LastMatchTimeSec = -1
+	arena.resetTeleopAdjustment()
+	arena.endgameStarted = false
+	arena.autoEnablePending = false
+	arena.matchAborted = false
+	arena.setMatchState(state)
+	// Send the robots their new commands on the next loop rather than waiting for the periodic packet.
+	arena.lastDsPacketTime = time.Time{}
+	log.Printf("Match state set directly to %s at %.3f seconds elapsed.", state, elapsedSec)
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetState(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	dsConn := &DriverStationConnection{DsLinked: true, RobotLinked: true, lastPacketTime: time.Now()}
+	arena.AllianceStations["R1"].DsConn = dsConn
+	teleopStartSec := float64(arena.MatchTiming.WarmupDurationSec + arena.MatchTiming.AutoDurationSec +
+		arena.MatchTiming.PauseDurationSec)
+	teleopEndSec := teleopStartSec + float64(arena.MatchTiming.TeleopDurationSec)
+
+	// Jump straight into the endgame.
+	endgameSec := teleopEndSec - float64(arena.MatchTiming.WarningRemainingDurationSec) + 1
+	assert.Nil(t, arena.SetState(TeleopPeriod, endgameSec))
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Equal(t, endgameSec, arena.MatchTimeSec())
+	arena.Update()
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.True(t, arena.endgameStarted)
+	assert.True(t, dsConn.Enabled)
+	assert.False(t, dsConn.Auto)
+
+	// The arena loop should carry on from the new position.
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarningRemainingDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, PostMatch, arena.MatchState)
+
+	assert.Nil(t, arena.SetState(AutoPeriod, float64(arena.MatchTiming.WarmupDurationSec)))
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.True(t, dsConn.Enabled)
+	assert.True(t, dsConn.Auto)
+	assert.False(t, arena.endgameStarted)
+
+	// The elapsed time must be consistent with the state.
+	err := arena.SetState(PausePeriod, teleopStartSec)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidDurationError))
+		assert.Contains(t, err.Error(), "doesn't fall within PAUSE_PERIOD")
+	}
+	assert.NotNil(t, arena.SetState(WarmupPeriod, -1))
+	assert.NotNil(t, arena.SetState(TeleopPeriod, teleopEndSec))
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+
+	// Only the timed states can be set.
+	err = arena.SetState(PostMatch, teleopEndSec)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+		assert.Equal(t, "Cannot set the match state directly to POST_MATCH.", err.Error())
+	}
+
+	// Real matches can't be manipulated.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "practice"}))
+	err = arena.SetState(TeleopPeriod, teleopStartSec)
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+	assert.Equal(t, PreMatch, arena.MatchState)
+}
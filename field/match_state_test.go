@@ -0,0 +1,33 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchStateString(t *testing.T) {
+	assert.Equal(t, "PRE_MATCH", PreMatch.String())
+	assert.Equal(t, "TELEOP_PERIOD", TeleopPeriod.String())
+	assert.Equal(t, "Match entered POST_TIMEOUT", fmt.Sprintf("Match entered %v", PostTimeout))
+	assert.Equal(t, "MatchState(42)", MatchState(42).String())
+
+	for state := PreMatch; state <= PostTimeout; state++ {
+		parsedState, err := ParseMatchState(state.String())
+		assert.Nil(t, err)
+		assert.Equal(t, state, parsedState)
+	}
+	_, err := ParseMatchState("HALFTIME")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid match state 'HALFTIME'.", err.Error())
+	}
+	_, err = ParseMatchState("MatchState(42)")
+	assert.NotNil(t, err)
+
+	// The displays still receive the numeric value.
+	data, _ := json.Marshal(map[string]MatchState{"MatchState": AutoPeriod})
+	assert.Equal(t, `{"MatchState":3}`, string(data))
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Extra non-scoring stations for exhibition formats that field a neutral robot alongside the two alliances.
+
+package field
+
+import "strings"
+
+// Registers an extra station with the given key (e.g. "N1") that belongs to neither alliance. Its robot is enabled
+// and disabled with the match like any other, but it isn't required to be ready for the match to start and never
+// takes part in scoring or rankings, which only consider the red and blue teams of the match. The driver station
+// protocol only knows the six alliance positions, so a driver station in a neutral station is told it is in Red 1.
+func (arena *Arena) AddNeutralStation(station string) error {
+	station = strings.TrimSpace(station)
+	if station == "" {
+		return newArenaError(InvalidStationError, "A neutral station must have a name.")
+	}
+	if arena.MatchInProgress() {
+		return newArenaError(InvalidStateError, "Cannot add a neutral station while a match is in progress.")
+	}
+
+	arena.allianceStationsMutex.Lock()
+	if _, ok := arena.AllianceStations[station]; ok {
+		arena.allianceStationsMutex.Unlock()
+		return newArenaError(InvalidStationError, "Alliance station '%s' already exists.", station)
+	}
+	allianceStation := &AllianceStation{Neutral: true}
+	allianceStation.statusHistory = newDsStatusHistory(len(arena.AllianceStations["R1"].statusHistory.snapshots))
+	arena.AllianceStations[station] = allianceStation
+	arena.neutralStationKeys = append(arena.neutralStationKeys, station)
+	arena.allianceStationsMutex.Unlock()
+	// The status message checks the stations, so it can only be generated once the mutex has been released.
+	arena.notifyStatusChanged()
+	return nil
+}
+
+// Returns the keys of the neutral stations in the order they were added.
+func (arena *Arena) NeutralStationKeys() []string {
+	return append([]string(nil), arena.neutralStationKeys...)
+}
+
+// Assigns the given team to the given neutral station, or empties it if the team number is zero. The team stays in
+// place across matches until it is changed, and can't also be in the match lineup or another neutral station.
+func (arena *Arena) AssignNeutralTeam(teamId int, station string) error {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok || !allianceStation.Neutral {
+		return newArenaError(InvalidStationError, "'%s' is not a neutral station.", station)
+	}
+	if arena.MatchInProgress() {
+		return newArenaError(InvalidStateError, "Cannot assign a neutral team while a match is in progress.")
+	}
+	if assignedStation := arena.getAssignedAllianceStation(teamId); teamId != 0 && assignedStation != "" &&
+		assignedStation != station {
+		return newArenaError(DuplicateTeamError, "Team %d is already assigned to station %s.", teamId, assignedStation)
+	}
+	if err := arena.assignTeam(teamId, station); err != nil {
+		return err
+	}
+	arena.notifyStatusChanged()
+	return nil
+}
+
+// Returns the keys of every station whose driver station is controlled by the arena: the alliance stations in
+// canonical order followed by any neutral stations.
+func (arena *Arena) controlledStationKeys() []string {
+	return append(arena.StationKeys(), arena.neutralStationKeys...)
+}
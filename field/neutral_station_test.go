@@ -0,0 +1,117 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAddNeutralStation(t *testing.T) {
+	arena := setupTestArena(t)
+
+	assert.True(t, IsArenaErrorCode(arena.AddNeutralStation(" "), InvalidStationError))
+	assert.True(t, IsArenaErrorCode(arena.AddNeutralStation("R1"), InvalidStationError))
+	assert.Nil(t, arena.AddNeutralStation("N1"))
+	assert.True(t, IsArenaErrorCode(arena.AddNeutralStation("N1"), InvalidStationError))
+	assert.Nil(t, arena.AddNeutralStation("N2"))
+	assert.Equal(t, []string{"N1", "N2"}, arena.NeutralStationKeys())
+	assert.True(t, arena.AllianceStations["N1"].Neutral)
+	assert.False(t, arena.AllianceStations["R1"].Neutral)
+
+	// Neutral stations aren't part of either alliance.
+	assert.Equal(t, []string{"R1", "R2", "R3", "B1", "B2", "B3"}, arena.StationKeys())
+	assert.Equal(t, []string{"R1", "R2", "R3"}, allianceStationKeys(RedAlliance))
+	assert.Equal(t, []string{"B1", "B2", "B3"}, allianceStationKeys(BlueAlliance))
+
+	assert.True(t, IsArenaErrorCode(arena.AssignNeutralTeam(254, "R1"), InvalidStationError))
+	assert.True(t, IsArenaErrorCode(arena.AssignNeutralTeam(254, "N3"), InvalidStationError))
+	assert.Nil(t, arena.AssignNeutralTeam(254, "N1"))
+	assert.Equal(t, 254, arena.AllianceStations["N1"].Team.Id)
+	assert.Equal(t, "N1", arena.getAssignedAllianceStation(254))
+
+	// The neutral team stays in place when a match is loaded.
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, 254, arena.AllianceStations["N1"].Team.Id)
+
+	// A team can't be both the neutral team and in the match lineup, or in two neutral stations.
+	err := arena.LoadMatch(&model.Match{Type: "test", Blue2: 254})
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, DuplicateTeamError))
+		assert.Equal(t, "Cannot load match containing team 254, which is in neutral station N1.", err.Error())
+	}
+	err = arena.AssignNeutralTeam(254, "N2")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, DuplicateTeamError))
+		assert.Equal(t, "Team 254 is already assigned to station N1.", err.Error())
+	}
+	assert.Nil(t, arena.AssignNeutralTeam(254, "N1"))
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "test", Blue2: 1114}))
+	assert.True(t, IsArenaErrorCode(arena.AssignNeutralTeam(1114, "N1"), DuplicateTeamError))
+
+	// Neutral teams can't be carded, since they aren't part of either alliance.
+	assert.True(t, IsArenaErrorCode(arena.SetCard("N1", game.RedCard), InvalidStationError))
+	arena.AllianceStations["N1"].Card = game.RedCard
+	redCards, blueCards := arena.Cards()
+	assert.Empty(t, redCards)
+	assert.Empty(t, blueCards)
+	arena.AllianceStations["N1"].Card = game.NoCard
+	assert.Nil(t, arena.AssignNeutralTeam(0, "N1"))
+	assert.Nil(t, arena.AllianceStations["N1"].Team)
+
+	arena.MatchState = AutoPeriod
+	assert.True(t, IsArenaErrorCode(arena.AddNeutralStation("N3"), InvalidStateError))
+	assert.True(t, IsArenaErrorCode(arena.AssignNeutralTeam(254, "N1"), InvalidStateError))
+}
+
+func TestNeutralStationEnablesWithMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.AddNeutralStation("N1"))
+	assert.Nil(t, arena.AssignNeutralTeam(9999, "N1"))
+
+	// The neutral robot isn't connected, but it also isn't required for the match to start.
+	assert.Empty(t, arena.CheckCanStartMatchAll())
+
+	// Nor does a linked neutral robot count towards the minimum number of robots.
+	arena.EventSettings.MinRobotsToStartMatch = 1
+	arena.CurrentMatch.Type = "qualification"
+	arena.AllianceStations["N1"].DsConn = &DriverStationConnection{TeamId: 9999, AllianceStation: "N1",
+		RobotLinked: true}
+	assert.NotEmpty(t, arena.CheckCanStartMatchAll())
+	arena.EventSettings.MinRobotsToStartMatch = 0
+	arena.CurrentMatch.Type = "test"
+
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+	arena.AllianceStations["N1"].DsConn.udpConn = udpConn
+	arena.AllianceStations["N1"].DsConn.lastPacketTime = time.Now()
+
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	assert.Equal(t, WarmupPeriod, arena.MatchState)
+	assert.False(t, arena.AllianceStations["N1"].DsConn.Enabled)
+	currentTime = currentTime.Add(time.Duration(arena.MatchTiming.WarmupDurationSec) * time.Second)
+	arena.Update()
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+	assert.True(t, arena.AllianceStations["N1"].DsConn.Enabled)
+	packet := readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0x04), packet[3]&0x04)
+
+	assert.Nil(t, arena.AbortMatch())
+	assert.False(t, arena.AllianceStations["N1"].DsConn.Enabled)
+	packet = readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0), packet[3]&0x04)
+}
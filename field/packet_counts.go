@@ -0,0 +1,32 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Tally of the enabled and disabled control packets sent to each station during a match, as an integrity check on the
+// robot commands.
+
+package field
+
+// Counts a control packet sent to the station's driver station while a match is in progress.
+func (allianceStation *AllianceStation) countPacket(matchInProgress bool) {
+	dsConn := allianceStation.DsConn
+	if !matchInProgress || dsConn == nil {
+		return
+	}
+	if dsConn.Enabled {
+		allianceStation.EnabledPacketCount++
+	} else {
+		allianceStation.DisabledPacketCount++
+	}
+}
+
+// Returns the number of enabled and disabled control packets sent to the given station during the current match.
+// Returns zero counts for an invalid station.
+func (arena *Arena) PacketCounts(station string) (enabled, disabled int) {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return 0, 0
+	}
+	return allianceStation.EnabledPacketCount, allianceStation.DisabledPacketCount
+}
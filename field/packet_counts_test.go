@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPacketCounts(t *testing.T) {
+	arena := setupTestArena(t)
+	startTime := time.Now()
+	currentTime := startTime
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	arena.AllianceStations["R1"].Bypass = false
+	dsConn := &DriverStationConnection{TeamId: 254, AllianceStation: "R1", RobotLinked: true, RobotCodeRunning: true}
+	arena.AllianceStations["R1"].DsConn = dsConn
+
+	// Packets sent before the match starts aren't counted.
+	step := func() {
+		currentTime = currentTime.Add(250 * time.Millisecond)
+		dsConn.lastPacketTime = time.Now()
+		arena.lastDsPacketTime = time.Time{}
+		arena.Update()
+	}
+	step()
+	enabled, disabled := arena.PacketCounts("R1")
+	assert.Equal(t, 0, enabled)
+	assert.Equal(t, 0, disabled)
+
+	// Simulate a whole match with a packet every quarter second.
+	startTime = currentTime
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	for arena.MatchState != PostMatch {
+		step()
+	}
+	enabled, disabled = arena.PacketCounts("R1")
+	assert.Equal(t, 4*(arena.MatchTiming.AutoDurationSec+arena.MatchTiming.TeleopDurationSec), enabled)
+	// No packet goes out at the start of the warmup, since robots are already disabled.
+	expectedDisabled := 4*(arena.MatchTiming.WarmupDurationSec+arena.MatchTiming.PauseDurationSec) - 1
+	assert.Equal(t, expectedDisabled, disabled)
+	assert.Equal(t, arena.AllianceStations["R1"].EnabledPacketCount, enabled)
+
+	// Bypassed stations have no driver station to send packets to.
+	enabled, disabled = arena.PacketCounts("R2")
+	assert.Equal(t, 0, enabled)
+	assert.Equal(t, 0, disabled)
+	enabled, disabled = arena.PacketCounts("R4")
+	assert.Equal(t, 0, enabled)
+	assert.Equal(t, 0, disabled)
+
+	// Packets after the match are ignored, and loading the next match resets the counts.
+	step()
+	_, disabled = arena.PacketCounts("R1")
+	assert.Equal(t, expectedDisabled, disabled)
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.LoadTestMatch())
+	enabled, disabled = arena.PacketCounts("R1")
+	assert.Equal(t, 0, enabled)
+	assert.Equal(t, 0, disabled)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Opt-in tracing of every packet exchanged with the driver stations, for diagnosing a specific team's field issues.
+
+package field
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// Number of trace lines that can be queued before new ones are dropped rather than holding up the arena loop.
+const packetTraceBufferSize = 1024
+
+// Copies queued trace lines to the writer on its own goroutine so that slow I/O never blocks the caller.
+type packetTracer struct {
+	writer       io.Writer
+	lines        chan string
+	done         chan struct{}
+	droppedLines int
+}
+
+func newPacketTracer(writer io.Writer) *packetTracer {
+	tracer := &packetTracer{writer: writer, lines: make(chan string, packetTraceBufferSize), done: make(chan struct{})}
+	go tracer.run()
+	return tracer
+}
+
+func (tracer *packetTracer) run() {
+	defer close(tracer.done)
+	for line := range tracer.lines {
+		if _, err := io.WriteString(tracer.writer, line); err != nil {
+			log.Printf("Failed to write driver station packet trace: %v", err)
+		}
+	}
+}
+
+// Flushes any queued lines and stops the writer goroutine.
+func (tracer *packetTracer) stop() {
+	close(tracer.lines)
+	<-tracer.done
+	if tracer.droppedLines > 0 {
+		log.Printf("Dropped %d driver station packet trace lines due to a slow writer.", tracer.droppedLines)
+	}
+}
+
+// Starts writing a line to the given writer for every packet sent to and status received from a driver station,
+// replacing any previous trace. Passing nil turns tracing off, which is the default. Any lines queued for the previous
+// writer are flushed before this returns, so it is safe for the caller to close it afterwards.
+func (arena *Arena) SetPacketTrace(writer io.Writer) {
+	arena.packetTraceMutex.Lock()
+	defer arena.packetTraceMutex.Unlock()
+
+	if arena.packetTracer != nil {
+		arena.packetTracer.stop()
+		arena.packetTracer = nil
+	}
+	if writer != nil {
+		arena.packetTracer = newPacketTracer(writer)
+	}
+}
+
+// Queues a trace line if tracing is enabled, dropping it if the writer has fallen too far behind.
+func (arena *Arena) tracePacket(direction string, dsConn *DriverStationConnection, format string, args ...interface{}) {
+	arena.packetTraceMutex.Lock()
+	defer arena.packetTraceMutex.Unlock()
+
+	if arena.packetTracer == nil {
+		return
+	}
+	line := fmt.Sprintf(
+		"%s,%s,%s,%d,%s\n", time.Now().Format(time.RFC3339Nano), direction, dsConn.AllianceStation, dsConn.TeamId,
+		fmt.Sprintf(format, args...),
+	)
+	select {
+	case arena.packetTracer.lines <- line:
+	default:
+		arena.packetTracer.droppedLines++
+	}
+}
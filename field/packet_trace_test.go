@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPacketTrace(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254, AllianceStation: "R1",
+		lastPacketTime: time.Now()}
+
+	// Nothing should be traced by default.
+	arena.sendDsPacket(true, false)
+	assert.Nil(t, arena.packetTracer)
+
+	var buffer bytes.Buffer
+	arena.SetPacketTrace(&buffer)
+	arena.sendDsPacket(true, false)
+	arena.sendDsPacket(false, true)
+	arena.SetPacketTrace(nil)
+	arena.sendDsPacket(false, false)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Equal(t, 2, len(lines)) {
+		assert.True(t, strings.HasSuffix(lines[0], ",sent,R1,254,auto=true,enabled=false,estop=false,error=false"))
+		assert.True(t, strings.HasSuffix(lines[1], ",sent,R1,254,auto=false,enabled=true,estop=false,error=false"))
+		_, err := time.Parse(time.RFC3339Nano, strings.Split(lines[0], ",")[0])
+		assert.Nil(t, err)
+	}
+}
+
+// Writer that doesn't return until it is released, simulating slow I/O.
+type blockingWriter struct {
+	release chan struct{}
+	lines   int
+}
+
+func (writer *blockingWriter) Write(p []byte) (int, error) {
+	<-writer.release
+	writer.lines++
+	return len(p), nil
+}
+
+func TestPacketTraceDoesNotBlock(t *testing.T) {
+	arena := setupTestArena(t)
+	dsConn := &DriverStationConnection{TeamId: 254, AllianceStation: "R1"}
+	writer := &blockingWriter{release: make(chan struct{})}
+	arena.SetPacketTrace(writer)
+
+	startTime := time.Now()
+	for i := 0; i < 2*packetTraceBufferSize; i++ {
+		arena.tracePacket("sent", dsConn, "index=%d", i)
+	}
+	assert.Less(t, time.Since(startTime).Seconds(), 1.0)
+	assert.Less(t, 0, arena.packetTracer.droppedLines)
+
+	close(writer.release)
+	arena.SetPacketTrace(nil)
+	assert.Less(t, writer.lines, 2*packetTraceBufferSize)
+	assert.GreaterOrEqual(t, writer.lines, packetTraceBufferSize)
+}
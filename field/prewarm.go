@@ -0,0 +1,97 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Early acceptance of driver station connections for the next match's teams while the current match wraps up, so
+// that they are already connected when the next match is loaded.
+
+package field
+
+import "log"
+
+// Starts accepting driver station connections from the teams in the next match, in the stations they are scheduled
+// for, without making it the current match. Only allowed after the current match has ended. The connections are
+// handed over when a match placing each team in the same station is loaded; those whose team or station has changed
+// by then are closed, and the driver station will reconnect as usual.
+func (arena *Arena) PrewarmNextMatch() error {
+	if arena.MatchState != PostMatch {
+		return newArenaError(InvalidStateError, "Cannot pre-warm the next match until the current match has ended.")
+	}
+	nextMatch, err := arena.getNextMatch(true)
+	if err != nil {
+		return err
+	}
+	if nextMatch == nil {
+		return newArenaError(MatchNotFoundError, "There is no next match to pre-warm.")
+	}
+
+	prewarmStations := make(map[int]string)
+	for _, station := range stationKeys {
+		if teamId := *stationMatchTeamFields[station](nextMatch); teamId != 0 {
+			prewarmStations[teamId] = station
+		}
+	}
+	arena.allianceStationsMutex.Lock()
+	arena.prewarmStations = prewarmStations
+	arena.dropStalePrewarmedDsConns()
+	arena.allianceStationsMutex.Unlock()
+	log.Printf("Pre-warming driver station connections for match %s.", nextMatch.DisplayName)
+	return nil
+}
+
+// Returns the station that the given team is scheduled for in the match being pre-warmed, or the empty string if the
+// team isn't in it.
+func (arena *Arena) getPrewarmAllianceStation(teamId int) string {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	return arena.prewarmStations[teamId]
+}
+
+// Keeps the given connection until the match being pre-warmed is loaded, as long as its team is still scheduled for
+// its station. Returns true if the connection was kept.
+func (arena *Arena) holdPrewarmedDsConn(dsConn *DriverStationConnection) bool {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	if station, ok := arena.prewarmStations[dsConn.TeamId]; !ok || station != dsConn.AllianceStation {
+		return false
+	}
+	if previousDsConn := arena.prewarmedDsConns[dsConn.TeamId]; previousDsConn != nil {
+		previousDsConn.close()
+	}
+	if arena.prewarmedDsConns == nil {
+		arena.prewarmedDsConns = make(map[int]*DriverStationConnection)
+	}
+	arena.prewarmedDsConns[dsConn.TeamId] = dsConn
+	return true
+}
+
+// Removes and returns the pre-warmed connection for the given team if it was opened for the given station, or nil if
+// there is none. Must be called with the alliance stations mutex held.
+func (arena *Arena) takePrewarmedDsConn(teamId int, station string) *DriverStationConnection {
+	dsConn := arena.prewarmedDsConns[teamId]
+	if dsConn == nil || dsConn.AllianceStation != station {
+		return nil
+	}
+	delete(arena.prewarmedDsConns, teamId)
+	log.Printf("Reusing pre-warmed driver station connection for team %d in station %s.", teamId, station)
+	return dsConn
+}
+
+// Closes any pre-warmed connections that no longer match the lineup being pre-warmed. Must be called with the
+// alliance stations mutex held.
+func (arena *Arena) dropStalePrewarmedDsConns() {
+	for teamId, dsConn := range arena.prewarmedDsConns {
+		if arena.prewarmStations[teamId] != dsConn.AllianceStation {
+			log.Printf("Closing pre-warmed driver station connection for team %d, whose station has changed.", teamId)
+			dsConn.close()
+			delete(arena.prewarmedDsConns, teamId)
+		}
+	}
+}
+
+// Stops pre-warming and closes the pre-warmed connections that weren't handed over to the loaded match.
+func (arena *Arena) endPrewarm() {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+	arena.prewarmStations = nil
+	arena.dropStalePrewarmedDsConns()
+}
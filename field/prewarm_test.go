@@ -0,0 +1,87 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPrewarmNextMatch(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, teamId := range []int{101, 102, 103, 104, 105, 106} {
+		arena.Database.CreateTeam(&model.Team{Id: teamId})
+	}
+	match := model.Match{Type: "practice", DisplayName: "1", Red1: 101}
+	arena.Database.CreateMatch(&match)
+	nextMatch := model.Match{Type: "practice", DisplayName: "2", Red2: 102, Red3: 103, Blue1: 104}
+	arena.Database.CreateMatch(&nextMatch)
+	assert.Nil(t, arena.LoadMatch(&match))
+	err := arena.PrewarmNextMatch()
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+
+	arena.MatchState = PostMatch
+	match.Status = game.RedWonMatch
+	arena.Database.UpdateMatch(&match)
+	assert.Nil(t, arena.PrewarmNextMatch())
+	assert.Equal(t, "R2", arena.getPrewarmAllianceStation(102))
+	assert.Equal(t, "", arena.getPrewarmAllianceStation(101))
+	assert.Nil(t, arena.AllianceStations["R2"].Team)
+
+	// Simulate the listener accepting early connections from driver stations in the next match.
+	openDsConn := func(teamId int, station string) (*DriverStationConnection, func() error) {
+		serverConn, clientConn := setupLoopbackTcpConn(t)
+		t.Cleanup(func() { clientConn.Close() })
+		dsConn, err := newDriverStationConnection(teamId, station, serverConn, 0, 0)
+		assert.Nil(t, err)
+		checkClosed := func() error {
+			clientConn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+			_, err := clientConn.Read(make([]byte, 1))
+			return err
+		}
+		return dsConn, checkClosed
+	}
+	dsConn102, _ := openDsConn(102, "R2")
+	assert.True(t, arena.holdPrewarmedDsConn(dsConn102))
+	dsConn104, checkClosed104 := openDsConn(104, "B1")
+	assert.True(t, arena.holdPrewarmedDsConn(dsConn104))
+	dsConn105, checkClosed105 := openDsConn(105, "B2")
+	assert.False(t, arena.holdPrewarmedDsConn(dsConn105))
+	dsConn105.close()
+	assert.NotNil(t, checkClosed105())
+
+	// A load which fails partway through the lineup should hand back the pre-warmed connections it has taken.
+	arena.SetEventRoster([]int{101, 102})
+	arena.EventSettings.EnforceEventRoster = true
+	arena.MatchState = PreMatch
+	err = arena.LoadNextMatch()
+	assert.True(t, IsArenaErrorCode(err, TeamNotRegisteredError))
+	assert.Nil(t, arena.AllianceStations["R2"].DsConn)
+	assert.Same(t, dsConn102, arena.prewarmedDsConns[102])
+	assert.Same(t, dsConn104, arena.prewarmedDsConns[104])
+	arena.EventSettings.EnforceEventRoster = false
+
+	// Move one of the pre-warmed teams to a different station before the next match is loaded.
+	nextMatch.Blue1 = 0
+	nextMatch.Blue2 = 104
+	arena.Database.UpdateMatch(&nextMatch)
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadNextMatch())
+	assert.Equal(t, nextMatch.Id, arena.CurrentMatch.Id)
+	assert.Same(t, dsConn102, arena.AllianceStations["R2"].DsConn)
+	assert.Equal(t, StationConnected, arena.AllianceStations["R2"].ConnectionState())
+	assert.Nil(t, arena.AllianceStations["R3"].DsConn)
+	assert.Nil(t, arena.AllianceStations["B2"].DsConn)
+	assert.NotNil(t, checkClosed104())
+	assert.Empty(t, arena.prewarmedDsConns)
+	assert.Equal(t, "", arena.getPrewarmAllianceStation(103))
+
+	// There is nothing left to pre-warm after the last match.
+	arena.MatchState = PostMatch
+	err = arena.PrewarmNextMatch()
+	assert.True(t, IsArenaErrorCode(err, MatchNotFoundError))
+	arena.AllianceStations["R2"].DsConn.close()
+}
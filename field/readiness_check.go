@@ -0,0 +1,48 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Automated pre-match go/no-go check of every robot, formalizing the link checks that the FTA does by hand.
+
+package field
+
+import "log"
+
+// Pings the driver station of every non-bypassed station with a team, one at a time, and reports each one as ready
+// if its driver station answered with a status showing its robot linked with code running. The status is taken from
+// the answering packet itself, so that a robot which was linked earlier but has since dropped isn't counted. Stations
+// without a driver station connected are reported as not ready. The pings are disabled control packets, so no robot
+// is enabled, and the check is only allowed before a match is started and while no robot is enabled for diagnostics.
+func (arena *Arena) RunReadinessCheck() (map[string]bool, error) {
+	if arena.MatchState != PreMatch {
+		return nil, newArenaError(
+			InvalidStateError, "Cannot run the readiness check while a match is in progress.",
+		)
+	}
+	if station := arena.diagnosticEnabledStation(); station != "" {
+		return nil, newArenaError(
+			InvalidStateError, "Cannot run the readiness check while the robot in station %s is enabled for "+
+				"diagnostics.", station,
+		)
+	}
+
+	readiness := make(map[string]bool)
+	for _, station := range arena.StationKeys() {
+		arena.allianceStationsMutex.Lock()
+		allianceStation := arena.AllianceStations[station]
+		skip := allianceStation.Team == nil || allianceStation.Bypass
+		arena.allianceStationsMutex.Unlock()
+		if skip {
+			continue
+		}
+
+		answered, _, status, err := arena.pingStation(station)
+		if err != nil && !IsArenaErrorCode(err, NotReadyError) {
+			log.Printf("Readiness check of station %s failed: %v", station, err)
+		}
+		readiness[station] = answered && status.robotLinked && status.robotCodeRunning
+		if !readiness[station] {
+			log.Printf("Station %s is not ready: its driver station didn't answer or its robot isn't running code.",
+				station)
+		}
+	}
+	return readiness, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRunReadinessCheck(t *testing.T) {
+	arena := setupTestArena(t)
+	enabledBits := make(chan byte, 6)
+	simulateDs := func(station string, teamId int, answer bool, status byte) {
+		arena.AllianceStations[station].Team = &model.Team{Id: teamId}
+		dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.Nil(t, err)
+		t.Cleanup(func() { dsListener.Close() })
+		udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+		assert.Nil(t, err)
+		t.Cleanup(func() { udpConn.Close() })
+		arena.AllianceStations[station].DsConn = &DriverStationConnection{
+			TeamId: teamId, AllianceStation: station, udpConn: udpConn,
+		}
+		go func() {
+			var data [22]byte
+			dsListener.SetReadDeadline(time.Now().Add(2 * time.Second))
+			if _, err := dsListener.Read(data[:]); err == nil {
+				enabledBits <- data[3] & 0x04
+				if answer {
+					arena.handleDsUdpPacket([50]byte{3: status, 4: byte(teamId >> 8), 5: byte(teamId & 0xff)})
+				}
+			}
+		}()
+	}
+
+	readiness, err := arena.RunReadinessCheck()
+	assert.Nil(t, err)
+	assert.Empty(t, readiness)
+
+	simulateDs("R1", 254, true, 0x30)  // Radio linked, robot code running.
+	simulateDs("R2", 1114, true, 0x18) // Radio and robot linked, but no robot code.
+	simulateDs("R3", 148, false, 0x30)
+	arena.AllianceStations["B1"].Team = &model.Team{Id: 2056}
+	arena.AllianceStations["B2"].Team = &model.Team{Id: 1678}
+	arena.AllianceStations["B2"].Bypass = true
+	simulateDs("B3", 971, true, 0x00) // Answers, but its robot has dropped off.
+
+	// The driver stations start out unlinked, and R3 and B3 are given a stale linked status, so that only the status
+	// in the answer to the ping can make a station ready.
+	for _, station := range []string{"R1", "R2"} {
+		assert.False(t, arena.AllianceStations[station].DsConn.RobotLinked)
+	}
+	for _, station := range []string{"R3", "B3"} {
+		arena.AllianceStations[station].DsConn.RobotLinked = true
+		arena.AllianceStations[station].DsConn.RobotCodeRunning = true
+	}
+	readiness, err = arena.RunReadinessCheck()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"R1": true, "R2": false, "R3": false, "B1": false, "B3": false}, readiness)
+
+	// Every robot that was checked should only ever have been sent disabled packets.
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, byte(0), <-enabledBits)
+	}
+	for _, station := range []string{"R1", "R2", "R3", "B3"} {
+		assert.False(t, arena.AllianceStations[station].DsConn.Enabled)
+	}
+
+	arena.MatchState = AutoPeriod
+	_, err = arena.RunReadinessCheck()
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	}
+	arena.MatchState = PreMatch
+	arena.diagnosticEnable = &diagnosticEnable{station: "R1", teamId: 254}
+	_, err = arena.RunReadinessCheck()
+	if assert.NotNil(t, err) {
+		assert.Equal(
+			t, "Cannot run the readiness check while the robot in station R1 is enabled for diagnostics.", err.Error(),
+		)
+	}
+}
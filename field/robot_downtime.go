@@ -0,0 +1,41 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Accounting of how long each robot was out of contact while it should have been enabled during a match.
+
+package field
+
+import "time"
+
+// Accounts for the time since the previous driver station packet if the station's robot was commanded enabled but
+// wasn't linked, given whether the match is currently enabling robots.
+func (allianceStation *AllianceStation) updateDowntime(matchEnabled bool, now time.Time) {
+	if allianceStation.Team == nil || !allianceStation.isEnabled(matchEnabled) {
+		allianceStation.lastDowntimeSampleTime = time.Time{}
+		return
+	}
+	robotLinked := allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked
+	if !robotLinked && !allianceStation.lastDowntimeSampleTime.IsZero() {
+		allianceStation.downtimeSec += now.Sub(allianceStation.lastDowntimeSampleTime).Seconds()
+	}
+	allianceStation.lastDowntimeSampleTime = now
+}
+
+// Clears the downtime accumulated during the previous match.
+func (allianceStation *AllianceStation) resetDowntime() {
+	allianceStation.downtimeSec = 0
+	allianceStation.lastDowntimeSampleTime = time.Time{}
+}
+
+// Returns the number of seconds of the current match during which the robot in the given station was commanded
+// enabled but wasn't linked, measured at the resolution of the driver station packets. Returns zero for an invalid
+// station.
+func (arena *Arena) RobotDowntimeSec(station string) float64 {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return 0
+	}
+	return allianceStation.downtimeSec
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRobotDowntimeSec(t *testing.T) {
+	arena := setupTestArena(t)
+	startTime := time.Now()
+	currentTime := startTime
+	arena.now = func() time.Time { return currentTime }
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Nil(t, arena.assignTeam(1114, "R2"))
+	arena.AllianceStations["R1"].Bypass = false
+	dsConn := &DriverStationConnection{TeamId: 254, AllianceStation: "R1", RobotLinked: true, RobotCodeRunning: true}
+	arena.AllianceStations["R1"].DsConn = dsConn
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R1"))
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R4"))
+
+	// Steps the match clock forward in quarter-second increments, sending a driver station packet each time and
+	// setting the robot link according to the given function of the time since teleop began.
+	teleopStartSec := arena.MatchTiming.GetDurationToTeleopStart().Seconds()
+	runUntil := func(matchTimeSec float64, robotLinked func(teleopTimeSec float64) bool) {
+		for currentTime.Sub(startTime).Seconds() < matchTimeSec {
+			currentTime = currentTime.Add(250 * time.Millisecond)
+			dsConn.RobotLinked = robotLinked(currentTime.Sub(startTime).Seconds() - teleopStartSec)
+			dsConn.lastPacketTime = time.Now()
+			arena.lastDsPacketTime = time.Time{}
+			arena.Update()
+		}
+	}
+	assert.Nil(t, arena.StartMatch())
+	arena.Update()
+	runUntil(arena.MatchTiming.GetDurationToAutoEnd().Seconds(), func(float64) bool { return true })
+	assert.Equal(t, PausePeriod, arena.MatchState)
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R1"))
+
+	// Losing the link while robots are disabled during the pause doesn't count.
+	runUntil(teleopStartSec-0.25, func(float64) bool { return false })
+	assert.Equal(t, PausePeriod, arena.MatchState)
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R1"))
+
+	// Drop the link for three seconds of teleop.
+	runUntil(teleopStartSec+10, func(teleopTimeSec float64) bool {
+		return teleopTimeSec < 2 || teleopTimeSec >= 5
+	})
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.InDelta(t, 3.0, arena.RobotDowntimeSec("R1"), 0.001)
+
+	// A bypassed robot is never commanded enabled, so it accrues no downtime despite never connecting.
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R2"))
+
+	assert.Nil(t, arena.AbortMatch())
+	runUntil(teleopStartSec+15, func(float64) bool { return false })
+	assert.InDelta(t, 3.0, arena.RobotDowntimeSec("R1"), 0.001)
+
+	assert.Nil(t, arena.ResetMatch())
+	assert.Nil(t, arena.LoadTestMatch())
+	assert.Equal(t, 0.0, arena.RobotDowntimeSec("R1"))
+}
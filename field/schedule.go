@@ -0,0 +1,109 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Display-oriented representation of the match schedule, with teams resolved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"time"
+)
+
+type ScheduleTeam struct {
+	Id          int
+	Nickname    string
+	IsSurrogate bool
+}
+
+type ScheduleEntry struct {
+	MatchId     int
+	DisplayName string
+	Time        time.Time
+	Complete    bool
+	// Winner of the match, or game.MatchNotPlayed if it isn't complete.
+	Status    game.MatchStatus
+	RedTeams  [3]ScheduleTeam
+	BlueTeams [3]ScheduleTeam
+	IsCurrent bool
+}
+
+// A match that a particular team plays in, along with where it plays from.
+type TeamScheduleEntry struct {
+	Match    model.Match
+	Station  string
+	Alliance int
+}
+
+// Returns every match of the given type in schedule order, with team details looked up so that a schedule display
+// needs no further queries. Empty positions are left as zero values.
+func (arena *Arena) GetSchedule(matchType string) ([]ScheduleEntry, error) {
+	matches, err := arena.Datastore.GetMatchesByType(matchType)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := arena.Datastore.GetAllTeams()
+	if err != nil {
+		return nil, err
+	}
+	teamNicknames := make(map[int]string, len(teams))
+	for _, team := range teams {
+		teamNicknames[team.Id] = team.Nickname
+	}
+	scheduleTeam := func(teamId int, isSurrogate bool) ScheduleTeam {
+		if teamId == 0 {
+			return ScheduleTeam{}
+		}
+		return ScheduleTeam{Id: teamId, Nickname: teamNicknames[teamId], IsSurrogate: isSurrogate}
+	}
+
+	schedule := make([]ScheduleEntry, len(matches))
+	for i, match := range matches {
+		schedule[i] = ScheduleEntry{
+			MatchId:     match.Id,
+			DisplayName: match.TypePrefix() + match.DisplayName,
+			Time:        match.Time,
+			Complete:    match.IsComplete(),
+			Status:      match.Status,
+			RedTeams: [3]ScheduleTeam{
+				scheduleTeam(match.Red1, match.Red1IsSurrogate),
+				scheduleTeam(match.Red2, match.Red2IsSurrogate),
+				scheduleTeam(match.Red3, match.Red3IsSurrogate),
+			},
+			BlueTeams: [3]ScheduleTeam{
+				scheduleTeam(match.Blue1, match.Blue1IsSurrogate),
+				scheduleTeam(match.Blue2, match.Blue2IsSurrogate),
+				scheduleTeam(match.Blue3, match.Blue3IsSurrogate),
+			},
+			IsCurrent: arena.CurrentMatch != nil && arena.CurrentMatch.Id != 0 && arena.CurrentMatch.Id == match.Id,
+		}
+	}
+	return schedule, nil
+}
+
+// Returns every match of any type that the given team plays in, with practice matches first, then qualification and
+// then playoff matches, each in schedule order. Returns an empty list if the team isn't in any matches.
+func (arena *Arena) GetTeamSchedule(teamId int) ([]TeamScheduleEntry, error) {
+	schedule := make([]TeamScheduleEntry, 0)
+	if teamId == 0 {
+		return schedule, nil
+	}
+	for _, matchType := range []string{"practice", "qualification", "elimination"} {
+		matches, err := arena.Datastore.GetMatchesByType(matchType)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			for _, alliance := range []int{RedAlliance, BlueAlliance} {
+				for _, station := range allianceStationKeys(alliance) {
+					if *stationMatchTeamFields[station](&match) == teamId {
+						schedule = append(
+							schedule, TeamScheduleEntry{Match: match, Station: station, Alliance: alliance},
+						)
+					}
+				}
+			}
+		}
+	}
+	return schedule, nil
+}
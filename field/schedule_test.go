@@ -0,0 +1,96 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetSchedule(t *testing.T) {
+	arena := setupTestArena(t)
+
+	schedule, err := arena.GetSchedule("qualification")
+	assert.Nil(t, err)
+	assert.Empty(t, schedule)
+
+	arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs"})
+	arena.Database.CreateTeam(&model.Team{Id: 1114, Nickname: "Simbotics"})
+	matchTime := time.Unix(1000, 0).UTC()
+	match1 := model.Match{Type: "qualification", DisplayName: "1", Time: matchTime, Red1: 254, Blue2: 1114,
+		Blue2IsSurrogate: true, Status: game.RedWonMatch}
+	match2 := model.Match{Type: "qualification", DisplayName: "2", Time: matchTime.Add(6 * time.Minute), Red3: 1114,
+		Blue1: 9999}
+	arena.Database.CreateMatch(&match1)
+	arena.Database.CreateMatch(&match2)
+	arena.Database.CreateMatch(&model.Match{Type: "practice", DisplayName: "1"})
+	assert.Nil(t, arena.LoadMatch(&match1))
+
+	schedule, err = arena.GetSchedule("qualification")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(schedule)) {
+		assert.Equal(t, match1.Id, schedule[0].MatchId)
+		assert.Equal(t, "Q1", schedule[0].DisplayName)
+		assert.Equal(t, matchTime, schedule[0].Time.UTC())
+		assert.True(t, schedule[0].Complete)
+		assert.Equal(t, game.RedWonMatch, schedule[0].Status)
+		assert.Equal(t, ScheduleTeam{Id: 254, Nickname: "The Cheesy Poofs"}, schedule[0].RedTeams[0])
+		assert.Equal(t, ScheduleTeam{}, schedule[0].RedTeams[1])
+		assert.Equal(t, ScheduleTeam{Id: 1114, Nickname: "Simbotics", IsSurrogate: true}, schedule[0].BlueTeams[1])
+		assert.True(t, schedule[0].IsCurrent)
+
+		assert.Equal(t, "Q2", schedule[1].DisplayName)
+		assert.False(t, schedule[1].Complete)
+		assert.Equal(t, game.MatchNotPlayed, schedule[1].Status)
+		assert.Equal(t, ScheduleTeam{Id: 1114, Nickname: "Simbotics"}, schedule[1].RedTeams[2])
+		// Teams missing from the database should still be listed by number.
+		assert.Equal(t, ScheduleTeam{Id: 9999}, schedule[1].BlueTeams[0])
+		assert.False(t, schedule[1].IsCurrent)
+	}
+
+	// The test match shouldn't be flagged as current in any schedule.
+	assert.Nil(t, arena.LoadTestMatch())
+	schedule, _ = arena.GetSchedule("qualification")
+	assert.False(t, schedule[0].IsCurrent)
+}
+
+func TestGetTeamSchedule(t *testing.T) {
+	arena := setupTestArena(t)
+
+	schedule, err := arena.GetTeamSchedule(254)
+	assert.Nil(t, err)
+	assert.NotNil(t, schedule)
+	assert.Empty(t, schedule)
+
+	arena.Database.CreateMatch(&model.Match{Type: "elimination", DisplayName: "F-1", ElimRound: 1, Blue3: 254})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "2", Red2: 254, Blue1: 1114})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "3", Red1: 1114, Blue2: 846})
+	arena.Database.CreateMatch(&model.Match{Type: "practice", DisplayName: "1", Blue1: 254})
+	arena.Database.CreateMatch(&model.Match{Type: "qualification", DisplayName: "4", Red3: 846, Blue2: 254})
+
+	schedule, err = arena.GetTeamSchedule(254)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(schedule)) {
+		assert.Equal(t, "1", schedule[0].Match.DisplayName)
+		assert.Equal(t, "practice", schedule[0].Match.Type)
+		assert.Equal(t, "B1", schedule[0].Station)
+		assert.Equal(t, BlueAlliance, schedule[0].Alliance)
+		assert.Equal(t, "2", schedule[1].Match.DisplayName)
+		assert.Equal(t, "R2", schedule[1].Station)
+		assert.Equal(t, RedAlliance, schedule[1].Alliance)
+		assert.Equal(t, "4", schedule[2].Match.DisplayName)
+		assert.Equal(t, "B2", schedule[2].Station)
+		assert.Equal(t, "F-1", schedule[3].Match.DisplayName)
+		assert.Equal(t, "B3", schedule[3].Station)
+	}
+
+	schedule, err = arena.GetTeamSchedule(9999)
+	assert.Nil(t, err)
+	assert.Empty(t, schedule)
+	schedule, err = arena.GetTeamSchedule(0)
+	assert.Nil(t, err)
+	assert.Empty(t, schedule)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Tracking of alliance stations whose team is assigned but whose driver station isn't connected, and manual retries
+// of driver station connections that failed to be set up.
+
+package field
+
+import (
+	"log"
+	"net"
+)
+
+type StationConnectionState string
+
+const (
+	StationEmpty              StationConnectionState = "empty"
+	StationAwaitingConnection StationConnectionState = "awaitingConnection"
+	StationConnected          StationConnectionState = "connected"
+)
+
+// Returns whether the station has no team, a team whose driver station hasn't connected (including one whose
+// connection failed to be set up), or a connected driver station.
+func (allianceStation *AllianceStation) ConnectionState() StationConnectionState {
+	if allianceStation.Team == nil {
+		return StationEmpty
+	}
+	if allianceStation.DsConn == nil {
+		return StationAwaitingConnection
+	}
+	return StationConnected
+}
+
+// Returns the connection state of every alliance station, keyed by station.
+func (arena *Arena) stationConnectionStates() map[string]StationConnectionState {
+	states := make(map[string]StationConnectionState, len(arena.AllianceStations))
+	for station, allianceStation := range arena.AllianceStations {
+		states[station] = allianceStation.ConnectionState()
+	}
+	return states
+}
+
+// Keeps the TCP connection of a driver station whose connection couldn't be fully set up, so that RetryConnection can
+// try again without waiting for the driver station to reconnect. Returns false if the station is no longer assigned to
+// the team, in which case the caller should close the connection.
+func (arena *Arena) holdPendingTcpConn(teamId int, station string, tcpConn net.Conn) bool {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	allianceStation := arena.AllianceStations[station]
+	if allianceStation.Team == nil || allianceStation.Team.Id != teamId || allianceStation.DsConn != nil {
+		return false
+	}
+	allianceStation.dropPendingTcpConn()
+	allianceStation.pendingTcpConn = tcpConn
+	return true
+}
+
+// Closes and forgets any held connection from a driver station whose setup failed. Must be called with the alliance
+// stations mutex held.
+func (allianceStation *AllianceStation) dropPendingTcpConn() {
+	if allianceStation.pendingTcpConn != nil {
+		allianceStation.pendingTcpConn.Close()
+		allianceStation.pendingTcpConn = nil
+	}
+}
+
+// Retries setting up the driver station connection for the given station, whose team is assigned but whose driver
+// station failed to connect. Returns an error if there is no failed connection to retry, or if it fails again.
+func (arena *Arena) RetryConnection(station string) error {
+	arena.allianceStationsMutex.Lock()
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		arena.allianceStationsMutex.Unlock()
+		return newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	switch allianceStation.ConnectionState() {
+	case StationEmpty:
+		arena.allianceStationsMutex.Unlock()
+		return newArenaError(NotReadyError, "No team is assigned to station %s.", station)
+	case StationConnected:
+		arena.allianceStationsMutex.Unlock()
+		return newArenaError(InvalidStateError, "The driver station in station %s is already connected.", station)
+	}
+	teamId := allianceStation.Team.Id
+	tcpConn := allianceStation.pendingTcpConn
+	allianceStation.pendingTcpConn = nil
+	arena.allianceStationsMutex.Unlock()
+	if tcpConn == nil {
+		return newArenaError(
+			NotReadyError, "No failed connection to retry for team %d in station %s; waiting for its driver station.",
+			teamId, station,
+		)
+	}
+
+	log.Printf("Retrying driver station connection for team %d in station %s.", teamId, station)
+	dsConn, err := newDriverStationConnection(
+		teamId, station, tcpConn, arena.EventSettings.DsConnectRetries, arena.EventSettings.DsConnectBackoffMs,
+	)
+	if err != nil {
+		if !arena.holdPendingTcpConn(teamId, station, tcpConn) {
+			tcpConn.Close()
+		}
+		return err
+	}
+	if !arena.attachDsConn(dsConn) {
+		dsConn.close()
+		return newArenaError(InvalidStateError, "Team %d was reassigned out of station %s while retrying.", teamId,
+			station)
+	}
+	go dsConn.handleTcpConnection(arena)
+	return nil
+}
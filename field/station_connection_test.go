@@ -0,0 +1,105 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// Returns both ends of a loopback TCP connection, the first being the arena's end.
+func setupLoopbackTcpConn(t *testing.T) (net.Conn, net.Conn) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	clientConn, err := net.Dial("tcp4", listener.Addr().String())
+	assert.Nil(t, err)
+	serverConn, err := listener.Accept()
+	assert.Nil(t, err)
+	return serverConn, clientConn
+}
+
+func TestStationConnectionState(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, allianceStation := range arena.AllianceStations {
+		allianceStation.Bypass = true
+	}
+	assert.Equal(t, StationEmpty, arena.AllianceStations["R1"].ConnectionState())
+
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Equal(t, StationAwaitingConnection, arena.AllianceStations["R1"].ConnectionState())
+	assert.Equal(t, StationAwaitingConnection, arena.stationConnectionStates()["R1"])
+	assert.Equal(t, StationEmpty, arena.stationConnectionStates()["R2"])
+	arena.AllianceStations["R1"].Bypass = false
+	if err := arena.checkCanStartMatch(); assert.NotNil(t, err) {
+		assert.Equal(
+			t,
+			"Cannot start match until all robots are connected or bypassed (station R1; the driver station for team "+
+				"254 has not connected).",
+			err.Error(),
+		)
+	}
+
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254}
+	assert.Equal(t, StationConnected, arena.AllianceStations["R1"].ConnectionState())
+	if err := arena.checkCanStartMatch(); assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start match until all robots are connected or bypassed (station R1).", err.Error())
+	}
+
+	arena.AllianceStations["R2"].Bypass = false
+	arena.AllianceStations["R1"].Bypass = true
+	if err := arena.checkCanStartMatch(); assert.NotNil(t, err) {
+		assert.Equal(t, "Cannot start match until all robots are connected or bypassed (station R2).", err.Error())
+	}
+}
+
+func TestRetryConnection(t *testing.T) {
+	arena := setupTestArena(t)
+
+	err := arena.RetryConnection("R4")
+	assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	err = arena.RetryConnection("R1")
+	assert.True(t, IsArenaErrorCode(err, NotReadyError))
+
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	if err = arena.RetryConnection("R1"); assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		assert.Contains(t, err.Error(), "No failed connection to retry for team 254")
+	}
+
+	// A failed connection can only be held for the team assigned to the station.
+	serverConn, clientConn := setupLoopbackTcpConn(t)
+	defer clientConn.Close()
+	assert.False(t, arena.holdPendingTcpConn(1114, "R1", serverConn))
+	assert.True(t, arena.holdPendingTcpConn(254, "R1", serverConn))
+	assert.Equal(t, StationAwaitingConnection, arena.AllianceStations["R1"].ConnectionState())
+
+	assert.Nil(t, arena.RetryConnection("R1"))
+	assert.Equal(t, StationConnected, arena.AllianceStations["R1"].ConnectionState())
+	assert.Equal(t, 254, arena.AllianceStations["R1"].DsConn.TeamId)
+	assert.Nil(t, arena.AllianceStations["R1"].pendingTcpConn)
+	err = arena.RetryConnection("R1")
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+	arena.AllianceStations["R1"].DsConn.close()
+}
+
+func TestAssignTeamDropsPendingConnection(t *testing.T) {
+	arena := setupTestArena(t)
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	serverConn, clientConn := setupLoopbackTcpConn(t)
+	defer clientConn.Close()
+	assert.True(t, arena.holdPendingTcpConn(254, "R1", serverConn))
+
+	// Reassigning the same team keeps the failed connection around for a retry.
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Equal(t, serverConn, arena.AllianceStations["R1"].pendingTcpConn)
+
+	assert.Nil(t, arena.assignTeam(1114, "R1"))
+	assert.Nil(t, arena.AllianceStations["R1"].pendingTcpConn)
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err := clientConn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
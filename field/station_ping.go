@@ -0,0 +1,81 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// On-demand connectivity check of a single driver station, for troubleshooting outside of the match flow.
+
+package field
+
+import (
+	"log"
+	"time"
+)
+
+const stationPingTimeoutMs = 500
+
+// Sends a single disabled control packet to the driver station in the given station and waits for its next status
+// packet. Returns whether it answered within the timeout and, if so, how long the answer took in milliseconds. The
+// driver station doesn't echo control packets, so this is the time until its next periodic status packet rather than
+// a true round-trip time. Only allowed before a match is started, since the extra packet would otherwise interleave
+// with the match control packets.
+func (arena *Arena) PingStation(station string) (answered bool, answerMs float64, err error) {
+	answered, answerMs, _, err = arena.pingStation(station)
+	return
+}
+
+// Does the work of PingStation, additionally returning the status reported by the answering packet.
+func (arena *Arena) pingStation(station string) (answered bool, answerMs float64, status dsStatusSnapshot, err error) {
+	if arena.MatchState != PreMatch {
+		return false, 0, status, newArenaError(
+			InvalidStateError, "Cannot ping a driver station while a match is in progress.",
+		)
+	}
+
+	arena.allianceStationsMutex.Lock()
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		arena.allianceStationsMutex.Unlock()
+		return false, 0, status, newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	if allianceStation.Team == nil {
+		arena.allianceStationsMutex.Unlock()
+		return false, 0, status, newArenaError(NotReadyError, "No team is assigned to station %s.", station)
+	}
+	dsConn := allianceStation.DsConn
+	if dsConn == nil {
+		arena.allianceStationsMutex.Unlock()
+		return false, 0, status, newArenaError(
+			NotReadyError, "No driver station is connected in station %s.", station,
+		)
+	}
+	dsConn.Enabled = false
+	sentTime := time.Now()
+	err = dsConn.sendControlPacket(arena)
+	answer := make(chan dsStatusSnapshot, 1)
+	if err == nil {
+		dsConn.statusPacketWaiters = append(dsConn.statusPacketWaiters, answer)
+	}
+	arena.allianceStationsMutex.Unlock()
+	arena.tracePacket("ping", dsConn, "error=%v", err != nil)
+	if err != nil {
+		return false, 0, status, err
+	}
+
+	select {
+	case status = <-answer:
+		answerMs = float64(time.Since(sentTime).Microseconds()) / 1000
+		log.Printf("Driver station for team %d in station %s answered ping in %.1f ms.", dsConn.TeamId, station,
+			answerMs)
+		return true, answerMs, status, nil
+	case <-time.After(stationPingTimeoutMs * time.Millisecond):
+	}
+
+	arena.allianceStationsMutex.Lock()
+	for i, waiter := range dsConn.statusPacketWaiters {
+		if waiter == answer {
+			dsConn.statusPacketWaiters = append(dsConn.statusPacketWaiters[:i], dsConn.statusPacketWaiters[i+1:]...)
+			break
+		}
+	}
+	arena.allianceStationsMutex.Unlock()
+	log.Printf("Driver station for team %d in station %s didn't answer ping.", dsConn.TeamId, station)
+	return false, 0, status, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPingStation(t *testing.T) {
+	arena := setupTestArena(t)
+
+	_, _, err := arena.PingStation("R4")
+	assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	_, _, err = arena.PingStation("R1")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		assert.Equal(t, "No team is assigned to station R1.", err.Error())
+	}
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	_, _, err = arena.PingStation("R1")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, NotReadyError))
+		assert.Equal(t, "No driver station is connected in station R1.", err.Error())
+	}
+
+	dsListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer dsListener.Close()
+	udpConn, err := net.Dial("udp4", dsListener.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+	dsConn := &DriverStationConnection{TeamId: 254, AllianceStation: "R1", udpConn: udpConn}
+	arena.AllianceStations["R1"].DsConn = dsConn
+
+	// A driver station that doesn't answer should be reported as not having answered once the timeout elapses.
+	answered, answerMs, err := arena.PingStation("R1")
+	assert.Nil(t, err)
+	assert.False(t, answered)
+	assert.Equal(t, 0.0, answerMs)
+	assert.Empty(t, dsConn.statusPacketWaiters)
+	packet := readLastUdpPacket(t, dsListener)
+	assert.Equal(t, byte(0), packet[3]&0x04)
+
+	// Simulate the driver station answering the ping with a status packet.
+	go func() {
+		var data [22]byte
+		dsListener.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := dsListener.Read(data[:]); err == nil {
+			time.Sleep(5 * time.Millisecond)
+			arena.handleDsUdpPacket([50]byte{4: 254 >> 8, 5: 254 & 0xff})
+		}
+	}()
+	answered, answerMs, err = arena.PingStation("R1")
+	assert.Nil(t, err)
+	assert.True(t, answered)
+	assert.GreaterOrEqual(t, answerMs, 5.0)
+	assert.Less(t, answerMs, float64(stationPingTimeoutMs))
+
+	arena.MatchState = AutoPeriod
+	_, _, err = arena.PingStation("R1")
+	assert.True(t, IsArenaErrorCode(err, InvalidStateError))
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Consistent point-in-time view of every alliance station, for displays that render all of them at once.
+
+package field
+
+import "time"
+
+// Copy of the connection, stop and link status of a single alliance station. TeamId is zero if the station is empty,
+// and the driver station fields are zero values if its driver station isn't connected.
+type StationStatus struct {
+	TeamId            int
+	ConnectionState   StationConnectionState
+	Bypass            bool
+	Estop             bool
+	Astop             bool
+	Ethernet          bool
+	DsLinked          bool
+	RadioLinked       bool
+	RobotLinked       bool
+	RobotCodeRunning  bool
+	BatteryVoltage    float64
+	DsRobotTripTimeMs int
+	MissedPacketCount int
+	LinkedSince       time.Time
+}
+
+// Returns the status of every station, keyed by station, taken together under the alliance stations mutex so that
+// none of them change partway through. The returned map is the caller's own copy.
+func (arena *Arena) AllStationStatuses() map[string]StationStatus {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	statuses := make(map[string]StationStatus, len(arena.AllianceStations))
+	for station, allianceStation := range arena.AllianceStations {
+		status := StationStatus{
+			ConnectionState: allianceStation.ConnectionState(),
+			Bypass:          allianceStation.Bypass,
+			Estop:           allianceStation.Estop,
+			Astop:           allianceStation.Astop,
+			Ethernet:        allianceStation.Ethernet,
+			LinkedSince:     allianceStation.LinkedSince,
+		}
+		if allianceStation.Team != nil {
+			status.TeamId = allianceStation.Team.Id
+		}
+		if dsConn := allianceStation.DsConn; dsConn != nil {
+			status.DsLinked = dsConn.DsLinked
+			status.RadioLinked = dsConn.RadioLinked
+			status.RobotLinked = dsConn.RobotLinked
+			status.RobotCodeRunning = dsConn.RobotCodeRunning
+			status.BatteryVoltage = dsConn.BatteryVoltage
+			status.DsRobotTripTimeMs = dsConn.DsRobotTripTimeMs
+			status.MissedPacketCount = dsConn.MissedPacketCount
+		}
+		statuses[station] = status
+	}
+	return statuses
+}
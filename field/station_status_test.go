@@ -0,0 +1,66 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAllStationStatuses(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.AllianceStations["R1"].Team = &model.Team{Id: 254}
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{
+		TeamId: 254, DsLinked: true, RobotLinked: true, BatteryVoltage: 12.5, lastPacketTime: time.Now(),
+	}
+	arena.AllianceStations["B3"].Team = &model.Team{Id: 1114}
+	arena.AllianceStations["B3"].Bypass = true
+
+	statuses := arena.AllStationStatuses()
+	assert.Equal(t, len(arena.AllianceStations), len(statuses))
+	assert.Equal(t, 254, statuses["R1"].TeamId)
+	assert.Equal(t, StationConnected, statuses["R1"].ConnectionState)
+	assert.True(t, statuses["R1"].DsLinked)
+	assert.True(t, statuses["R1"].RobotLinked)
+	assert.Equal(t, 12.5, statuses["R1"].BatteryVoltage)
+	assert.Equal(t, 1114, statuses["B3"].TeamId)
+	assert.Equal(t, StationAwaitingConnection, statuses["B3"].ConnectionState)
+	assert.True(t, statuses["B3"].Bypass)
+	assert.False(t, statuses["B3"].DsLinked)
+	assert.Equal(t, StationStatus{ConnectionState: StationEmpty}, statuses["R2"])
+
+	// The snapshot is a copy that doesn't track or affect the arena.
+	delete(statuses, "R2")
+	statuses["B3"] = StationStatus{}
+	arena.AllianceStations["R1"].DsConn.BatteryVoltage = 11.9
+	assert.Equal(t, 12.5, statuses["R1"].BatteryVoltage)
+	assert.Contains(t, arena.AllStationStatuses(), "R2")
+	assert.True(t, arena.AllStationStatuses()["B3"].Bypass)
+
+	// Snapshots taken while the arena loop is running and an operator is changing bypasses should be free of data
+	// races.
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	bypassesFinished := make(chan struct{})
+	go func() {
+		arena.runLoop(done)
+		close(finished)
+	}()
+	go func() {
+		for i := 0; i < 500; i++ {
+			arena.SetBypass("R2", i%2 == 0)
+		}
+		close(bypassesFinished)
+	}()
+	for i := 0; i < 500; i++ {
+		for _, status := range arena.AllStationStatuses() {
+			assert.LessOrEqual(t, status.BatteryVoltage, 12.5)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(done)
+	<-finished
+	<-bypassesFinished
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Sequence number tracking changes to the arena status, so that the arena loop only sends the status to clients when
+// something they display has changed.
+//
+// The version is bumped where the following change:
+//   - the match state, alliance labels, notes, start token and field reset confirmation;
+//   - each station's team, driver station connection, bypass, e-stop, a-stop, card and fault flags and Ethernet link;
+//   - each driver station's commanded mode, and its telemetry at the precision it is displayed: the link flags, the
+//     battery voltage to a tenth of a volt, the trip time, the missed packet count and the whole seconds since the
+//     robot link was lost.
+//
+// Status which is derived from other state or polled from other components is compared once per driver station
+// packet cycle instead: the start blockers, whether robots are enabled, driver station network availability, PLC
+// health, field e-stop and ArmorBlock status, and team Wi-Fi status.
+//
+// Counters which advance on every cycle, such as packet counts and downtime, don't count as changes by themselves;
+// they go out along with the next change.
+
+package field
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena-lite/network"
+	"strings"
+	"sync"
+)
+
+type statusVersionTracker struct {
+	mutex   sync.Mutex
+	version uint64
+}
+
+// The status which is compared once per cycle rather than being tracked where it changes.
+type polledStatus struct {
+	startBlockers      string
+	robotsEnabled      bool
+	dsNetworkAvailable bool
+	plcIsHealthy       bool
+	fieldEstop         bool
+	armorBlockStatuses string
+	teamWifiStatuses   [6]network.TeamWifiStatus
+}
+
+// Driver station telemetry at the precision at which it is displayed.
+type dsStatusSnapshot struct {
+	auto                  bool
+	enabled               bool
+	estop                 bool
+	dsLinked              bool
+	radioLinked           bool
+	robotLinked           bool
+	robotCodeRunning      bool
+	robotEnabled          bool
+	batteryDecivolts      int
+	dsRobotTripTimeMs     int
+	missedPacketCount     int
+	secondsSinceRobotLink int
+}
+
+// Returns the current arena status version, which increases each time the status changes.
+func (arena *Arena) StatusVersion() uint64 {
+	arena.statusVersion.mutex.Lock()
+	defer arena.statusVersion.mutex.Unlock()
+	return arena.statusVersion.version
+}
+
+// Returns true if the arena status has changed since the given version was obtained from StatusVersion.
+func (arena *Arena) StatusChanged(sinceVersion uint64) bool {
+	return arena.StatusVersion() != sinceVersion
+}
+
+// Records that the arena status has changed, so that it goes out with the next driver station packet cycle.
+func (arena *Arena) markStatusChanged() {
+	arena.statusVersion.mutex.Lock()
+	arena.statusVersion.version++
+	arena.statusVersion.mutex.Unlock()
+}
+
+// Records that the arena status has changed and sends it out straight away.
+func (arena *Arena) notifyStatusChanged() {
+	arena.markStatusChanged()
+	arena.ArenaStatusNotifier.Notify()
+}
+
+// Sends out the arena status from the arena loop if it has changed since the loop last sent it.
+func (arena *Arena) sendStatusIfChanged() {
+	arena.checkPolledStatus()
+	if version := arena.StatusVersion(); version != arena.sentStatusVersion {
+		arena.sentStatusVersion = version
+		arena.ArenaStatusNotifier.Notify()
+	}
+}
+
+// Records a status change if any of the derived or polled status differs from the last cycle.
+func (arena *Arena) checkPolledStatus() {
+	var startBlockers []string
+	for _, err := range arena.CheckCanStartMatchAll() {
+		startBlockers = append(startBlockers, err.Error())
+	}
+	status := polledStatus{
+		startBlockers:      strings.Join(startBlockers, "\n"),
+		robotsEnabled:      arena.RobotsEnabled(),
+		dsNetworkAvailable: arena.DsNetworkAvailable(),
+		plcIsHealthy:       arena.Plc.IsHealthy,
+		fieldEstop:         arena.Plc.GetFieldEstop(),
+		// Maps are printed in key order, so this is stable.
+		armorBlockStatuses: fmt.Sprint(arena.Plc.GetArmorBlockStatuses()),
+	}
+	for i := range status.teamWifiStatuses {
+		if arena.EventSettings.Ap2TeamChannel == 0 || i < 3 {
+			status.teamWifiStatuses[i] = arena.accessPoint.TeamWifiStatuses[i]
+		} else {
+			status.teamWifiStatuses[i] = arena.accessPoint2.TeamWifiStatuses[i]
+		}
+	}
+	if status != arena.lastPolledStatus {
+		arena.lastPolledStatus = status
+		arena.markStatusChanged()
+	}
+}
+
+// Returns the connection's telemetry at the precision at which it is displayed.
+func (dsConn *DriverStationConnection) statusSnapshot() dsStatusSnapshot {
+	snapshot := dsStatusSnapshot{
+		auto:              dsConn.Auto,
+		enabled:           dsConn.Enabled,
+		estop:             dsConn.Estop,
+		dsLinked:          dsConn.DsLinked,
+		radioLinked:       dsConn.RadioLinked,
+		robotLinked:       dsConn.RobotLinked,
+		robotCodeRunning:  dsConn.RobotCodeRunning,
+		robotEnabled:      dsConn.RobotEnabled,
+		batteryDecivolts:  int(dsConn.BatteryVoltage * 10),
+		dsRobotTripTimeMs: dsConn.DsRobotTripTimeMs,
+		missedPacketCount: dsConn.MissedPacketCount,
+	}
+	// The displays only show the time since the robot link was lost between one and a thousand seconds.
+	if dsConn.SecondsSinceLastRobotLink > 1 && dsConn.SecondsSinceLastRobotLink < 1000 {
+		snapshot.secondsSinceRobotLink = int(dsConn.SecondsSinceLastRobotLink)
+	}
+	return snapshot
+}
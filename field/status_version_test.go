@@ -0,0 +1,61 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatusVersion(t *testing.T) {
+	arena := setupTestArena(t)
+
+	version := arena.StatusVersion()
+	assert.False(t, arena.StatusChanged(version))
+
+	// Generating the status message shouldn't count as a change.
+	arena.generateArenaStatusMessage()
+	assert.False(t, arena.StatusChanged(version))
+
+	assert.Nil(t, arena.SetBypass("B2", true))
+	assert.True(t, arena.StatusChanged(version))
+	version = arena.StatusVersion()
+
+	// Mutations which send a status notification should also count.
+	arena.SetAllianceLabels("Gold", "Silver")
+	assert.True(t, arena.StatusChanged(version))
+	version = arena.StatusVersion()
+
+	// An e-stop request only counts when it changes the station's state.
+	arena.handleEstop("R1", true)
+	assert.True(t, arena.StatusChanged(version))
+	version = arena.StatusVersion()
+	arena.handleEstop("R1", true)
+	assert.False(t, arena.StatusChanged(version))
+
+	// Telemetry only counts when it changes at the precision it is displayed.
+	dsConn := &DriverStationConnection{BatteryVoltage: 12.51}
+	snapshot := dsConn.statusSnapshot()
+	dsConn.BatteryVoltage = 12.54
+	assert.Equal(t, snapshot, dsConn.statusSnapshot())
+	dsConn.BatteryVoltage = 12.61
+	assert.NotEqual(t, snapshot, dsConn.statusSnapshot())
+}
+
+func TestSendStatusIfChanged(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.sendStatusIfChanged()
+	sentVersion := arena.sentStatusVersion
+	assert.Equal(t, arena.StatusVersion(), sentVersion)
+
+	// Nothing has changed, so the status shouldn't be sent again.
+	arena.sendStatusIfChanged()
+	assert.Equal(t, sentVersion, arena.sentStatusVersion)
+
+	// A change to polled status, such as the start blockers, should be picked up and sent.
+	arena.AllianceStations["R1"].Bypass = true
+	arena.sendStatusIfChanged()
+	assert.NotEqual(t, sentVersion, arena.sentStatusVersion)
+	assert.Equal(t, arena.StatusVersion(), arena.sentStatusVersion)
+}
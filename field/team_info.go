@@ -0,0 +1,44 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Display-oriented summary of the team assigned to each alliance station.
+
+package field
+
+// Subset of a team's details that displays need, copied by value so that callers can't mutate the arena's state.
+type TeamInfo struct {
+	Id        int
+	Nickname  string
+	City      string
+	StateProv string
+	Country   string
+	RobotName string
+}
+
+// Returns a copy of the details of the team assigned to the given station, or a zero value if the station is empty.
+func (arena *Arena) TeamInfo(station string) (TeamInfo, error) {
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return TeamInfo{}, newArenaError(InvalidStationError, "Invalid alliance station '%s'.", station)
+	}
+	team := allianceStation.Team
+	if team == nil {
+		return TeamInfo{}, nil
+	}
+	return TeamInfo{
+		Id:        team.Id,
+		Nickname:  team.Nickname,
+		City:      team.City,
+		StateProv: team.StateProv,
+		Country:   team.Country,
+		RobotName: team.RobotName,
+	}, nil
+}
+
+// Returns the team details for every alliance station, keyed by station.
+func (arena *Arena) teamInfos() map[string]TeamInfo {
+	teamInfos := make(map[string]TeamInfo, len(arena.AllianceStations))
+	for station := range arena.AllianceStations {
+		teamInfos[station], _ = arena.TeamInfo(station)
+	}
+	return teamInfos
+}
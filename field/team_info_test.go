@@ -0,0 +1,39 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTeamInfo(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254, Nickname: "The Cheesy Poofs", City: "San Jose", StateProv: "CA",
+		Country: "USA", RobotName: "Barrage", WpaKey: "12345678"})
+	assert.Nil(t, arena.assignTeam(254, "B2"))
+
+	teamInfo, err := arena.TeamInfo("B2")
+	assert.Nil(t, err)
+	assert.Equal(t, TeamInfo{Id: 254, Nickname: "The Cheesy Poofs", City: "San Jose", StateProv: "CA",
+		Country: "USA", RobotName: "Barrage"}, teamInfo)
+
+	// Mutating the returned copy shouldn't affect the arena.
+	teamInfo.Nickname = "Changed"
+	assert.Equal(t, "The Cheesy Poofs", arena.AllianceStations["B2"].Team.Nickname)
+
+	teamInfo, err = arena.TeamInfo("R1")
+	assert.Nil(t, err)
+	assert.Equal(t, TeamInfo{}, teamInfo)
+
+	_, err = arena.TeamInfo("R4")
+	if assert.NotNil(t, err) {
+		assert.True(t, IsArenaErrorCode(err, InvalidStationError))
+	}
+
+	teamInfos := arena.teamInfos()
+	assert.Equal(t, 6, len(teamInfos))
+	assert.Equal(t, 254, teamInfos["B2"].Id)
+	assert.Equal(t, 0, teamInfos["R3"].Id)
+}
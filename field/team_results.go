@@ -0,0 +1,106 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// History of a single team's completed matches and their outcomes, for a team dashboard.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+)
+
+type MatchOutcome string
+
+const (
+	MatchWon  MatchOutcome = "W"
+	MatchLost MatchOutcome = "L"
+	MatchTied MatchOutcome = "T"
+)
+
+// A completed match that a particular team played in, with its alliance's score and the outcome from its point of
+// view. IsSurrogate is set if the team was playing as a surrogate, in which case the match doesn't count towards its
+// record.
+type TeamMatchResult struct {
+	Match         model.Match
+	Station       string
+	Alliance      int
+	IsSurrogate   bool
+	Score         int
+	OpponentScore int
+	Outcome       MatchOutcome
+}
+
+// Returns every completed match that the given team played in, in the same order as GetTeamSchedule, along with the
+// scores and whether its alliance won, lost or tied. The outcome takes any referee override into account. Surrogate
+// appearances are included but marked. Matches without a stored result are skipped.
+func (arena *Arena) GetTeamResults(teamId int) ([]TeamMatchResult, error) {
+	schedule, err := arena.GetTeamSchedule(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]TeamMatchResult, 0)
+	for _, entry := range schedule {
+		if !entry.Match.IsComplete() {
+			continue
+		}
+		matchResult, err := arena.Datastore.GetMatchResultForMatch(entry.Match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if matchResult == nil {
+			continue
+		}
+
+		result := TeamMatchResult{
+			Match:       entry.Match,
+			Station:     entry.Station,
+			Alliance:    entry.Alliance,
+			IsSurrogate: isSurrogateInStation(&entry.Match, entry.Station),
+		}
+		redScore, blueScore := matchResult.RedScoreSummary().Score, matchResult.BlueScoreSummary().Score
+		winningStatus := game.RedWonMatch
+		result.Score, result.OpponentScore = redScore, blueScore
+		if entry.Alliance == BlueAlliance {
+			winningStatus = game.BlueWonMatch
+			result.Score, result.OpponentScore = blueScore, redScore
+		}
+		switch entry.Match.OfficialStatus() {
+		case winningStatus:
+			result.Outcome = MatchWon
+		case game.TieMatch:
+			result.Outcome = MatchTied
+		default:
+			result.Outcome = MatchLost
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Tallies the given results into a win-loss-tie record, leaving out surrogate appearances.
+func TeamRecord(results []TeamMatchResult) (wins, losses, ties int) {
+	for _, result := range results {
+		if result.IsSurrogate {
+			continue
+		}
+		switch result.Outcome {
+		case MatchWon:
+			wins++
+		case MatchLost:
+			losses++
+		case MatchTied:
+			ties++
+		}
+	}
+	return
+}
+
+// Returns true if the team in the given station of the given match is playing as a surrogate.
+func isSurrogateInStation(match *model.Match, station string) bool {
+	surrogates := map[string]bool{
+		"R1": match.Red1IsSurrogate, "R2": match.Red2IsSurrogate, "R3": match.Red3IsSurrogate,
+		"B1": match.Blue1IsSurrogate, "B2": match.Blue2IsSurrogate, "B3": match.Blue3IsSurrogate,
+	}
+	return surrogates[station]
+}
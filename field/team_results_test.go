@@ -0,0 +1,83 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetTeamResults(t *testing.T) {
+	arena := setupTestArena(t)
+
+	results, err := arena.GetTeamResults(254)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+
+	createMatch := func(match model.Match, redScore, blueScore int) model.Match {
+		arena.Database.CreateMatch(&match)
+		if match.IsComplete() {
+			matchResult := model.BuildTestMatchResult(match.Id, 1)
+			matchResult.RedScore = &game.Score{TeleopPoints: redScore}
+			matchResult.BlueScore = &game.Score{TeleopPoints: blueScore}
+			arena.Database.CreateMatchResult(matchResult)
+		}
+		return match
+	}
+	createMatch(model.Match{Type: "practice", DisplayName: "1", Red1: 254, Status: game.RedWonMatch}, 50, 20)
+	createMatch(model.Match{Type: "qualification", DisplayName: "1", Blue2: 254, Status: game.RedWonMatch}, 30, 10)
+	createMatch(model.Match{Type: "qualification", DisplayName: "2", Red3: 254, Status: game.TieMatch}, 40, 40)
+	createMatch(
+		model.Match{Type: "qualification", DisplayName: "3", Blue1: 254, Blue1IsSurrogate: true,
+			Status: game.BlueWonMatch}, 15, 25,
+	)
+	overridden := createMatch(
+		model.Match{Type: "qualification", DisplayName: "4", Red2: 254, Status: game.RedWonMatch}, 60, 45,
+	)
+	createMatch(model.Match{Type: "qualification", DisplayName: "5", Red1: 254}, 0, 0)
+	createMatch(model.Match{Type: "qualification", DisplayName: "6", Red1: 1114, Status: game.RedWonMatch}, 70, 5)
+	overridden.OverrideStatus = game.BlueWonMatch
+	overridden.OverrideReason = "Red alliance was disqualified."
+	arena.Database.UpdateMatch(&overridden)
+
+	results, err = arena.GetTeamResults(254)
+	assert.Nil(t, err)
+	if assert.Equal(t, 5, len(results)) {
+		assert.Equal(t, "practice", results[0].Match.Type)
+		assert.Equal(t, "R1", results[0].Station)
+		assert.Equal(t, RedAlliance, results[0].Alliance)
+		assert.Equal(t, 50, results[0].Score)
+		assert.Equal(t, 20, results[0].OpponentScore)
+		assert.Equal(t, MatchWon, results[0].Outcome)
+
+		assert.Equal(t, "B2", results[1].Station)
+		assert.Equal(t, BlueAlliance, results[1].Alliance)
+		assert.Equal(t, 10, results[1].Score)
+		assert.Equal(t, 30, results[1].OpponentScore)
+		assert.Equal(t, MatchLost, results[1].Outcome)
+
+		assert.Equal(t, MatchTied, results[2].Outcome)
+
+		assert.Equal(t, "B1", results[3].Station)
+		assert.True(t, results[3].IsSurrogate)
+		assert.Equal(t, MatchWon, results[3].Outcome)
+		assert.False(t, results[0].IsSurrogate)
+
+		// The referee override decides the outcome even though the team's alliance had the higher score.
+		assert.Equal(t, "4", results[4].Match.DisplayName)
+		assert.Equal(t, 60, results[4].Score)
+		assert.Equal(t, MatchLost, results[4].Outcome)
+	}
+
+	// The surrogate win doesn't count towards the team's record.
+	wins, losses, ties := TeamRecord(results)
+	assert.Equal(t, 1, wins)
+	assert.Equal(t, 2, losses)
+	assert.Equal(t, 1, ties)
+
+	results, err = arena.GetTeamResults(9999)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
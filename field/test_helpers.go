@@ -7,7 +7,6 @@ package field
 
 import (
 	"fmt"
-	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/model"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
@@ -23,11 +22,15 @@ func SetupTestArena(t *testing.T, uniqueName string) *Arena {
 	os.Remove(dbPath)
 	arena, err := NewArena(dbPath)
 	assert.Nil(t, err)
+
+	// Tests run with short warmup and pause periods; they are saved so that they survive the settings being reloaded.
+	arena.EventSettings.WarmupDurationSec = 3
+	arena.EventSettings.PauseDurationSec = 2
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
 	return arena
 }
 
 func setupTestArena(t *testing.T) *Arena {
-	game.MatchTiming.WarmupDurationSec = 3
-	game.MatchTiming.PauseDurationSec = 2
 	return SetupTestArena(t, "field")
 }
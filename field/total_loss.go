@@ -0,0 +1,55 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Optional abort of a match in which every robot has lost its link at once, e.g. following a field power blip, since
+// there is no point in letting it run on.
+
+package field
+
+import (
+	"log"
+	"time"
+)
+
+// Aborts the match if it is enabled in the event settings and none of the non-bypassed robots have been linked for at
+// least the configured duration. A match with no non-bypassed robots to begin with is left alone.
+func (arena *Arena) checkTotalRobotLoss() {
+	now := arena.now()
+	if !arena.EventSettings.AutoAbortOnTotalLoss || !arena.MatchInProgress() || arena.anyRobotLinked() {
+		arena.totalLossSince = time.Time{}
+		return
+	}
+
+	if arena.totalLossSince.IsZero() {
+		arena.totalLossSince = now
+	}
+	lostMs := now.Sub(arena.totalLossSince).Milliseconds()
+	if lostMs < int64(arena.EventSettings.TotalLossAbortAfterMs) {
+		return
+	}
+	log.Printf("CRITICAL: No robots have been linked for the last %d ms; aborting match %s.", lostMs,
+		arena.CurrentMatch.DisplayName)
+	arena.raiseFault(
+		TotalRobotLossFault, CriticalFault, "", "No robots were linked for %d ms; the match was aborted.", lostMs,
+	)
+	arena.totalLossSince = time.Time{}
+	arena.AbortMatch()
+}
+
+// Returns true if any non-bypassed station with a team has a linked robot, or if there are no such stations.
+func (arena *Arena) anyRobotLinked() bool {
+	arena.allianceStationsMutex.Lock()
+	defer arena.allianceStationsMutex.Unlock()
+
+	expectedRobots := 0
+	for _, station := range arena.StationKeys() {
+		allianceStation := arena.AllianceStations[station]
+		if allianceStation.Team == nil || allianceStation.Bypass {
+			continue
+		}
+		expectedRobots++
+		if allianceStation.DsConn != nil && allianceStation.DsConn.RobotLinked {
+			return true
+		}
+	}
+	return expectedRobots == 0
+}
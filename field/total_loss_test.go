@@ -0,0 +1,66 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCheckTotalRobotLoss(t *testing.T) {
+	arena := setupTestArena(t)
+	currentTime := time.Now()
+	arena.now = func() time.Time { return currentTime }
+	step := func(ms int) {
+		currentTime = currentTime.Add(time.Duration(ms) * time.Millisecond)
+		arena.checkTotalRobotLoss()
+	}
+	arena.AllianceStations["R1"].Team = &model.Team{Id: 254}
+	arena.AllianceStations["R1"].DsConn = &DriverStationConnection{TeamId: 254}
+	arena.AllianceStations["B1"].Team = &model.Team{Id: 1114}
+	arena.AllianceStations["B1"].DsConn = &DriverStationConnection{TeamId: 1114}
+	arena.AllianceStations["B2"].Team = &model.Team{Id: 148}
+	arena.AllianceStations["B2"].Bypass = true
+	arena.MatchState = TeleopPeriod
+
+	// Nothing should happen unless it is turned on.
+	step(10000)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+
+	// A brief loss of every robot should be ridden out.
+	arena.EventSettings.AutoAbortOnTotalLoss = true
+	arena.EventSettings.TotalLossAbortAfterMs = 2000
+	step(0)
+	step(1500)
+	arena.AllianceStations["B1"].DsConn.RobotLinked = true
+	step(100)
+	arena.AllianceStations["B1"].DsConn.RobotLinked = false
+	step(100)
+	step(1500)
+	assert.Equal(t, TeleopPeriod, arena.MatchState)
+	assert.Empty(t, arena.ActiveFaults())
+
+	// A sustained loss should abort the match.
+	step(500)
+	assert.Equal(t, PostMatch, arena.MatchState)
+	assert.True(t, arena.matchAborted)
+	faults := arena.ActiveFaults()
+	if assert.Equal(t, 1, len(faults)) {
+		assert.Equal(t, TotalRobotLossFault, faults[0].Kind)
+		assert.Equal(t, CriticalFault, faults[0].Severity)
+		assert.Equal(t, "No robots were linked for 2000 ms; the match was aborted.", faults[0].Description)
+	}
+
+	// Outside of a match, or with every robot bypassed, there is nothing to lose.
+	step(5000)
+	assert.Equal(t, PostMatch, arena.MatchState)
+	arena.MatchState = AutoPeriod
+	arena.matchAborted = false
+	arena.AllianceStations["R1"].Bypass = true
+	arena.AllianceStations["B1"].Bypass = true
+	step(0)
+	step(5000)
+	assert.Equal(t, AutoPeriod, arena.MatchState)
+}
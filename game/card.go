@@ -0,0 +1,16 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Referee cards which may be assigned to a team during a match.
+
+package game
+
+const (
+	NoCard = iota
+	YellowCard
+	RedCard
+)
+
+// Returns true if the given value is one of the defined cards.
+func IsValidCard(card int) bool {
+	return card >= NoCard && card <= RedCard
+}
@@ -5,6 +5,8 @@
 
 package game
 
+import "math"
+
 type MatchSound struct {
 	Name          string
 	FileExtension string
@@ -12,12 +14,25 @@ type MatchSound struct {
 	Timeout       bool
 }
 
-// List of sounds and how many seconds into the match they are played. A negative time indicates that the sound can only
-// be triggered explicitly.
-var MatchSounds []*MatchSound
+// Returns the list of sounds for a match with the given timing and how many seconds into the match they are played. A
+// negative time indicates that the sound can only be triggered explicitly.
+func NewMatchSounds(timing Timing) []*MatchSound {
+	teleopStartSec := timing.AutoDurationSec + timing.PauseDurationSec
+	teleopEndSec := teleopStartSec + timing.TeleopDurationSec
+	resumeSec := float64(teleopStartSec)
+	// The warning can't come before teleop starts, even if the configured lead is longer than teleop itself.
+	warningSec := math.Max(float64(teleopEndSec-timing.WarningRemainingDurationSec), resumeSec)
+	endSec := float64(teleopEndSec)
+	if timing.TeleopDurationSec == 0 {
+		// Without a teleop period (e.g. for an auto-only demo) there is nothing to resume or warn about, and the sound
+		// at the end of auto also ends the match unless there is a pause after it.
+		resumeSec, warningSec = -1, -1
+		if timing.PauseDurationSec == 0 {
+			endSec = -1
+		}
+	}
 
-func UpdateMatchSounds() {
-	MatchSounds = []*MatchSound{
+	return []*MatchSound{
 		{
 			"start",
 			"wav",
@@ -27,40 +42,37 @@ func UpdateMatchSounds() {
 		{
 			"end",
 			"wav",
-			float64(MatchTiming.AutoDurationSec),
+			float64(timing.AutoDurationSec),
 			false,
 		},
 		{
 			"resume",
 			"wav",
-			float64(MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec),
+			resumeSec,
 			false,
 		},
 		{
 			"warning",
 			"wav",
-			float64(
-				MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec -
-					MatchTiming.WarningRemainingDurationSec,
-			),
+			warningSec,
 			false,
 		},
 		{
 			"end",
 			"wav",
-			float64(MatchTiming.AutoDurationSec + MatchTiming.PauseDurationSec + MatchTiming.TeleopDurationSec),
+			endSec,
 			false,
 		},
 		{
 			"timeout_warning",
 			"wav",
-			float64(MatchTiming.TimeoutDurationSec - MatchTiming.TimeoutWarningRemainingDurationSec),
+			float64(timing.TimeoutDurationSec - timing.TimeoutWarningRemainingDurationSec),
 			true,
 		},
 		{
 			"end",
 			"wav",
-			float64(MatchTiming.TimeoutDurationSec),
+			float64(timing.TimeoutDurationSec),
 			true,
 		},
 		{
@@ -7,7 +7,7 @@ package game
 
 import "time"
 
-var MatchTiming = struct {
+type Timing struct {
 	WarmupDurationSec                  int
 	AutoDurationSec                    int
 	PauseDurationSec                   int
@@ -15,18 +15,52 @@ var MatchTiming = struct {
 	WarningRemainingDurationSec        int
 	TimeoutDurationSec                 int
 	TimeoutWarningRemainingDurationSec int
-}{0, 15, 2, 135, 30, 0, 60}
+}
+
+// Timing of a match before the event settings are loaded. Each arena keeps its own copy, so this is never modified.
+var DefaultMatchTiming = Timing{0, 15, 2, 135, 30, 0, 60}
+
+// Periods of a match, as classified by PeriodAtTime.
+const (
+	PeriodBeforeMatch = iota
+	PeriodWarmup
+	PeriodAuto
+	PeriodPause
+	PeriodTeleop
+	PeriodAfterMatch
+)
+
+// Returns the period of a match with the given timing that the given number of seconds since the start of the match
+// falls into. Each period includes its start time but not its end time, and periods of zero duration are skipped.
+func PeriodAtTime(timeSec float64, timing Timing) int {
+	autoStartSec := timing.WarmupDurationSec
+	pauseStartSec := autoStartSec + timing.AutoDurationSec
+	teleopStartSec := pauseStartSec + timing.PauseDurationSec
+	teleopEndSec := teleopStartSec + timing.TeleopDurationSec
+	switch {
+	case timeSec < 0:
+		return PeriodBeforeMatch
+	case timeSec < float64(autoStartSec):
+		return PeriodWarmup
+	case timeSec < float64(pauseStartSec):
+		return PeriodAuto
+	case timeSec < float64(teleopStartSec):
+		return PeriodPause
+	case timeSec < float64(teleopEndSec):
+		return PeriodTeleop
+	}
+	return PeriodAfterMatch
+}
 
-func GetDurationToAutoEnd() time.Duration {
-	return time.Duration(MatchTiming.WarmupDurationSec+MatchTiming.AutoDurationSec) * time.Second
+func (timing Timing) GetDurationToAutoEnd() time.Duration {
+	return time.Duration(timing.WarmupDurationSec+timing.AutoDurationSec) * time.Second
 }
 
-func GetDurationToTeleopStart() time.Duration {
-	return time.Duration(MatchTiming.WarmupDurationSec+MatchTiming.AutoDurationSec+MatchTiming.PauseDurationSec) *
-		time.Second
+func (timing Timing) GetDurationToTeleopStart() time.Duration {
+	return time.Duration(timing.WarmupDurationSec+timing.AutoDurationSec+timing.PauseDurationSec) * time.Second
 }
 
-func GetDurationToTeleopEnd() time.Duration {
-	return time.Duration(MatchTiming.WarmupDurationSec+MatchTiming.AutoDurationSec+MatchTiming.PauseDurationSec+
-		MatchTiming.TeleopDurationSec) * time.Second
+func (timing Timing) GetDurationToTeleopEnd() time.Duration {
+	return time.Duration(timing.WarmupDurationSec+timing.AutoDurationSec+timing.PauseDurationSec+
+		timing.TeleopDurationSec) * time.Second
 }
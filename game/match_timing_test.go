@@ -0,0 +1,44 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPeriodAtTime(t *testing.T) {
+	timing := Timing{WarmupDurationSec: 3, AutoDurationSec: 15, PauseDurationSec: 2, TeleopDurationSec: 135}
+	for _, testCase := range []struct {
+		timeSec float64
+		period  int
+	}{
+		{-0.001, PeriodBeforeMatch},
+		{0, PeriodWarmup},
+		{2.999, PeriodWarmup},
+		{3, PeriodAuto},
+		{17.999, PeriodAuto},
+		{18, PeriodPause},
+		{19.999, PeriodPause},
+		{20, PeriodTeleop},
+		{154.999, PeriodTeleop},
+		{155, PeriodAfterMatch},
+		{1000, PeriodAfterMatch},
+	} {
+		assert.Equal(t, testCase.period, PeriodAtTime(testCase.timeSec, timing), "time %v", testCase.timeSec)
+	}
+
+	// Periods without any duration are skipped.
+	timing.WarmupDurationSec = 0
+	timing.PauseDurationSec = 0
+	assert.Equal(t, PeriodBeforeMatch, PeriodAtTime(-0.001, timing))
+	assert.Equal(t, PeriodAuto, PeriodAtTime(0, timing))
+	assert.Equal(t, PeriodAuto, PeriodAtTime(14.999, timing))
+	assert.Equal(t, PeriodTeleop, PeriodAtTime(15, timing))
+	assert.Equal(t, PeriodAfterMatch, PeriodAtTime(150, timing))
+
+	timing.TeleopDurationSec = 0
+	assert.Equal(t, PeriodAfterMatch, PeriodAtTime(15, timing))
+
+	assert.Equal(t, PeriodTeleop, PeriodAtTime(20, DefaultMatchTiming))
+}
@@ -28,24 +28,87 @@ type Ranking struct {
 
 type Rankings []Ranking
 
-func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentScore *ScoreSummary) {
+// Function used to award ranking points to an alliance for a single match, since the formula changes from game to
+// game.
+type RankingPointsFunc func(ownScore *ScoreSummary, opponentScore *ScoreSummary) int
+
+// Awards two ranking points for a win, one for a tie and none for a loss.
+func DefaultRankingPoints(ownScore *ScoreSummary, opponentScore *ScoreSummary) int {
+	if ownScore.Score > opponentScore.Score {
+		return 2
+	} else if ownScore.Score == opponentScore.Score {
+		return 1
+	}
+	return 0
+}
+
+func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentScore *ScoreSummary, disqualified bool) {
+	fields.AddScoreSummaryWithRankingPoints(ownScore, opponentScore, disqualified, DefaultRankingPoints)
+}
+
+// Like AddScoreSummary, but awards ranking points using the given formula instead of the default one.
+func (fields *RankingFields) AddScoreSummaryWithRankingPoints(
+	ownScore *ScoreSummary, opponentScore *ScoreSummary, disqualified bool, rankingPoints RankingPointsFunc,
+) {
 	fields.Played += 1
 
 	// Store a random value to be used as the last tiebreaker if necessary.
 	fields.Random = rand.Float64()
 
+	if disqualified {
+		// Don't award any points to a team that received a red card.
+		return
+	}
+
 	// Assign ranking points and wins/losses/ties.
+	fields.RankingPoints += rankingPoints(ownScore, opponentScore)
 	if ownScore.Score > opponentScore.Score {
-		fields.RankingPoints += 2
 		fields.Wins += 1
 	} else if ownScore.Score == opponentScore.Score {
-		fields.RankingPoints += 1
 		fields.Ties += 1
 	} else {
 		fields.Losses += 1
 	}
 
-	// Assign tiebreaker points.
+	fields.addTiebreakerPoints(ownScore)
+}
+
+// Like AddScoreSummaryWithRankingPoints, but for a match whose result has been overridden by the head referee to the
+// given status. The win, loss or tie comes from the override instead of the scores. The ranking points come from the
+// given formula with the opponent's score moved just far enough to produce the overridden result, so that any points
+// the formula awards for the alliance's own score are kept. Tiebreakers still use the scores.
+func (fields *RankingFields) AddOverriddenScoreSummary(
+	ownScore *ScoreSummary, opponentScore *ScoreSummary, isRed bool, overrideStatus MatchStatus, disqualified bool,
+	rankingPoints RankingPointsFunc,
+) {
+	fields.Played += 1
+	fields.Random = rand.Float64()
+
+	if disqualified {
+		return
+	}
+
+	overriddenOpponentScore := *opponentScore
+	if overrideStatus == TieMatch {
+		overriddenOpponentScore.Score = ownScore.Score
+		fields.Ties += 1
+	} else if (overrideStatus == RedWonMatch) == isRed {
+		if overriddenOpponentScore.Score >= ownScore.Score {
+			overriddenOpponentScore.Score = ownScore.Score - 1
+		}
+		fields.Wins += 1
+	} else {
+		if overriddenOpponentScore.Score <= ownScore.Score {
+			overriddenOpponentScore.Score = ownScore.Score + 1
+		}
+		fields.Losses += 1
+	}
+	fields.RankingPoints += rankingPoints(ownScore, &overriddenOpponentScore)
+
+	fields.addTiebreakerPoints(ownScore)
+}
+
+func (fields *RankingFields) addTiebreakerPoints(ownScore *ScoreSummary) {
 	fields.AutoPoints += ownScore.AutoPoints
 	fields.EndgamePoints += ownScore.EndgamePoints
 	fields.TeleopPoints += ownScore.TeleopPoints
@@ -66,6 +129,9 @@ func (rankings Rankings) Less(i, j int) bool {
 		if a.AutoPoints*b.Played == b.AutoPoints*a.Played {
 			if a.EndgamePoints*b.Played == b.EndgamePoints*a.Played {
 				if a.TeleopPoints*b.Played == b.TeleopPoints*a.Played {
+					if a.Random == b.Random {
+						return a.TeamId < b.TeamId
+					}
 					return a.Random > b.Random
 				}
 				return a.TeleopPoints*b.Played > b.TeleopPoints*a.Played
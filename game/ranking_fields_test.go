@@ -19,16 +19,65 @@ func TestAddScoreSummary(t *testing.T) {
 	rankingFields := RankingFields{}
 
 	// Add a loss.
-	rankingFields.AddScoreSummary(redSummary, blueSummary)
+	rankingFields.AddScoreSummary(redSummary, blueSummary, false)
 	assert.Equal(t, RankingFields{2, 45, 30, 80, 0.9451961492941164, 1, 0, 0, 1}, rankingFields)
 
 	// Add a win.
-	rankingFields.AddScoreSummary(blueSummary, redSummary)
+	rankingFields.AddScoreSummary(blueSummary, redSummary, false)
 	assert.Equal(t, RankingFields{2, 60, 55, 120, 0.24496508529377975, 1, 1, 0, 2}, rankingFields)
 
 	// Add a tie.
-	rankingFields.AddScoreSummary(redSummary, redSummary)
+	rankingFields.AddScoreSummary(redSummary, redSummary, false)
 	assert.Equal(t, RankingFields{3, 105, 85, 200, 0.6559562651954052, 1, 1, 1, 3}, rankingFields)
+
+	// Add a disqualification, which should count as played but award nothing.
+	rankingFields.AddScoreSummary(blueSummary, redSummary, true)
+	rankingFields.Random = 0
+	assert.Equal(t, RankingFields{3, 105, 85, 200, 0, 1, 1, 1, 4}, rankingFields)
+}
+
+func TestAddOverriddenScoreSummary(t *testing.T) {
+	redSummary := TestScore1().Summarize()
+	blueSummary := TestScore2().Summarize()
+	rankingFields := RankingFields{}
+
+	// The red alliance lost on the scores but was awarded the win.
+	rankingFields.AddOverriddenScoreSummary(redSummary, blueSummary, true, RedWonMatch, false, DefaultRankingPoints)
+	rankingFields.Random = 0
+	assert.Equal(t, RankingFields{2, 45, 30, 80, 0, 1, 0, 0, 1}, rankingFields)
+
+	// The blue alliance won on the scores but had the win taken away.
+	rankingFields.AddOverriddenScoreSummary(blueSummary, redSummary, false, RedWonMatch, false, DefaultRankingPoints)
+	rankingFields.Random = 0
+	assert.Equal(t, RankingFields{2, 60, 55, 120, 0, 1, 1, 0, 2}, rankingFields)
+
+	rankingFields.AddOverriddenScoreSummary(blueSummary, redSummary, false, TieMatch, false, DefaultRankingPoints)
+	rankingFields.Random = 0
+	assert.Equal(t, RankingFields{3, 75, 80, 160, 0, 1, 1, 1, 3}, rankingFields)
+
+	rankingFields.AddOverriddenScoreSummary(redSummary, blueSummary, true, RedWonMatch, true, DefaultRankingPoints)
+	rankingFields.Random = 0
+	assert.Equal(t, RankingFields{3, 75, 80, 160, 0, 1, 1, 1, 4}, rankingFields)
+
+	// The ranking points come from the given formula, judging the overridden result.
+	winBonus := func(ownScore, opponentScore *ScoreSummary) int {
+		points := ownScore.Score / 100
+		if ownScore.Score > opponentScore.Score {
+			points += 3
+		}
+		return points
+	}
+	rankingFields = RankingFields{}
+	rankingFields.AddOverriddenScoreSummary(redSummary, blueSummary, true, RedWonMatch, false, winBonus)
+	assert.Equal(t, 3+redSummary.Score/100, rankingFields.RankingPoints)
+	rankingFields.AddOverriddenScoreSummary(blueSummary, redSummary, false, RedWonMatch, false, winBonus)
+	assert.Equal(t, 3+redSummary.Score/100+blueSummary.Score/100, rankingFields.RankingPoints)
+}
+
+func TestDefaultRankingPoints(t *testing.T) {
+	assert.Equal(t, 2, DefaultRankingPoints(&ScoreSummary{Score: 10}, &ScoreSummary{Score: 5}))
+	assert.Equal(t, 1, DefaultRankingPoints(&ScoreSummary{Score: 5}, &ScoreSummary{Score: 5}))
+	assert.Equal(t, 0, DefaultRankingPoints(&ScoreSummary{Score: 5}, &ScoreSummary{Score: 10}))
 }
 
 func TestSortRankings(t *testing.T) {
@@ -65,4 +114,14 @@ func TestSortRankings(t *testing.T) {
 	assert.Equal(t, 2, rankings[0].TeamId)
 	assert.Equal(t, 3, rankings[1].TeamId)
 	assert.Equal(t, 1, rankings[2].TeamId)
+
+	// Check that teams tied on every field including the random draw are ordered by team number.
+	rankings = make(Rankings, 3)
+	rankings[0] = Ranking{9, 0, 0, RankingFields{10, 25, 25, 25, 0, 3, 2, 1, 5}}
+	rankings[1] = Ranking{3, 0, 0, RankingFields{10, 25, 25, 25, 0, 3, 2, 1, 5}}
+	rankings[2] = Ranking{6, 0, 0, RankingFields{10, 25, 25, 25, 0, 3, 2, 1, 5}}
+	sort.Sort(rankings)
+	assert.Equal(t, 3, rankings[0].TeamId)
+	assert.Equal(t, 6, rankings[1].TeamId)
+	assert.Equal(t, 9, rankings[2].TeamId)
 }
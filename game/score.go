@@ -5,6 +5,8 @@
 
 package game
 
+import "fmt"
+
 type Score struct {
 	AutoPoints    int
 	TeleopPoints  int
@@ -23,6 +25,21 @@ func (score *Score) Summarize() *ScoreSummary {
 	return summary
 }
 
+// Adds the given number of points to the named component of the score ("auto", "teleop" or "endgame").
+func (score *Score) AddPoints(component string, points int) error {
+	switch component {
+	case "auto":
+		score.AutoPoints += points
+	case "teleop":
+		score.TeleopPoints += points
+	case "endgame":
+		score.EndgamePoints += points
+	default:
+		return fmt.Errorf("Invalid score component '%s'.", component)
+	}
+	return nil
+}
+
 // Returns true if and only if all fields of the two scores are equal.
 func (score *Score) Equals(other *Score) bool {
 	if score.AutoPoints != other.AutoPoints ||
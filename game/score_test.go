@@ -48,3 +48,19 @@ func TestScoreEquals(t *testing.T) {
 	assert.False(t, score1.Equals(score2))
 	assert.False(t, score2.Equals(score1))
 }
+
+func TestScoreAddPoints(t *testing.T) {
+	score := new(Score)
+	assert.Nil(t, score.AddPoints("auto", 5))
+	assert.Nil(t, score.AddPoints("teleop", 3))
+	assert.Nil(t, score.AddPoints("teleop", 4))
+	assert.Nil(t, score.AddPoints("endgame", 12))
+	assert.Nil(t, score.AddPoints("auto", -2))
+	assert.Equal(t, Score{AutoPoints: 3, TeleopPoints: 7, EndgamePoints: 12}, *score)
+
+	err := score.AddPoints("foul", 5)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid score component 'foul'")
+	}
+	assert.Equal(t, Score{AutoPoints: 3, TeleopPoints: 7, EndgamePoints: 12}, *score)
+}
@@ -21,6 +21,9 @@ func main() {
 	if err != nil {
 		log.Fatalln("Error during startup: ", err)
 	}
+	if err = arena.RecoverInProgressMatch(); err != nil {
+		log.Printf("Failed to recover in-progress match: %v", err)
+	}
 
 	// Start the web server in a separate goroutine.
 	web := web.NewWeb(arena)
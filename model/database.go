@@ -28,6 +28,7 @@ type Database struct {
 	eventSettingsTable *table[EventSettings]
 	lowerThirdTable    *table[LowerThird]
 	matchTable         *table[Match]
+	matchProgressTable *table[MatchProgress]
 	matchResultTable   *table[MatchResult]
 	rankingTable       *table[game.Ranking]
 	scheduleBlockTable *table[ScheduleBlock]
@@ -61,6 +62,9 @@ func OpenDatabase(filename string) (*Database, error) {
 	if database.matchTable, err = newTable[Match](&database); err != nil {
 		return nil, err
 	}
+	if database.matchProgressTable, err = newTable[MatchProgress](&database); err != nil {
+		return nil, err
+	}
 	if database.matchResultTable, err = newTable[MatchResult](&database); err != nil {
 		return nil, err
 	}
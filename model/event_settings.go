@@ -5,40 +5,63 @@
 
 package model
 
-import "github.com/Team254/cheesy-arena-lite/game"
+import (
+	"encoding/json"
+	"github.com/Team254/cheesy-arena-lite/game"
+)
 
 type EventSettings struct {
-	Id                          int `db:"id"`
-	Name                        string
-	ElimType                    string
-	NumElimAlliances            int
-	SelectionRound2Order        string
-	SelectionRound3Order        string
-	TBADownloadEnabled          bool
-	TbaPublishingEnabled        bool
-	TbaEventCode                string
-	TbaSecretId                 string
-	TbaSecret                   string
-	NetworkSecurityEnabled      bool
-	ApAddress                   string
-	ApUsername                  string
-	ApPassword                  string
-	ApTeamChannel               int
-	ApAdminChannel              int
-	ApAdminWpaKey               string
-	Ap2Address                  string
-	Ap2Username                 string
-	Ap2Password                 string
-	Ap2TeamChannel              int
-	SwitchAddress               string
-	SwitchPassword              string
-	PlcAddress                  string
-	AdminPassword               string
-	WarmupDurationSec           int
-	AutoDurationSec             int
-	PauseDurationSec            int
-	TeleopDurationSec           int
-	WarningRemainingDurationSec int
+	Id                           int `db:"id"`
+	Name                         string
+	ElimType                     string
+	NumElimAlliances             int
+	SelectionRound2Order         string
+	SelectionRound3Order         string
+	TBADownloadEnabled           bool
+	TbaPublishingEnabled         bool
+	TbaEventCode                 string
+	TbaSecretId                  string
+	TbaSecret                    string
+	NetworkSecurityEnabled       bool
+	ApAddress                    string
+	ApUsername                   string
+	ApPassword                   string
+	ApTeamChannel                int
+	ApAdminChannel               int
+	ApAdminWpaKey                string
+	Ap2Address                   string
+	Ap2Username                  string
+	Ap2Password                  string
+	Ap2TeamChannel               int
+	SwitchAddress                string
+	SwitchPassword               string
+	PlcAddress                   string
+	AdminPassword                string
+	WarmupDurationSec            int
+	AutoDurationSec              int
+	PauseDurationSec             int
+	TeleopDurationSec            int
+	WarningRemainingDurationSec  int
+	AutoEstopLatchesThroughMatch bool
+	MinLinkStableSec             int
+	MinRobotsToStartMatch        int
+	AutoBypassAfterSec           int
+	RequireRobotCodeToStart      bool
+	DsPacketSpacingMs            int
+	DsConnectRetries             int
+	DsConnectBackoffMs           int
+	EnforceEventRoster           bool
+	EventRoster                  []int
+	EnableDelayMs                int
+	LoopOverrunEstopThresholdMs  int
+	LoopOverrunEstopWindowMs     int
+	RequireReplayToRestart       bool
+	RequireFieldResetConfirm     bool
+	BatteryEmaFactor             float64
+	AutoAbortOnTotalLoss         bool
+	TotalLossAbortAfterMs        int
+	RankingMatchTypes            []string
+	ManualScoring                bool
 }
 
 func (database *Database) GetEventSettings() (*EventSettings, error) {
@@ -51,7 +74,31 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 	}
 
 	// Database record doesn't exist yet; create it now.
-	eventSettings := EventSettings{
+	eventSettings := newDefaultEventSettings()
+	if err := database.eventSettingsTable.create(&eventSettings); err != nil {
+		return nil, err
+	}
+	return &eventSettings, nil
+}
+
+func (database *Database) UpdateEventSettings(eventSettings *EventSettings) error {
+	return database.eventSettingsTable.update(eventSettings)
+}
+
+// Decodes a stored settings record on top of the defaults, so that settings added since the record was last saved
+// take their default values rather than the zero value.
+func (eventSettings *EventSettings) UnmarshalJSON(data []byte) error {
+	type storedEventSettings EventSettings
+	settings := storedEventSettings(newDefaultEventSettings())
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	*eventSettings = EventSettings(settings)
+	return nil
+}
+
+func newDefaultEventSettings() EventSettings {
+	return EventSettings{
 		Name:                        "Untitled Event",
 		ElimType:                    "single",
 		NumElimAlliances:            8,
@@ -62,19 +109,50 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 		ApAdminChannel:              0,
 		ApAdminWpaKey:               "1234Five",
 		Ap2TeamChannel:              0,
-		WarmupDurationSec:           game.MatchTiming.WarmupDurationSec,
-		AutoDurationSec:             game.MatchTiming.AutoDurationSec,
-		PauseDurationSec:            game.MatchTiming.PauseDurationSec,
-		TeleopDurationSec:           game.MatchTiming.TeleopDurationSec,
-		WarningRemainingDurationSec: game.MatchTiming.WarningRemainingDurationSec,
+		WarmupDurationSec:           game.DefaultMatchTiming.WarmupDurationSec,
+		AutoDurationSec:             game.DefaultMatchTiming.AutoDurationSec,
+		PauseDurationSec:            game.DefaultMatchTiming.PauseDurationSec,
+		TeleopDurationSec:           game.DefaultMatchTiming.TeleopDurationSec,
+		WarningRemainingDurationSec: game.DefaultMatchTiming.WarningRemainingDurationSec,
+		MinRobotsToStartMatch:       1,
+		RequireRobotCodeToStart:     true,
+		DsConnectRetries:            3,
+		DsConnectBackoffMs:          50,
+		LoopOverrunEstopThresholdMs: 100,
+		LoopOverrunEstopWindowMs:    2000,
+		BatteryEmaFactor:            0.2,
+		TotalLossAbortAfterMs:       3000,
+		RankingMatchTypes:           []string{"qualification"},
 	}
+}
 
-	if err := database.eventSettingsTable.create(&eventSettings); err != nil {
-		return nil, err
+// Returns the longest time in milliseconds that opening a driver station connection can spend waiting between
+// attempts before giving up, given that the wait doubles after each failed attempt.
+func (eventSettings *EventSettings) DsConnectMaxWaitMs() int {
+	waitMs, backoffMs := 0, eventSettings.DsConnectBackoffMs
+	for retry := 0; retry < eventSettings.DsConnectRetries; retry++ {
+		waitMs += backoffMs
+		backoffMs *= 2
 	}
-	return &eventSettings, nil
+	return waitMs
 }
 
-func (database *Database) UpdateEventSettings(eventSettings *EventSettings) error {
-	return database.eventSettingsTable.update(eventSettings)
+// Returns the types of matches whose results count toward the rankings, ignoring any repeated types so that no match
+// is counted twice. Qualification matches are used if none are configured.
+func (eventSettings *EventSettings) GetRankingMatchTypes() []string {
+	var matchTypes []string
+	for _, matchType := range eventSettings.RankingMatchTypes {
+		if !containsMatchType(matchTypes, matchType) {
+			matchTypes = append(matchTypes, matchType)
+		}
+	}
+	if len(matchTypes) == 0 {
+		return []string{"qualification"}
+	}
+	return matchTypes
+}
+
+// Returns true if the given match type is configured to count toward the rankings.
+func (eventSettings *EventSettings) CountsTowardRankings(matchType string) bool {
+	return containsMatchType(eventSettings.GetRankingMatchTypes(), matchType)
 }
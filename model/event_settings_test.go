@@ -5,6 +5,7 @@ package model
 
 import (
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
 	"testing"
 )
 
@@ -32,6 +33,15 @@ func TestEventSettingsReadWrite(t *testing.T) {
 			PauseDurationSec:            2,
 			TeleopDurationSec:           135,
 			WarningRemainingDurationSec: 30,
+			MinRobotsToStartMatch:       1,
+			RequireRobotCodeToStart:     true,
+			DsConnectRetries:            3,
+			DsConnectBackoffMs:          50,
+			LoopOverrunEstopThresholdMs: 100,
+			LoopOverrunEstopWindowMs:    2000,
+			BatteryEmaFactor:            0.2,
+			TotalLossAbortAfterMs:       3000,
+			RankingMatchTypes:           []string{"qualification"},
 		},
 		*eventSettings,
 	)
@@ -46,3 +56,43 @@ func TestEventSettingsReadWrite(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, eventSettings, eventSettings2)
 }
+
+func TestEventSettingsDefaultsForMissingFields(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	// Simulate a record saved before the newer settings existed.
+	_, err := db.GetEventSettings()
+	assert.Nil(t, err)
+	err = db.bolt.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(db.eventSettingsTable.bucketKey).Put(
+			idToKey(1), []byte(`{"Id":1,"Name":"Chezy Champs","MinLinkStableSec":0}`),
+		)
+	})
+	assert.Nil(t, err)
+
+	eventSettings, err := db.GetEventSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, "Chezy Champs", eventSettings.Name)
+	assert.Equal(t, 1, eventSettings.MinRobotsToStartMatch)
+	assert.True(t, eventSettings.RequireRobotCodeToStart)
+	assert.Equal(t, 3, eventSettings.DsConnectRetries)
+	assert.Equal(t, 50, eventSettings.DsConnectBackoffMs)
+	assert.Equal(t, 350, eventSettings.DsConnectMaxWaitMs())
+	assert.True(t, eventSettings.CountsTowardRankings("qualification"))
+	assert.False(t, eventSettings.CountsTowardRankings("practice"))
+
+	// Repeated ranking match types are only kept once, and an empty list falls back to qualification matches.
+	eventSettings.RankingMatchTypes = []string{"qualification", "practice", "qualification"}
+	assert.Equal(t, []string{"qualification", "practice"}, eventSettings.GetRankingMatchTypes())
+	eventSettings.RankingMatchTypes = nil
+	assert.Equal(t, []string{"qualification"}, eventSettings.GetRankingMatchTypes())
+	assert.True(t, eventSettings.CountsTowardRankings("qualification"))
+
+	// A value explicitly saved as zero should be kept.
+	eventSettings.MinRobotsToStartMatch = 0
+	assert.Nil(t, db.UpdateEventSettings(eventSettings))
+	eventSettings, err = db.GetEventSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, eventSettings.MinRobotsToStartMatch)
+}
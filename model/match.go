@@ -37,6 +37,13 @@ type Match struct {
 	StartedAt        time.Time
 	ScoreCommittedAt time.Time
 	Status           game.MatchStatus
+	TeamsSubstituted bool
+	Notes            string
+	OverrideStatus   game.MatchStatus
+	OverrideReason   string
+
+	// Teams originally scheduled in R1 through B3 order, recorded before a substitution first changes the lineup.
+	ScheduledTeams []int
 }
 
 func (database *Database) CreateMatch(match *Match) error {
@@ -120,6 +127,24 @@ func (match *Match) IsComplete() bool {
 	return match.Status != game.MatchNotPlayed
 }
 
+// Returns true if this is an unscheduled test match, whose results are never recorded.
+func (match *Match) IsTest() bool {
+	return match.Type == "test"
+}
+
+// Returns true if the head referee has overridden the computed result of the match.
+func (match *Match) IsResultOverridden() bool {
+	return match.OverrideStatus != game.MatchNotPlayed
+}
+
+// Returns the official result of the match, which is the override if there is one and the computed result otherwise.
+func (match *Match) OfficialStatus() game.MatchStatus {
+	if match.IsResultOverridden() {
+		return match.OverrideStatus
+	}
+	return match.Status
+}
+
 func (match *Match) CapitalizedType() string {
 	if match.Type == "" || match.Type == "test" {
 		return ""
@@ -148,12 +173,23 @@ func (match *Match) ShouldUpdateCards() bool {
 	return match.Type == "qualification" || match.Type == "elimination"
 }
 
-// Returns true if the rankings should be updated as a result of the match.
-func (match *Match) ShouldUpdateRankings() bool {
-	return match.Type == "qualification"
+// Returns true if the rankings should be updated as a result of the match, given the types of matches that count
+// toward them.
+func (match *Match) ShouldUpdateRankings(rankingMatchTypes []string) bool {
+	return containsMatchType(rankingMatchTypes, match.Type)
 }
 
 // Returns true if the elimination match set should be updated as a result of the match.
 func (match *Match) ShouldUpdateEliminationMatches() bool {
 	return match.Type == "elimination"
 }
+
+// Returns true if the given list of match types includes the given type.
+func containsMatchType(matchTypes []string, matchType string) bool {
+	for _, existingMatchType := range matchTypes {
+		if existingMatchType == matchType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Model and datastore read/write methods for the progress of the match currently being played, used to recover from a
+// crash of the arena process.
+
+package model
+
+import "time"
+
+type MatchProgress struct {
+	Id             int `db:"id"`
+	MatchId        int
+	MatchState     int
+	MatchStartTime time.Time
+	MatchAborted   bool
+}
+
+// Returns the saved progress of the in-flight match, or nil if there is none.
+func (database *Database) GetMatchProgress() (*MatchProgress, error) {
+	allMatchProgress, err := database.matchProgressTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(allMatchProgress) == 0 {
+		return nil, nil
+	}
+	return &allMatchProgress[0], nil
+}
+
+// Saves the given match progress, replacing any that was previously saved.
+func (database *Database) SaveMatchProgress(matchProgress *MatchProgress) error {
+	existingMatchProgress, err := database.GetMatchProgress()
+	if err != nil {
+		return err
+	}
+	if existingMatchProgress == nil {
+		return database.matchProgressTable.create(matchProgress)
+	}
+	matchProgress.Id = existingMatchProgress.Id
+	return database.matchProgressTable.update(matchProgress)
+}
+
+func (database *Database) ClearMatchProgress() error {
+	return database.matchProgressTable.truncate()
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMatchProgressReadWrite(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	matchProgress, err := db.GetMatchProgress()
+	assert.Nil(t, err)
+	assert.Nil(t, matchProgress)
+
+	startTime := time.Unix(1234, 0).UTC()
+	assert.Nil(t, db.SaveMatchProgress(&MatchProgress{MatchId: 12, MatchState: 3, MatchStartTime: startTime}))
+	matchProgress, err = db.GetMatchProgress()
+	assert.Nil(t, err)
+	if assert.NotNil(t, matchProgress) {
+		assert.Equal(t, 12, matchProgress.MatchId)
+		assert.Equal(t, 3, matchProgress.MatchState)
+		assert.Equal(t, startTime, matchProgress.MatchStartTime.UTC())
+	}
+
+	// Check that saving again replaces the existing record rather than adding another.
+	assert.Nil(
+		t, db.SaveMatchProgress(&MatchProgress{MatchId: 12, MatchState: 5, MatchStartTime: startTime, MatchAborted: true}),
+	)
+	allMatchProgress, err := db.matchProgressTable.getAll()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(allMatchProgress)) {
+		assert.Equal(t, 5, allMatchProgress[0].MatchState)
+		assert.True(t, allMatchProgress[0].MatchAborted)
+	}
+
+	assert.Nil(t, db.ClearMatchProgress())
+	matchProgress, err = db.GetMatchProgress()
+	assert.Nil(t, err)
+	assert.Nil(t, matchProgress)
+}
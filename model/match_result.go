@@ -6,7 +6,9 @@
 package model
 
 import (
+	"fmt"
 	"github.com/Team254/cheesy-arena-lite/game"
+	"strconv"
 )
 
 type MatchResult struct {
@@ -16,6 +18,8 @@ type MatchResult struct {
 	MatchType  string
 	RedScore   *game.Score
 	BlueScore  *game.Score
+	RedCards   map[string]int
+	BlueCards  map[string]int
 }
 
 // Returns a new match result object with empty slices instead of nil.
@@ -23,10 +27,15 @@ func NewMatchResult() *MatchResult {
 	matchResult := new(MatchResult)
 	matchResult.RedScore = new(game.Score)
 	matchResult.BlueScore = new(game.Score)
+	matchResult.RedCards = make(map[string]int)
+	matchResult.BlueCards = make(map[string]int)
 	return matchResult
 }
 
 func (database *Database) CreateMatchResult(matchResult *MatchResult) error {
+	if matchResult.MatchType == "test" {
+		return fmt.Errorf("can't save the result of a test match")
+	}
 	return database.matchResultTable.create(matchResult)
 }
 
@@ -47,6 +56,9 @@ func (database *Database) GetMatchResultForMatch(matchId int) (*MatchResult, err
 }
 
 func (database *Database) UpdateMatchResult(matchResult *MatchResult) error {
+	if matchResult.MatchType == "test" {
+		return fmt.Errorf("can't save the result of a test match")
+	}
 	return database.matchResultTable.update(matchResult)
 }
 
@@ -67,3 +79,25 @@ func (matchResult *MatchResult) RedScoreSummary() *game.ScoreSummary {
 func (matchResult *MatchResult) BlueScoreSummary() *game.ScoreSummary {
 	return matchResult.BlueScore.Summarize()
 }
+
+// Returns the card assigned to the given team on the given alliance in this match.
+func (matchResult *MatchResult) TeamCard(teamId int, isRed bool) int {
+	if isRed {
+		return matchResult.RedCards[strconv.Itoa(teamId)]
+	}
+	return matchResult.BlueCards[strconv.Itoa(teamId)]
+}
+
+// Returns true if any team on the given alliance received a red card in this match.
+func (matchResult *MatchResult) HasRedCard(isRed bool) bool {
+	cards := matchResult.BlueCards
+	if isRed {
+		cards = matchResult.RedCards
+	}
+	for _, card := range cards {
+		if card == game.RedCard {
+			return true
+		}
+	}
+	return false
+}
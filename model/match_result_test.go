@@ -39,6 +39,19 @@ func TestMatchResultCrud(t *testing.T) {
 	assert.Nil(t, matchResult2)
 }
 
+func TestTestMatchResultNotSaved(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	matchResult := BuildTestMatchResult(0, 1)
+	matchResult.MatchType = "test"
+	assert.NotNil(t, db.CreateMatchResult(matchResult))
+	assert.NotNil(t, db.UpdateMatchResult(matchResult))
+	matchResults, err := db.matchResultTable.getAll()
+	assert.Nil(t, err)
+	assert.Empty(t, matchResults)
+}
+
 func TestTruncateMatchResults(t *testing.T) {
 	db := setupTestDb(t)
 	defer db.Close()
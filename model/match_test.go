@@ -24,7 +24,7 @@ func TestMatchCrud(t *testing.T) {
 	defer db.Close()
 
 	match := Match{0, "qualification", "254", time.Now().UTC(), 0, 0, 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed}
+		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed, false, "", "", "", nil}
 	db.CreateMatch(&match)
 	match2, err := db.GetMatchById(1)
 	assert.Nil(t, err)
@@ -50,7 +50,7 @@ func TestTruncateMatches(t *testing.T) {
 	defer db.Close()
 
 	match := Match{0, "qualification", "254", time.Now().UTC(), 0, 0, 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed}
+		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed, false, "", "", "", nil}
 	db.CreateMatch(&match)
 	db.TruncateMatches()
 	match2, err := db.GetMatchById(1)
@@ -93,13 +93,13 @@ func TestGetMatchesByType(t *testing.T) {
 	defer db.Close()
 
 	match := Match{0, "qualification", "1", time.Now().UTC(), 0, 0, 0, 0, 0, 1, false, 2, false, 3, false, 4, false,
-		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed}
+		5, false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed, false, "", "", "", nil}
 	db.CreateMatch(&match)
 	match2 := Match{0, "practice", "1", time.Now().UTC(), 0, 0, 0, 0, 0, 1, false, 2, false, 3, false, 4, false, 5,
-		false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed}
+		false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed, false, "", "", "", nil}
 	db.CreateMatch(&match2)
 	match3 := Match{0, "practice", "2", time.Now().UTC(), 0, 0, 0, 0, 0, 1, false, 2, false, 3, false, 4, false, 5,
-		false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed}
+		false, 6, false, time.Now().UTC(), time.Now().UTC(), game.MatchNotPlayed, false, "", "", "", nil}
 	db.CreateMatch(&match3)
 
 	matches, err := db.GetMatchesByType("test")
@@ -112,3 +112,25 @@ func TestGetMatchesByType(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(matches))
 }
+
+func TestMatchOfficialStatus(t *testing.T) {
+	match := Match{Status: game.BlueWonMatch}
+	assert.False(t, match.IsResultOverridden())
+	assert.Equal(t, game.BlueWonMatch, match.OfficialStatus())
+
+	match.OverrideStatus = game.TieMatch
+	assert.True(t, match.IsResultOverridden())
+	assert.Equal(t, game.TieMatch, match.OfficialStatus())
+	assert.Equal(t, game.BlueWonMatch, match.Status)
+}
+
+func TestShouldUpdateRankings(t *testing.T) {
+	rankingMatchTypes := []string{"qualification"}
+	assert.True(t, (&Match{Type: "qualification"}).ShouldUpdateRankings(rankingMatchTypes))
+	assert.False(t, (&Match{Type: "practice"}).ShouldUpdateRankings(rankingMatchTypes))
+
+	rankingMatchTypes = []string{"practice", "elimination"}
+	assert.False(t, (&Match{Type: "qualification"}).ShouldUpdateRankings(rankingMatchTypes))
+	assert.True(t, (&Match{Type: "practice"}).ShouldUpdateRankings(rankingMatchTypes))
+	assert.True(t, (&Match{Type: "elimination"}).ShouldUpdateRankings(rankingMatchTypes))
+}
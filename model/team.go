@@ -5,7 +5,10 @@
 
 package model
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+)
 
 type Team struct {
 	Id              int `db:"id,manual"`
@@ -22,6 +25,18 @@ type Team struct {
 	FtaNotes        string
 }
 
+// Single entry of a team roster being imported in bulk.
+type TeamImportRow struct {
+	Id         int
+	Name       string
+	Nickname   string
+	City       string
+	StateProv  string
+	Country    string
+	RookieYear int
+	RobotName  string
+}
+
 func (database *Database) CreateTeam(team *Team) error {
 	return database.teamTable.create(team)
 }
@@ -52,3 +67,61 @@ func (database *Database) GetAllTeams() ([]Team, error) {
 	})
 	return teams, nil
 }
+
+// Creates or updates a team for each of the given roster rows, skipping over any invalid rows and returning the number
+// of rows imported along with an error for each row that was rejected. Event-specific fields of existing teams (e.g.
+// WPA key and FTA notes) are preserved. If dryRun is true, the rows are validated but nothing is written.
+func (database *Database) ImportTeams(rows []TeamImportRow, dryRun bool) (int, []error) {
+	imported := 0
+	var errs []error
+	seenIds := make(map[int]int)
+	for i, row := range rows {
+		rowNumber := i + 1
+		if row.Id <= 0 {
+			errs = append(errs, fmt.Errorf("Row %d: team number must be positive; got %d.", rowNumber, row.Id))
+			continue
+		}
+		if row.Nickname == "" {
+			errs = append(errs, fmt.Errorf("Row %d: team %d is missing a nickname.", rowNumber, row.Id))
+			continue
+		}
+		if previousRowNumber, ok := seenIds[row.Id]; ok {
+			errs = append(
+				errs, fmt.Errorf("Row %d: team %d is a duplicate of row %d.", rowNumber, row.Id, previousRowNumber),
+			)
+			continue
+		}
+		seenIds[row.Id] = rowNumber
+
+		team, err := database.GetTeamById(row.Id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Row %d: %v", rowNumber, err))
+			continue
+		}
+		isNewTeam := team == nil
+		if isNewTeam {
+			team = &Team{Id: row.Id}
+		}
+		team.Name = row.Name
+		team.Nickname = row.Nickname
+		team.City = row.City
+		team.StateProv = row.StateProv
+		team.Country = row.Country
+		team.RookieYear = row.RookieYear
+		team.RobotName = row.RobotName
+
+		if !dryRun {
+			if isNewTeam {
+				err = database.CreateTeam(team)
+			} else {
+				err = database.UpdateTeam(team)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Row %d: %v", rowNumber, err))
+				continue
+			}
+		}
+		imported++
+	}
+	return imported, errs
+}
@@ -72,3 +72,44 @@ func TestGetAllTeams(t *testing.T) {
 		assert.Equal(t, i+1, teams[i].Id)
 	}
 }
+
+func TestImportTeams(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+	db.CreateTeam(&Team{Id: 254, Nickname: "Old Nickname", WpaKey: "12345678", FtaNotes: "Check radio"})
+
+	rows := []TeamImportRow{
+		{Id: 254, Name: "NASA", Nickname: "The Cheesy Poofs", City: "San Jose", StateProv: "CA", Country: "USA",
+			RookieYear: 1999},
+		{Id: 0, Nickname: "Nobody"},
+		{Id: 1114, Nickname: "Simbotics"},
+		{Id: 846},
+		{Id: 1114, Nickname: "Simbotics Again"},
+	}
+
+	// Check that a dry run validates without writing anything.
+	imported, errs := db.ImportTeams(rows, true)
+	assert.Equal(t, 2, imported)
+	if assert.Equal(t, 3, len(errs)) {
+		assert.Equal(t, "Row 2: team number must be positive; got 0.", errs[0].Error())
+		assert.Equal(t, "Row 4: team 846 is missing a nickname.", errs[1].Error())
+		assert.Equal(t, "Row 5: team 1114 is a duplicate of row 3.", errs[2].Error())
+	}
+	team, _ := db.GetTeamById(254)
+	assert.Equal(t, "Old Nickname", team.Nickname)
+	team, _ = db.GetTeamById(1114)
+	assert.Nil(t, team)
+
+	imported, errs = db.ImportTeams(rows, false)
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, 3, len(errs))
+	team, _ = db.GetTeamById(254)
+	assert.Equal(t, Team{Id: 254, Name: "NASA", Nickname: "The Cheesy Poofs", City: "San Jose", StateProv: "CA",
+		Country: "USA", RookieYear: 1999, WpaKey: "12345678", FtaNotes: "Check radio"}, *team)
+	team, _ = db.GetTeamById(1114)
+	if assert.NotNil(t, team) {
+		assert.Equal(t, "Simbotics", team.Nickname)
+	}
+	team, _ = db.GetTeamById(846)
+	assert.Nil(t, team)
+}
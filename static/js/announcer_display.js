@@ -55,7 +55,7 @@ var handleMatchLoad = function(data) {
 var handleMatchTime = function(data) {
   translateMatchTime(data, function(matchState, matchStateText, countdownSec) {
     $("#matchState").text(matchStateText);
-    $("#matchTime").text(getCountdown(data.MatchState, data.MatchTimeSec));
+    $("#matchTime").text(countdownSec);
   });
 };
 
@@ -38,6 +38,11 @@ var signalReset = function() {
   websocket.send("signalReset");
 };
 
+// Sends a websocket message to confirm that the field has been reset for the next match.
+var confirmFieldReset = function() {
+  websocket.send("confirmFieldReset");
+};
+
 // Sends a websocket message to commit the match score and load the next match.
 var commitResults = function() {
   websocket.send("commitResults");
@@ -102,6 +107,19 @@ var setTestMatchName = function() {
   websocket.send("setTestMatchName", $("#testMatchName").val());
 };
 
+// Sends a websocket message to add a note to the current match, then clears the note text box.
+var appendNote = function() {
+  websocket.send("appendNote", $("#noteText").val());
+  $("#noteText").val("");
+};
+
+// Adds the note when the enter key is pressed in the note text box.
+var noteKeyHandler = function(e) {
+  if (e.which === 13) {
+    appendNote();
+  }
+};
+
 // Handles a websocket message to update the team connection status.
 var handleArenaStatus = function(data) {
   // If getting data for the wrong match (e.g. after a server restart), reload the page.
@@ -257,6 +275,11 @@ var handleArenaStatus = function(data) {
       break;
   }
 
+  var matchInProgress = ["START_MATCH", "WARMUP_PERIOD", "AUTO_PERIOD", "PAUSE_PERIOD", "TELEOP_PERIOD"]
+      .indexOf(matchStates[data.MatchState]) >= 0;
+  $("#confirmFieldReset").prop("disabled", matchInProgress || data.FieldResetConfirmed);
+  $("#matchNotes").text(data.Notes);
+
   if (data.PlcIsHealthy) {
     $("#plcStatus").text("Connected");
     $("#plcStatus").attr("data-ready", true);
@@ -16,7 +16,8 @@ var matchStates = {
 };
 var matchTiming;
 
-// Handles a websocket message containing the length of each period in the match.
+// Handles a websocket message containing the length of each period in the match, including any adjustment made to the
+// teleop period of the match in progress.
 var handleMatchTiming = function(data) {
   matchTiming = data;
 };
@@ -50,24 +51,30 @@ var translateMatchTime = function(data, callback) {
       matchStateText = "TIMEOUT";
       break;
   }
-  callback(matchStates[data.MatchState], matchStateText, getCountdown(data.MatchState, data.MatchTimeSec));
+  callback(matchStates[data.MatchState], matchStateText, getCountdown(data.MatchState, data.CountdownSec));
 };
 
-// Returns the per-period countdown for the given match state and overall time into the match.
-var getCountdown = function(matchState, matchTimeSec) {
+// Returns the per-period countdown for the given match state and countdown computed by the server, limited to between
+// zero and the length of the period so that a bad match time is never displayed. Both the countdown and the period
+// lengths reported by the server take into account any adjustment to the teleop period.
+var getCountdown = function(matchState, countdownSec) {
   switch (matchStates[matchState]) {
     case "PRE_MATCH":
     case "START_MATCH":
     case "WARMUP_PERIOD":
       return matchTiming.AutoDurationSec;
     case "AUTO_PERIOD":
-      return matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec - matchTimeSec;
+      return clampCountdown(countdownSec, matchTiming.AutoDurationSec);
     case "TELEOP_PERIOD":
-      return matchTiming.WarmupDurationSec + matchTiming.AutoDurationSec + matchTiming.TeleopDurationSec +
-          matchTiming.PauseDurationSec - matchTimeSec;
+      return clampCountdown(countdownSec, matchTiming.TeleopDurationSec);
     case "TIMEOUT_ACTIVE":
-      return matchTiming.TimeoutDurationSec - matchTimeSec;
+      return clampCountdown(countdownSec, matchTiming.TimeoutDurationSec);
     default:
       return 0;
   }
 };
+
+// Limits the given countdown to between zero and the given period length.
+var clampCountdown = function(countdownSec, periodSec) {
+  return Math.max(0, Math.min(countdownSec, periodSec));
+};
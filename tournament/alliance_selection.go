@@ -0,0 +1,229 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+//
+// Driver for the playoff alliance selection process, enforcing pick order and team eligibility.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/Team254/cheesy-arena-lite/model"
+)
+
+// The subset of the database needed to save the alliances, which is satisfied by *model.Database.
+type AlliancesDatastore interface {
+	GetAllAlliances() ([]model.Alliance, error)
+	CreateAlliance(alliance *model.Alliance) error
+}
+
+var _ AlliancesDatastore = (*model.Database)(nil)
+
+type AllianceSelection struct {
+	Alliances     []model.Alliance
+	rankedTeamIds []int
+	picked        map[int]bool
+	pickOrder     []int
+	pickCount     int
+}
+
+// Creates a new alliance selection for the given number of alliances, with the eligible teams and the order in which
+// captains are filled taken from the current qualification rankings. The round order parameters follow the event
+// settings convention of "F" for first-to-last and "L" for last-to-first; the third round can also be left empty to
+// skip it.
+func NewAllianceSelection(
+	database RankingsDatastore, numAlliances int, round2Order, round3Order string,
+) (*AllianceSelection, error) {
+	if numAlliances <= 0 {
+		return nil, fmt.Errorf("Number of alliances must be positive; got %d.", numAlliances)
+	}
+	if round2Order != "F" && round2Order != "L" {
+		return nil, fmt.Errorf("Invalid order '%s' for the second round of alliance selection.", round2Order)
+	}
+	if round3Order != "F" && round3Order != "L" && round3Order != "" {
+		return nil, fmt.Errorf("Invalid order '%s' for the third round of alliance selection.", round3Order)
+	}
+	rankings, err := database.GetAllRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &AllianceSelection{picked: make(map[int]bool)}
+	for _, ranking := range rankings {
+		selection.rankedTeamIds = append(selection.rankedTeamIds, ranking.TeamId)
+	}
+
+	// Build the sequence of alliances making each pick across all rounds.
+	rounds := []string{"F", round2Order}
+	if round3Order != "" {
+		rounds = append(rounds, round3Order)
+	}
+	for _, order := range rounds {
+		for i := 0; i < numAlliances; i++ {
+			if order == "L" {
+				selection.pickOrder = append(selection.pickOrder, numAlliances-1-i)
+			} else {
+				selection.pickOrder = append(selection.pickOrder, i)
+			}
+		}
+	}
+
+	teamsPerAlliance := len(rounds) + 1
+	if len(selection.rankedTeamIds) < numAlliances*teamsPerAlliance {
+		return nil, fmt.Errorf(
+			"Not enough ranked teams to fill %d alliances of %d; only %d teams are ranked.", numAlliances,
+			teamsPerAlliance, len(selection.rankedTeamIds),
+		)
+	}
+	selection.Alliances = make([]model.Alliance, numAlliances)
+	for i := range selection.Alliances {
+		selection.Alliances[i].Id = i + 1
+		selection.Alliances[i].TeamIds = make([]int, teamsPerAlliance)
+	}
+	selection.fillCaptain()
+
+	return selection, nil
+}
+
+// Picks up an alliance selection from the given alliances as filled in so far, e.g. by hand, by replaying their picks
+// in turn. Blank captain spots are filled in the same way as during the selection. Fails if the alliances don't follow
+// the pick order or include ineligible or repeated teams.
+func ResumeAllianceSelection(
+	database RankingsDatastore, alliances []model.Alliance, round2Order, round3Order string,
+) (*AllianceSelection, error) {
+	selection, err := NewAllianceSelection(database, len(alliances), round2Order, round3Order)
+	if err != nil {
+		return nil, err
+	}
+	for _, alliance := range alliances {
+		if len(alliance.TeamIds) != len(selection.Alliances[0].TeamIds) {
+			return nil, fmt.Errorf(
+				"Alliance %d has %d teams instead of the %d of the selection rounds.", alliance.Id,
+				len(alliance.TeamIds), len(selection.Alliances[0].TeamIds),
+			)
+		}
+	}
+
+	for !selection.IsComplete() {
+		allianceIndex := selection.pickOrder[selection.pickCount]
+		round := selection.pickCount/len(selection.Alliances) + 1
+		team := alliances[allianceIndex].TeamIds[round]
+		if team == 0 {
+			break
+		}
+		captain := alliances[allianceIndex].TeamIds[0]
+		if captain != 0 && captain != selection.NextCaptain() {
+			return nil, fmt.Errorf(
+				"The captain of alliance %d should be team %d, not team %d.", allianceIndex+1, selection.NextCaptain(),
+				captain,
+			)
+		}
+		if err = selection.Pick(selection.NextCaptain(), team); err != nil {
+			return nil, err
+		}
+	}
+
+	// Any team left over was placed out of turn.
+	for i, alliance := range alliances {
+		for j, team := range alliance.TeamIds {
+			if team != 0 && team != selection.Alliances[i].TeamIds[j] {
+				return nil, fmt.Errorf("Team %d was placed on alliance %d out of turn.", team, i+1)
+			}
+		}
+	}
+	return selection, nil
+}
+
+// Returns true if every pick has been made.
+func (selection *AllianceSelection) IsComplete() bool {
+	return selection.pickCount >= len(selection.pickOrder)
+}
+
+// Returns the captain of the alliance whose turn it is to pick, or zero if selection is complete.
+func (selection *AllianceSelection) NextCaptain() int {
+	if selection.IsComplete() {
+		return 0
+	}
+	return selection.Alliances[selection.pickOrder[selection.pickCount]].TeamIds[0]
+}
+
+// Records the given captain's pick of the given team, enforcing that captains pick in turn and that only ranked teams
+// not already on an alliance may be picked.
+func (selection *AllianceSelection) Pick(captain, team int) error {
+	if selection.IsComplete() {
+		return fmt.Errorf("Alliance selection is already complete.")
+	}
+	if nextCaptain := selection.NextCaptain(); captain != nextCaptain {
+		return fmt.Errorf("It is team %d's turn to pick, not team %d's.", nextCaptain, captain)
+	}
+	if !selection.isRanked(team) {
+		return fmt.Errorf("Team %d has not played any matches at this event and is ineligible for selection.", team)
+	}
+	if selection.picked[team] {
+		return fmt.Errorf("Team %d is already part of an alliance.", team)
+	}
+
+	allianceIndex := selection.pickOrder[selection.pickCount]
+	round := selection.pickCount/len(selection.Alliances) + 1
+	selection.Alliances[allianceIndex].TeamIds[round] = team
+	selection.picked[team] = true
+	selection.pickCount++
+	selection.fillCaptain()
+	return nil
+}
+
+// Saves the completed alliances to the database.
+func (selection *AllianceSelection) Save(database AlliancesDatastore) error {
+	if !selection.IsComplete() {
+		return fmt.Errorf("Can't save alliances until all picks have been made.")
+	}
+	return SaveAlliances(database, selection.Alliances)
+}
+
+// Saves the given alliances to the database, with the initial lineup populated according to the tournament rules
+// (alliance captain in the middle, first pick on the left, second pick on the right). Fails if any alliances have
+// already been saved, so that a repeated request can't create a second set.
+func SaveAlliances(database AlliancesDatastore, alliances []model.Alliance) error {
+	existingAlliances, err := database.GetAllAlliances()
+	if err != nil {
+		return err
+	}
+	if len(existingAlliances) > 0 {
+		return fmt.Errorf("Alliances have already been saved; reset the alliance selection to start over.")
+	}
+	for _, alliance := range alliances {
+		alliance.Lineup[0] = alliance.TeamIds[1]
+		alliance.Lineup[1] = alliance.TeamIds[0]
+		alliance.Lineup[2] = alliance.TeamIds[2]
+		if err = database.CreateAlliance(&alliance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assigns a captain to the alliance whose turn it is, if it doesn't have one yet, from the highest-ranked team not
+// already on an alliance.
+func (selection *AllianceSelection) fillCaptain() {
+	if selection.IsComplete() {
+		return
+	}
+	alliance := &selection.Alliances[selection.pickOrder[selection.pickCount]]
+	if alliance.TeamIds[0] != 0 {
+		return
+	}
+	for _, teamId := range selection.rankedTeamIds {
+		if !selection.picked[teamId] {
+			alliance.TeamIds[0] = teamId
+			selection.picked[teamId] = true
+			return
+		}
+	}
+}
+
+func (selection *AllianceSelection) isRanked(team int) bool {
+	for _, teamId := range selection.rankedTeamIds {
+		if teamId == team {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Team 254. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/Team254/cheesy-arena-lite/game"
+	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAllianceSelection(t *testing.T) {
+	database := setupTestDb(t)
+	for i := 1; i <= 10; i++ {
+		database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+
+	_, err := NewAllianceSelection(database, 4, "L", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Not enough ranked teams to fill 4 alliances of 3; only 10 teams are ranked.", err.Error())
+	}
+
+	selection, err := NewAllianceSelection(database, 3, "L", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 101, selection.NextCaptain())
+
+	// Check that captains must pick in turn and can only pick eligible teams.
+	err = selection.Pick(102, 103)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "It is team 101's turn to pick, not team 102's.", err.Error())
+	}
+	err = selection.Pick(101, 254)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Team 254 has not played any matches")
+	}
+	err = selection.Pick(101, 101)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 101 is already part of an alliance.", err.Error())
+	}
+
+	// Picking the next captain should promote the following team to captain.
+	assert.Nil(t, selection.Pick(101, 102))
+	assert.Equal(t, 103, selection.NextCaptain())
+	assert.Nil(t, selection.Pick(103, 105))
+	assert.Equal(t, 104, selection.NextCaptain())
+	assert.Nil(t, selection.Pick(104, 106))
+	err = selection.Pick(104, 102)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 102 is already part of an alliance.", err.Error())
+	}
+	assert.NotNil(t, selection.Save(database))
+
+	// The second round should run in reverse order.
+	assert.Equal(t, 104, selection.NextCaptain())
+	assert.Nil(t, selection.Pick(104, 107))
+	assert.Nil(t, selection.Pick(103, 108))
+	assert.Nil(t, selection.Pick(101, 110))
+	assert.True(t, selection.IsComplete())
+	assert.Equal(t, 0, selection.NextCaptain())
+	assert.NotNil(t, selection.Pick(101, 109))
+
+	assert.Nil(t, selection.Save(database))
+	alliances, err := database.GetAllAlliances()
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]model.Alliance{
+			{Id: 1, TeamIds: []int{101, 102, 110}, Lineup: [3]int{102, 101, 110}},
+			{Id: 2, TeamIds: []int{103, 105, 108}, Lineup: [3]int{105, 103, 108}},
+			{Id: 3, TeamIds: []int{104, 106, 107}, Lineup: [3]int{106, 104, 107}},
+		},
+		alliances,
+	)
+}
+
+func TestAllianceSelectionRoundOrders(t *testing.T) {
+	database := setupTestDb(t)
+	for i := 1; i <= 12; i++ {
+		database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+
+	_, err := NewAllianceSelection(database, 3, "", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid order '' for the second round of alliance selection.", err.Error())
+	}
+	_, err = NewAllianceSelection(database, 3, "F", "X")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid order 'X' for the third round of alliance selection.", err.Error())
+	}
+
+	// A third round adds a pick to each alliance, in its own order.
+	selection, err := NewAllianceSelection(database, 3, "F", "L")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(selection.Alliances[0].TeamIds))
+	for _, team := range []int{102, 104, 106, 107, 108, 109} {
+		assert.Nil(t, selection.Pick(selection.NextCaptain(), team))
+	}
+	assert.Equal(t, 105, selection.NextCaptain())
+}
+
+func TestResumeAllianceSelection(t *testing.T) {
+	database := setupTestDb(t)
+	for i := 1; i <= 10; i++ {
+		database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+	blankAlliances := func() []model.Alliance {
+		alliances := make([]model.Alliance, 3)
+		for i := range alliances {
+			alliances[i] = model.Alliance{Id: i + 1, TeamIds: make([]int, 3)}
+		}
+		return alliances
+	}
+
+	// Blank alliances start from the beginning, with the first captain filled in.
+	selection, err := ResumeAllianceSelection(database, blankAlliances(), "L", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 101, selection.NextCaptain())
+
+	// Picks made by hand in turn carry on from where they left off.
+	alliances := blankAlliances()
+	alliances[0].TeamIds = []int{101, 102, 0}
+	alliances[1].TeamIds = []int{103, 0, 0}
+	selection, err = ResumeAllianceSelection(database, alliances, "L", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 103, selection.NextCaptain())
+	assert.Nil(t, selection.Pick(103, 110))
+	assert.Equal(t, []int{103, 110, 0}, selection.Alliances[1].TeamIds)
+
+	// Alliances filled out of turn can't be picked up.
+	alliances = blankAlliances()
+	alliances[1].TeamIds = []int{103, 104, 0}
+	_, err = ResumeAllianceSelection(database, alliances, "L", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Team 103 was placed on alliance 2 out of turn.", err.Error())
+	}
+	alliances = blankAlliances()
+	alliances[0].TeamIds = []int{102, 103, 0}
+	_, err = ResumeAllianceSelection(database, alliances, "L", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "The captain of alliance 1 should be team 101, not team 102.", err.Error())
+	}
+	alliances = blankAlliances()
+	alliances[0].TeamIds = []int{101, 102, 0, 0}
+	_, err = ResumeAllianceSelection(database, alliances, "L", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Alliance 1 has 4 teams instead of the 3 of the selection rounds.", err.Error())
+	}
+}
+
+func TestSaveAlliancesRejectsExistingAlliances(t *testing.T) {
+	database := setupTestDb(t)
+	alliances := []model.Alliance{{Id: 1, TeamIds: []int{101, 102, 103}}, {Id: 2, TeamIds: []int{104, 105, 106}}}
+
+	assert.Nil(t, SaveAlliances(database, alliances))
+	savedAlliances, _ := database.GetAllAlliances()
+	if assert.Equal(t, 2, len(savedAlliances)) {
+		assert.Equal(t, [3]int{105, 104, 106}, savedAlliances[1].Lineup)
+	}
+
+	err := SaveAlliances(database, alliances)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Alliances have already been saved")
+	}
+	savedAlliances, _ = database.GetAllAlliances()
+	assert.Equal(t, 2, len(savedAlliances))
+}
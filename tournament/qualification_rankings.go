@@ -11,40 +11,24 @@ import (
 	"sort"
 )
 
-// Determines the rankings from the stored match results, and saves them to the database.
-func CalculateRankings(database *model.Database, preservePreviousRank bool) (game.Rankings, error) {
-	matches, err := database.GetMatchesByType("qualification")
+// The subset of the database needed to calculate the rankings, which is satisfied by *model.Database.
+type RankingsDatastore interface {
+	GetMatchesByType(matchType string) ([]model.Match, error)
+	GetMatchResultForMatch(matchId int) (*model.MatchResult, error)
+	GetAllRankings() (game.Rankings, error)
+	ReplaceAllRankings(rankings game.Rankings) error
+}
+
+var _ RankingsDatastore = (*model.Database)(nil)
+
+// Determines the rankings from the stored results of matches of the given types, and saves them to the database.
+func CalculateRankings(
+	database RankingsDatastore, rankingMatchTypes []string, preservePreviousRank bool,
+) (game.Rankings, error) {
+	rankings, err := aggregateRankings(database, rankingMatchTypes, game.DefaultRankingPoints)
 	if err != nil {
 		return nil, err
 	}
-	rankings := make(map[int]*game.Ranking)
-	for _, match := range matches {
-		if !match.IsComplete() {
-			continue
-		}
-		matchResult, err := database.GetMatchResultForMatch(match.Id)
-		if err != nil {
-			return nil, err
-		}
-		if !match.Red1IsSurrogate {
-			addMatchResultToRankings(rankings, match.Red1, matchResult, true)
-		}
-		if !match.Red2IsSurrogate {
-			addMatchResultToRankings(rankings, match.Red2, matchResult, true)
-		}
-		if !match.Red3IsSurrogate {
-			addMatchResultToRankings(rankings, match.Red3, matchResult, true)
-		}
-		if !match.Blue1IsSurrogate {
-			addMatchResultToRankings(rankings, match.Blue1, matchResult, false)
-		}
-		if !match.Blue2IsSurrogate {
-			addMatchResultToRankings(rankings, match.Blue2, matchResult, false)
-		}
-		if !match.Blue3IsSurrogate {
-			addMatchResultToRankings(rankings, match.Blue3, matchResult, false)
-		}
-	}
 
 	// Retrieve old rankings so that we can display changes in rank as a result of this calculation.
 	oldRankings, err := database.GetAllRankings()
@@ -75,9 +59,76 @@ func CalculateRankings(database *model.Database, preservePreviousRank bool) (gam
 	return sortedRankings, nil
 }
 
-// Incrementally accounts for the given match result in the set of rankings that are being built.
+// Determines the rankings from the stored results of matches of the given types without saving them, e.g. for
+// previewing standings, awarding ranking points using the given formula. Unlike the official rankings, teams that are
+// tied on every tiebreaker are ordered by team number instead of by random draw, so that repeated calls return the same
+// order.
+func ComputeRankings(
+	database RankingsDatastore, rankingMatchTypes []string, rankingPoints game.RankingPointsFunc,
+) (game.Rankings, error) {
+	rankings, err := aggregateRankings(database, rankingMatchTypes, rankingPoints)
+	if err != nil {
+		return nil, err
+	}
+	for _, ranking := range rankings {
+		ranking.Random = 0
+	}
+	sortedRankings := sortRankings(rankings)
+	for rank := range sortedRankings {
+		sortedRankings[rank].Rank = rank + 1
+	}
+	return sortedRankings, nil
+}
+
+// Accumulates the ranking fields for each team across all completed matches of the given types, awarding ranking points
+// using the given formula.
+func aggregateRankings(
+	database RankingsDatastore, rankingMatchTypes []string, rankingPoints game.RankingPointsFunc,
+) (map[int]*game.Ranking, error) {
+	var matches []model.Match
+	for _, matchType := range rankingMatchTypes {
+		matchesOfType, err := database.GetMatchesByType(matchType)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, matchesOfType...)
+	}
+	rankings := make(map[int]*game.Ranking)
+	for _, match := range matches {
+		if !match.IsComplete() {
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !match.Red1IsSurrogate {
+			addMatchResultToRankings(rankings, match.Red1, &match, matchResult, true, rankingPoints)
+		}
+		if !match.Red2IsSurrogate {
+			addMatchResultToRankings(rankings, match.Red2, &match, matchResult, true, rankingPoints)
+		}
+		if !match.Red3IsSurrogate {
+			addMatchResultToRankings(rankings, match.Red3, &match, matchResult, true, rankingPoints)
+		}
+		if !match.Blue1IsSurrogate {
+			addMatchResultToRankings(rankings, match.Blue1, &match, matchResult, false, rankingPoints)
+		}
+		if !match.Blue2IsSurrogate {
+			addMatchResultToRankings(rankings, match.Blue2, &match, matchResult, false, rankingPoints)
+		}
+		if !match.Blue3IsSurrogate {
+			addMatchResultToRankings(rankings, match.Blue3, &match, matchResult, false, rankingPoints)
+		}
+	}
+	return rankings, nil
+}
+
+// Incrementally accounts for the given match result in the set of rankings that are being built. A result overridden
+// by the head referee takes precedence over the one computed from the scores.
 func addMatchResultToRankings(
-	rankings map[int]*game.Ranking, teamId int, matchResult *model.MatchResult, isRed bool,
+	rankings map[int]*game.Ranking, teamId int, match *model.Match, matchResult *model.MatchResult, isRed bool,
+	rankingPoints game.RankingPointsFunc,
 ) {
 	ranking := rankings[teamId]
 	if ranking == nil {
@@ -85,10 +136,23 @@ func addMatchResultToRankings(
 		rankings[teamId] = ranking
 	}
 
-	if isRed {
-		ranking.AddScoreSummary(matchResult.RedScoreSummary(), matchResult.BlueScoreSummary())
+	disqualified := matchResult.TeamCard(teamId, isRed) == game.RedCard
+	if match.IsResultOverridden() {
+		ownScore, opponentScore := matchResult.BlueScoreSummary(), matchResult.RedScoreSummary()
+		if isRed {
+			ownScore, opponentScore = opponentScore, ownScore
+		}
+		ranking.AddOverriddenScoreSummary(
+			ownScore, opponentScore, isRed, match.OverrideStatus, disqualified, rankingPoints,
+		)
+	} else if isRed {
+		ranking.AddScoreSummaryWithRankingPoints(
+			matchResult.RedScoreSummary(), matchResult.BlueScoreSummary(), disqualified, rankingPoints,
+		)
 	} else {
-		ranking.AddScoreSummary(matchResult.BlueScoreSummary(), matchResult.RedScoreSummary())
+		ranking.AddScoreSummaryWithRankingPoints(
+			matchResult.BlueScoreSummary(), matchResult.RedScoreSummary(), disqualified, rankingPoints,
+		)
 	}
 }
 
@@ -16,7 +16,7 @@ func TestCalculateRankings(t *testing.T) {
 	database := setupTestDb(t)
 
 	setupMatchResultsForRankings(database)
-	updatedRankings, err := CalculateRankings(database, false)
+	updatedRankings, err := CalculateRankings(database, []string{"qualification"}, false)
 	assert.Nil(t, err)
 	rankings, err := database.GetAllRankings()
 	assert.Nil(t, err)
@@ -41,7 +41,7 @@ func TestCalculateRankings(t *testing.T) {
 	matchResult3.RedScore, matchResult3.BlueScore = matchResult3.BlueScore, matchResult3.RedScore
 	err = database.CreateMatchResult(matchResult3)
 	assert.Nil(t, err)
-	updatedRankings, err = CalculateRankings(database, false)
+	updatedRankings, err = CalculateRankings(database, []string{"qualification"}, false)
 	assert.Nil(t, err)
 	rankings, err = database.GetAllRankings()
 	assert.Nil(t, err)
@@ -64,7 +64,7 @@ func TestCalculateRankings(t *testing.T) {
 	matchResult3 = model.BuildTestMatchResult(3, 4)
 	err = database.CreateMatchResult(matchResult3)
 	assert.Nil(t, err)
-	updatedRankings, err = CalculateRankings(database, true)
+	updatedRankings, err = CalculateRankings(database, []string{"qualification"}, true)
 	assert.Nil(t, err)
 	rankings, err = database.GetAllRankings()
 	assert.Nil(t, err)
@@ -85,6 +85,123 @@ func TestCalculateRankings(t *testing.T) {
 	}
 }
 
+func TestComputeRankings(t *testing.T) {
+	database := setupTestDb(t)
+	setupMatchResultsForRankings(database)
+
+	rankings, err := ComputeRankings(database, []string{"qualification"}, game.DefaultRankingPoints)
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(rankings)) {
+		for i, ranking := range rankings {
+			assert.Equal(t, i+1, ranking.Rank)
+		}
+	}
+	rankings2, _ := ComputeRankings(database, []string{"qualification"}, game.DefaultRankingPoints)
+	assert.Equal(t, rankings, rankings2)
+
+	// Computing the rankings shouldn't save them.
+	dbRankings, _ := database.GetAllRankings()
+	assert.Empty(t, dbRankings)
+
+	// Check that a replacement ranking point formula is applied.
+	threePoints := func(ownScore, opponentScore *game.ScoreSummary) int {
+		return 3
+	}
+	rankings, err = ComputeRankings(database, []string{"qualification"}, threePoints)
+	assert.Nil(t, err)
+	for _, ranking := range rankings {
+		assert.Equal(t, 3*ranking.Played, ranking.RankingPoints)
+	}
+}
+
+func TestRankingMatchTypes(t *testing.T) {
+	database := setupTestDb(t)
+	setupMatchResultsForRankings(database)
+
+	// Only the matches of the given types count.
+	rankings, err := ComputeRankings(database, []string{"qualification"}, game.DefaultRankingPoints)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(rankings))
+	for _, ranking := range rankings {
+		if ranking.TeamId == 1 {
+			assert.Equal(t, 3, ranking.Played)
+		}
+	}
+
+	_, err = CalculateRankings(database, []string{"qualification", "practice"}, false)
+	assert.Nil(t, err)
+	ranking, _ := database.GetRankingForTeam(1)
+	assert.Equal(t, 4, ranking.Played)
+	ranking, _ = database.GetRankingForTeam(5)
+	assert.Equal(t, 4, ranking.Played)
+
+	_, err = CalculateRankings(database, []string{"elimination"}, false)
+	assert.Nil(t, err)
+	rankings, _ = database.GetAllRankings()
+	if assert.Equal(t, 6, len(rankings)) {
+		for _, ranking := range rankings {
+			assert.Equal(t, 1, ranking.Played)
+		}
+	}
+}
+
+func TestCalculateRankingsWithRedCard(t *testing.T) {
+	database := setupTestDb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedCards = map[string]int{"2": game.RedCard, "3": game.YellowCard}
+	database.CreateMatchResult(matchResult)
+
+	_, err := CalculateRankings(database, []string{"qualification"}, false)
+	assert.Nil(t, err)
+	ranking, _ := database.GetRankingForTeam(1)
+	assert.Equal(t, 2, ranking.RankingPoints)
+
+	// The red-carded team should be credited with playing but receive no points.
+	ranking, _ = database.GetRankingForTeam(2)
+	assert.Equal(t, 1, ranking.Played)
+	assert.Equal(t, 0, ranking.RankingPoints)
+	assert.Equal(t, 0, ranking.Wins)
+	assert.Equal(t, 0, ranking.AutoPoints)
+	ranking, _ = database.GetRankingForTeam(3)
+	assert.Equal(t, 2, ranking.RankingPoints)
+}
+
+func TestCalculateRankingsWithOverride(t *testing.T) {
+	database := setupTestDb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.BlueWonMatch, OverrideStatus: game.RedWonMatch, OverrideReason: "Blue G204"}
+	database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedScore, matchResult.BlueScore = matchResult.BlueScore, matchResult.RedScore
+	database.CreateMatchResult(matchResult)
+
+	_, err := CalculateRankings(database, []string{"qualification"}, false)
+	assert.Nil(t, err)
+	ranking, _ := database.GetRankingForTeam(1)
+	assert.Equal(t, 2, ranking.RankingPoints)
+	assert.Equal(t, 1, ranking.Wins)
+	assert.Equal(t, matchResult.RedScoreSummary().AutoPoints, ranking.AutoPoints)
+	ranking, _ = database.GetRankingForTeam(4)
+	assert.Equal(t, 0, ranking.RankingPoints)
+	assert.Equal(t, 1, ranking.Losses)
+
+	match.OverrideStatus = game.TieMatch
+	database.UpdateMatch(&match)
+	_, err = CalculateRankings(database, []string{"qualification"}, false)
+	assert.Nil(t, err)
+	ranking, _ = database.GetRankingForTeam(1)
+	assert.Equal(t, 1, ranking.RankingPoints)
+	assert.Equal(t, 1, ranking.Ties)
+	ranking, _ = database.GetRankingForTeam(4)
+	assert.Equal(t, 1, ranking.RankingPoints)
+	assert.Equal(t, 1, ranking.Ties)
+}
+
 // Sets up a schedule and results that touches on all possible variables.
 func setupMatchResultsForRankings(database *model.Database) {
 	match1 := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
@@ -130,3 +247,25 @@ func setupMatchResultsForRankings(database *model.Database) {
 	matchResult6 := model.BuildTestMatchResult(match6.Id, 1)
 	database.CreateMatchResult(matchResult6)
 }
+
+func TestCalculateRankingsIgnoresSurrogateAppearances(t *testing.T) {
+	database := setupTestDb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch, Red3IsSurrogate: true, Blue1IsSurrogate: true}
+	database.CreateMatch(&match)
+	database.CreateMatchResult(model.BuildTestMatchResult(match.Id, 1))
+
+	rankings, err := CalculateRankings(database, []string{"qualification"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(rankings))
+	for _, teamId := range []int{3, 4} {
+		ranking, err := database.GetRankingForTeam(teamId)
+		assert.Nil(t, err)
+		assert.Nil(t, ranking, "team %d", teamId)
+	}
+	ranking, _ := database.GetRankingForTeam(1)
+	assert.Equal(t, 1, ranking.Wins)
+	ranking, _ = database.GetRankingForTeam(5)
+	assert.Equal(t, 1, ranking.Losses)
+}
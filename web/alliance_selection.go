@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/Team254/cheesy-arena-lite/tournament"
 	"net/http"
 	"strconv"
 	"time"
@@ -20,9 +21,6 @@ type RankedTeam struct {
 	Picked bool
 }
 
-// Global var to hold the team rankings during the alliance selection.
-var cachedRankedTeams []*RankedTeam
-
 // Shows the alliance selection page.
 func (web *Web) allianceSelectionGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -44,8 +42,8 @@ func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Reset picked state for each team in preparation for reconstructing it.
-	newRankedTeams := make([]*RankedTeam, len(cachedRankedTeams))
-	for i, team := range cachedRankedTeams {
+	newRankedTeams := make([]*RankedTeam, len(web.cachedRankedTeams))
+	for i, team := range web.cachedRankedTeams {
 		newRankedTeams[i] = &RankedTeam{team.Rank, team.TeamId, false}
 	}
 
@@ -88,7 +86,50 @@ func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Requ
 			}
 		}
 	}
-	cachedRankedTeams = newRankedTeams
+	web.cachedRankedTeams = newRankedTeams
+
+	web.arena.AllianceSelectionNotifier.Notify()
+	http.Redirect(w, r, "/alliance_selection", 303)
+}
+
+// Records the pick of the captain whose turn it is, enforcing the pick order and team eligibility.
+func (web *Web) allianceSelectionPickHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	if !web.canModifyAllianceSelection() {
+		web.renderAllianceSelection(w, r, "Alliance selection has already been finalized.")
+		return
+	}
+
+	selection, err := web.resumeAllianceSelection()
+	if err != nil {
+		web.renderAllianceSelection(w, r, fmt.Sprintf("Can't pick in turn: %s", err.Error()))
+		return
+	}
+	captain, _ := strconv.Atoi(r.PostFormValue("captain"))
+	team, err := strconv.Atoi(r.PostFormValue("team"))
+	if err != nil {
+		web.renderAllianceSelection(w, r, fmt.Sprintf("Invalid team number value '%s'.", r.PostFormValue("team")))
+		return
+	}
+	if err = selection.Pick(captain, team); err != nil {
+		web.renderAllianceSelection(w, r, err.Error())
+		return
+	}
+	web.arena.AllianceSelectionAlliances = selection.Alliances
+
+	// Captains filled in along the way are no longer available either, as well as the picked team.
+	allianceTeamIds := make(map[int]bool)
+	for _, alliance := range selection.Alliances {
+		for _, allianceTeamId := range alliance.TeamIds {
+			allianceTeamIds[allianceTeamId] = true
+		}
+	}
+	for _, rankedTeam := range web.cachedRankedTeams {
+		rankedTeam.Picked = allianceTeamIds[rankedTeam.TeamId]
+	}
 
 	web.arena.AllianceSelectionNotifier.Notify()
 	http.Redirect(w, r, "/alliance_selection", 303)
@@ -126,9 +167,9 @@ func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Req
 		handleWebErr(w, err)
 		return
 	}
-	cachedRankedTeams = make([]*RankedTeam, len(rankings))
+	web.cachedRankedTeams = make([]*RankedTeam, len(rankings))
 	for i, ranking := range rankings {
-		cachedRankedTeams[i] = &RankedTeam{i + 1, ranking.TeamId, false}
+		web.cachedRankedTeams[i] = &RankedTeam{i + 1, ranking.TeamId, false}
 	}
 
 	web.arena.AllianceSelectionNotifier.Notify()
@@ -172,7 +213,7 @@ func (web *Web) allianceSelectionResetHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	web.arena.AllianceSelectionNotifier.Notify()
 	http.Redirect(w, r, "/alliance_selection", 303)
 }
@@ -206,18 +247,9 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Save alliances to the database.
-	for _, alliance := range web.arena.AllianceSelectionAlliances {
-		// Populate the initial lineup according to the tournament rules (alliance captain in the middle, first pick on
-		// the left, second pick on the right).
-		alliance.Lineup[0] = alliance.TeamIds[1]
-		alliance.Lineup[1] = alliance.TeamIds[0]
-		alliance.Lineup[2] = alliance.TeamIds[2]
-
-		err := web.arena.Database.CreateAlliance(&alliance)
-		if err != nil {
-			handleWebErr(w, err)
-			return
-		}
+	if err = tournament.SaveAlliances(web.arena.Database, web.arena.AllianceSelectionAlliances); err != nil {
+		web.renderAllianceSelection(w, r, err.Error())
+		return
 	}
 
 	// Generate the first round of elimination matches.
@@ -295,14 +327,25 @@ func (web *Web) renderAllianceSelection(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	nextRow, nextCol := web.determineNextCell()
+	// Picking in turn is only offered while the alliances follow the pick order and there are enough ranked teams.
+	nextCaptain := 0
+	if len(web.arena.AllianceSelectionAlliances) > 0 && web.canModifyAllianceSelection() {
+		if selection, err := web.resumeAllianceSelection(); err == nil {
+			nextCaptain = selection.NextCaptain()
+		}
+	}
 	data := struct {
 		*model.EventSettings
 		Alliances    []model.Alliance
 		RankedTeams  []*RankedTeam
 		NextRow      int
 		NextCol      int
+		NextCaptain  int
 		ErrorMessage string
-	}{web.arena.EventSettings, web.arena.AllianceSelectionAlliances, cachedRankedTeams, nextRow, nextCol, errorMessage}
+	}{
+		web.arena.EventSettings, web.arena.AllianceSelectionAlliances, web.cachedRankedTeams, nextRow, nextCol,
+		nextCaptain, errorMessage,
+	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -310,6 +353,14 @@ func (web *Web) renderAllianceSelection(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// Picks up the alliance selection driver from the alliances as they currently stand.
+func (web *Web) resumeAllianceSelection() (*tournament.AllianceSelection, error) {
+	return tournament.ResumeAllianceSelection(
+		web.arena.Database, web.arena.AllianceSelectionAlliances, web.arena.EventSettings.SelectionRound2Order,
+		web.arena.EventSettings.SelectionRound3Order,
+	)
+}
+
 // Returns true if it is safe to change the alliance selection (i.e. no elimination matches exist yet).
 func (web *Web) canModifyAllianceSelection() bool {
 	matches, err := web.arena.Database.GetMatchesByType("elimination")
@@ -14,7 +14,7 @@ func TestAllianceSelection(t *testing.T) {
 	web := setupTestWeb(t)
 
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 15
 	web.arena.EventSettings.SelectionRound3Order = "L"
 	for i := 1; i <= 10; i++ {
@@ -99,7 +99,7 @@ func TestAllianceSelectionErrors(t *testing.T) {
 	web := setupTestWeb(t)
 
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 	for i := 1; i <= 6; i++ {
 		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
@@ -150,17 +150,89 @@ func TestAllianceSelectionErrors(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "already been finalized")
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	recorder = web.postHttpResponse("/alliance_selection/start", "")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "already been finalized")
 }
 
+func TestAllianceSelectionPickInTurn(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.NumElimAlliances = 2
+	web.arena.EventSettings.SelectionRound2Order = "L"
+	web.arena.EventSettings.SelectionRound3Order = ""
+	for i := 1; i <= 7; i++ {
+		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+	recorder := web.postHttpResponse("/alliance_selection/start", "")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.Contains(t, recorder.Body.String(), "Team 101 picks")
+
+	// Captains pick in turn, with the next captain promoted from the rankings.
+	recorder = web.postHttpResponse("/alliance_selection/pick", "captain=101&team=103")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []int{101, 103, 0}, web.arena.AllianceSelectionAlliances[0].TeamIds)
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.Contains(t, recorder.Body.String(), "Team 102 picks")
+	assert.NotContains(t, recorder.Body.String(), ">101<")
+	assert.NotContains(t, recorder.Body.String(), ">102<")
+	recorder = web.postHttpResponse("/alliance_selection/pick", "captain=101&team=104")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "It is team 102's turn to pick")
+	recorder = web.postHttpResponse("/alliance_selection/pick", "captain=102&team=103")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 103 is already part of an alliance.")
+	recorder = web.postHttpResponse("/alliance_selection/pick", "captain=102&team=254")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "ineligible for selection")
+	for _, pick := range []string{"captain=102&team=104", "captain=102&team=105", "captain=101&team=106"} {
+		recorder = web.postHttpResponse("/alliance_selection/pick", pick)
+		assert.Equal(t, 303, recorder.Code)
+	}
+	assert.Equal(t, []int{101, 103, 106}, web.arena.AllianceSelectionAlliances[0].TeamIds)
+	assert.Equal(t, []int{102, 104, 105}, web.arena.AllianceSelectionAlliances[1].TeamIds)
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.NotContains(t, recorder.Body.String(), "picks")
+
+	// Picking in turn isn't offered once the alliances have been edited out of turn.
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&selection1_0=102&selection1_1=107")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.NotContains(t, recorder.Body.String(), "picks")
+	recorder = web.postHttpResponse("/alliance_selection/pick", "captain=101&team=103")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Can't pick in turn")
+}
+
+func TestAllianceSelectionFinalizeWithSavedAlliances(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.NumElimAlliances = 2
+	for i := 1; i <= 6; i++ {
+		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+	recorder := web.postHttpResponse("/alliance_selection/start", "")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&selection0_1=102&selection0_2=103&"+
+		"selection1_0=104&selection1_1=105&selection1_2=106")
+	assert.Equal(t, 303, recorder.Code)
+
+	// Alliances saved before the playoff matches were created shouldn't be saved a second time.
+	web.arena.Database.CreateAlliance(&model.Alliance{Id: 1, TeamIds: []int{101, 102, 103}})
+	recorder = web.postHttpResponse("/alliance_selection/finalize", "startTime=2014-01-01 01:00:00 PM")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Alliances have already been saved")
+	alliances, _ := web.arena.Database.GetAllAlliances()
+	assert.Equal(t, 1, len(alliances))
+}
+
 func TestAllianceSelectionReset(t *testing.T) {
 	web := setupTestWeb(t)
 
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 	for i := 1; i <= 6; i++ {
 		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
@@ -216,7 +288,7 @@ func TestAllianceSelectionAutofocus(t *testing.T) {
 	web := setupTestWeb(t)
 
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 
 	// Straight draft.
@@ -4,7 +4,6 @@
 package web
 
 import (
-	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -63,7 +62,7 @@ func TestAllianceStationDisplayWebsocket(t *testing.T) {
 	messages := readWebsocketMultiple(t, ws, 3)
 	_, ok := messages["matchTime"]
 	assert.True(t, ok)
-	web.arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec) * time.Second)
+	web.arena.MatchStartTime = time.Now().Add(-time.Duration(web.arena.MatchTiming.WarmupDurationSec) * time.Second)
 	web.arena.Update()
 	messages = readWebsocketMultiple(t, ws, 2)
 	_, ok = messages["arenaStatus"]
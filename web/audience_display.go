@@ -28,7 +28,7 @@ func (web *Web) audienceDisplayHandler(w http.ResponseWriter, r *http.Request) {
 	data := struct {
 		*model.EventSettings
 		MatchSounds []*game.MatchSound
-	}{web.arena.EventSettings, game.MatchSounds}
+	}{web.arena.EventSettings, web.arena.MatchSounds}
 	err = template.ExecuteTemplate(w, "audience_display.html", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -80,3 +80,29 @@ func TestAudienceDisplayWebsocket(t *testing.T) {
 	web.arena.LowerThirdNotifier.Notify()
 	readWebsocketType(t, ws, "lowerThird")
 }
+
+func TestAudienceDisplayWebsocketLineupBypass(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/audience/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 9)
+
+	// The lineup in the match load message shows the bypasses, so it should be sent again when one changes.
+	assert.Nil(t, web.arena.SetBypass("R2", true))
+	message := readWebsocketType(t, ws, "matchLoad")
+	lineup := message.(map[string]interface{})["Lineup"].(map[string]interface{})
+	assert.Equal(t, true, lineup["R2"].(map[string]interface{})["Bypassed"])
+	assert.Equal(t, false, lineup["R1"].(map[string]interface{})["Bypassed"])
+
+	// Setting a bypass that is already in place isn't a change.
+	assert.Nil(t, web.arena.SetBypass("R2", true))
+	assert.Nil(t, web.arena.SetBypass("R2", false))
+	message = readWebsocketType(t, ws, "matchLoad")
+	lineup = message.(map[string]interface{})["Lineup"].(map[string]interface{})
+	assert.Equal(t, false, lineup["R2"].(map[string]interface{})["Bypassed"])
+}
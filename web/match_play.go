@@ -77,6 +77,7 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	isReplay := matchResult != nil
+	redScore, blueScore := web.arena.Scores()
 	data := struct {
 		*model.EventSettings
 		PlcIsEnabled          bool
@@ -100,8 +101,8 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		web.arena.CurrentMatch,
 		redOffFieldTeams,
 		blueOffFieldTeams,
-		web.arena.RedScore,
-		web.arena.BlueScore,
+		&redScore,
+		&blueScore,
 		web.arena.CurrentMatch.ShouldAllowSubstitution(),
 		isReplay,
 		web.arena.SavedMatch.CapitalizedType(),
@@ -137,7 +138,11 @@ func (web *Web) matchPlayLoadHandler(w http.ResponseWriter, r *http.Request) {
 			handleWebErr(w, fmt.Errorf("Invalid match ID %d.", matchId))
 			return
 		}
-		err = web.arena.LoadMatch(match)
+		if r.URL.Query().Get("replay") == "true" {
+			err = web.arena.LoadReplay(match)
+		} else {
+			err = web.arena.LoadMatch(match)
+		}
 	}
 	if err != nil {
 		handleWebErr(w, err)
@@ -173,7 +178,7 @@ func (web *Web) matchPlayShowResultHandler(w http.ResponseWriter, r *http.Reques
 		handleWebErr(w, fmt.Errorf("No result found for match ID %d.", matchId))
 		return
 	}
-	if match.ShouldUpdateRankings() {
+	if match.ShouldUpdateRankings(web.arena.EventSettings.GetRankingMatchTypes()) {
 		web.arena.SavedRankings, err = web.arena.Database.GetAllRankings()
 		if err != nil {
 			handleWebErr(w, err)
@@ -249,20 +254,41 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError(err.Error())
 				continue
 			}
+		case "setCard":
+			args := struct {
+				Station string
+				Card    int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.SetCard(args.Station, args.Card)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "toggleBypass":
 			station, ok := data.(string)
 			if !ok {
 				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
 				continue
 			}
-			if _, ok := web.arena.AllianceStations[station]; !ok {
+			allianceStation, ok := web.arena.AllianceStations[station]
+			if !ok {
 				ws.WriteError(fmt.Sprintf("Invalid alliance station '%s'.", station))
 				continue
 			}
-			web.arena.AllianceStations[station].Bypass = !web.arena.AllianceStations[station].Bypass
+			err = web.arena.SetBypass(station, !allianceStation.Bypass)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "startMatch":
 			args := struct {
 				MuteMatchSounds bool
+				StartToken      string
 			}{}
 			err = mapstructure.Decode(data, &args)
 			if err != nil {
@@ -270,7 +296,18 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				continue
 			}
 			web.arena.MuteMatchSounds = args.MuteMatchSounds
-			err = web.arena.StartMatch()
+			err = web.arena.StartMatchWithToken(args.StartToken)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+		case "setStartToken":
+			token, ok := data.(string)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			err = web.arena.SetStartToken(token)
 			if err != nil {
 				ws.WriteError(err.Error())
 				continue
@@ -297,6 +334,11 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 			web.arena.AllianceStationDisplayMode = "fieldReset"
 			web.arena.AllianceStationDisplayModeNotifier.Notify()
 			continue // Don't reload.
+		case "confirmFieldReset":
+			if err = web.arena.ConfirmFieldReset(); err != nil {
+				ws.WriteError(err.Error())
+			}
+			continue // Don't reload.
 		case "commitResults":
 			err = web.commitCurrentMatchScore()
 			if err != nil {
@@ -320,7 +362,7 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 			}
 			continue // Skip sending the status update, as the client is about to terminate and reload.
 		case "discardResults":
-			err = web.arena.ResetMatch()
+			err = web.arena.ForceReset()
 			if err != nil {
 				ws.WriteError(err.Error())
 				continue
@@ -376,15 +418,34 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 			web.arena.CurrentMatch.DisplayName = name
 			web.arena.MatchLoadNotifier.Notify()
 			continue
+		case "appendNote":
+			text, ok := data.(string)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			web.arena.AppendNote(text)
+		case "finalizeScores":
+			if err = web.arena.FinalizeScores(); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "updateRealtimeScore":
 			args := data.(map[string]interface{})
-			web.arena.BlueScore.AutoPoints = int(args["blueAuto"].(float64))
-			web.arena.RedScore.AutoPoints = int(args["redAuto"].(float64))
-			web.arena.BlueScore.TeleopPoints = int(args["blueTeleop"].(float64))
-			web.arena.RedScore.TeleopPoints = int(args["redTeleop"].(float64))
-			web.arena.BlueScore.EndgamePoints = int(args["blueEndgame"].(float64))
-			web.arena.RedScore.EndgamePoints = int(args["redEndgame"].(float64))
-			web.arena.RealtimeScoreNotifier.Notify()
+			redScore := game.Score{
+				AutoPoints:    int(args["redAuto"].(float64)),
+				TeleopPoints:  int(args["redTeleop"].(float64)),
+				EndgamePoints: int(args["redEndgame"].(float64)),
+			}
+			blueScore := game.Score{
+				AutoPoints:    int(args["blueAuto"].(float64)),
+				TeleopPoints:  int(args["blueTeleop"].(float64)),
+				EndgamePoints: int(args["blueEndgame"].(float64)),
+			}
+			if err = web.arena.SetScores(redScore, blueScore); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		default:
 			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
 			continue
@@ -403,7 +464,8 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchResult, isMatchReviewEdit bool) error {
 	var updatedRankings game.Rankings
 
-	if match.Type != "test" {
+	// Test matches are never recorded.
+	if !match.IsTest() {
 		if matchResult.PlayNumber == 0 {
 			// Determine the play number for this new match result.
 			prevMatchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
@@ -433,15 +495,28 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 		match.ScoreCommittedAt = time.Now()
 		redScoreSummary := matchResult.RedScoreSummary()
 		blueScoreSummary := matchResult.BlueScoreSummary()
-		match.Status = game.DetermineMatchStatus(redScoreSummary, blueScoreSummary)
+		match.Status = web.arena.DetermineMatchStatus(redScoreSummary, blueScoreSummary)
+		if match.ShouldUpdateEliminationMatches() {
+			// A red card in a playoff match disqualifies the entire alliance.
+			redDisqualified, blueDisqualified := matchResult.HasRedCard(true), matchResult.HasRedCard(false)
+			if redDisqualified && !blueDisqualified {
+				match.Status = game.BlueWonMatch
+			} else if blueDisqualified && !redDisqualified {
+				match.Status = game.RedWonMatch
+			}
+		}
+		// A newly committed result supersedes any earlier override of the result by the head referee.
+		match.OverrideStatus = game.MatchNotPlayed
+		match.OverrideReason = ""
 		err := web.arena.Database.UpdateMatch(match)
 		if err != nil {
 			return err
 		}
 
-		if match.ShouldUpdateRankings() {
+		rankingMatchTypes := web.arena.EventSettings.GetRankingMatchTypes()
+		if match.ShouldUpdateRankings(rankingMatchTypes) {
 			// Recalculate all the rankings.
-			rankings, err := tournament.CalculateRankings(web.arena.Database, isMatchReviewEdit)
+			rankings, err := tournament.CalculateRankings(web.arena.Database, rankingMatchTypes, isMatchReviewEdit)
 			if err != nil {
 				return err
 			}
@@ -484,7 +559,7 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 				if err = web.arena.TbaClient.PublishMatches(web.arena.Database); err != nil {
 					log.Printf("Failed to publish matches: %s", err.Error())
 				}
-				if match.ShouldUpdateRankings() {
+				if match.ShouldUpdateRankings(rankingMatchTypes) {
 					if err = web.arena.TbaClient.PublishRankings(web.arena.Database); err != nil {
 						log.Printf("Failed to publish rankings: %s", err.Error())
 					}
@@ -512,12 +587,17 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 }
 
 func (web *Web) getCurrentMatchResult() *model.MatchResult {
+	redCards, blueCards := web.arena.Cards()
+	redScore, blueScore := web.arena.Scores()
 	return &model.MatchResult{MatchId: web.arena.CurrentMatch.Id, MatchType: web.arena.CurrentMatch.Type,
-		RedScore: web.arena.RedScore, BlueScore: web.arena.BlueScore}
+		RedScore: &redScore, BlueScore: &blueScore, RedCards: redCards, BlueCards: blueCards}
 }
 
 // Saves the realtime result as the final score for the match currently loaded into the arena.
 func (web *Web) commitCurrentMatchScore() error {
+	if err := web.arena.CheckCanCommitResults(); err != nil {
+		return err
+	}
 	return web.commitMatchScore(web.arena.CurrentMatch, web.getCurrentMatchResult(), false)
 }
 
@@ -548,8 +628,8 @@ func (web *Web) buildMatchPlayList(matchType string) (MatchPlayList, error) {
 		matchPlayList[i].Id = match.Id
 		matchPlayList[i].DisplayName = match.TypePrefix() + match.DisplayName
 		matchPlayList[i].Time = match.Time.Local().Format("3:04 PM")
-		matchPlayList[i].Status = match.Status
-		switch match.Status {
+		matchPlayList[i].Status = match.OfficialStatus()
+		switch match.OfficialStatus() {
 		case game.RedWonMatch:
 			matchPlayList[i].ColorClass = "danger"
 		case game.BlueWonMatch:
@@ -175,6 +175,42 @@ func TestCommitMatch(t *testing.T) {
 	assert.Contains(t, writer.String(), "Failed to publish rankings")
 }
 
+func TestCommitMatchClearsOverride(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match := &model.Match{
+		Type: "qualification", DisplayName: "1", Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106,
+	}
+	assert.Nil(t, web.arena.Database.CreateMatch(match))
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.BlueScore = &game.Score{AutoPoints: 10}
+	assert.Nil(t, web.commitMatchScore(match, matchResult, false))
+	assert.Nil(t, web.arena.OverrideMatchResult(match.Id, field.RedAlliance, "Blue robot left the field early"))
+
+	// The match lists should show the overridden result.
+	matchPlayList, err := web.buildMatchPlayList("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, game.RedWonMatch, matchPlayList[0].Status)
+	assert.Equal(t, "danger", matchPlayList[0].ColorClass)
+	matchReviewList, err := web.buildMatchReviewList("qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, "danger", matchReviewList[0].ColorClass)
+
+	// Committing a new result should replace the override.
+	match, _ = web.arena.Database.GetMatchById(match.Id)
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.BlueScore = &game.Score{AutoPoints: 20}
+	assert.Nil(t, web.commitMatchScore(match, matchResult, false))
+	match, _ = web.arena.Database.GetMatchById(match.Id)
+	assert.False(t, match.IsResultOverridden())
+	assert.Equal(t, "", match.OverrideReason)
+	assert.Equal(t, game.BlueWonMatch, match.OfficialStatus())
+	matchPlayList, _ = web.buildMatchPlayList("qualification")
+	assert.Equal(t, game.BlueWonMatch, matchPlayList[0].Status)
+}
+
 func TestCommitEliminationTie(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -201,6 +237,27 @@ func TestCommitEliminationTie(t *testing.T) {
 	assert.Equal(t, game.TieMatch, match.Status)
 }
 
+func TestCommitEliminationRedCard(t *testing.T) {
+	web := setupTestWeb(t)
+	tournament.CreateTestAlliances(web.arena.Database, 8)
+	web.arena.CreatePlayoffBracket()
+
+	match := &model.Match{Type: "elimination", ElimRedAlliance: 1, ElimBlueAlliance: 2, Red1: 1, Red2: 2, Red3: 3,
+		Blue1: 4, Blue2: 5, Blue3: 6}
+	web.arena.Database.CreateMatch(match)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match.Id
+	matchResult.RedScore = &game.Score{AutoPoints: 20}
+	matchResult.RedCards["2"] = game.RedCard
+	assert.Nil(t, web.commitMatchScore(match, matchResult, true))
+
+	// A red card in a playoff match should disqualify the whole alliance regardless of score.
+	match, _ = web.arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, game.BlueWonMatch, match.Status)
+	dbMatchResult, _ := web.arena.Database.GetMatchResultForMatch(match.Id)
+	assert.Equal(t, map[string]int{"2": game.RedCard}, dbMatchResult.RedCards)
+}
+
 func TestMatchPlayWebsocketCommands(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -280,12 +337,13 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	})
 	readWebsocketType(t, ws, "arenaStatus")
 	readWebsocketType(t, ws, "realtimeScore")
-	assert.Equal(t, 20, web.arena.SavedMatchResult.RedScore.AutoPoints)
-	assert.Equal(t, 40, web.arena.SavedMatchResult.RedScore.TeleopPoints)
-	assert.Equal(t, 60, web.arena.SavedMatchResult.RedScore.EndgamePoints)
-	assert.Equal(t, 10, web.arena.SavedMatchResult.BlueScore.AutoPoints)
-	assert.Equal(t, 30, web.arena.SavedMatchResult.BlueScore.TeleopPoints)
-	assert.Equal(t, 50, web.arena.SavedMatchResult.BlueScore.EndgamePoints)
+	redScore, blueScore := web.arena.Scores()
+	assert.Equal(t, 20, redScore.AutoPoints)
+	assert.Equal(t, 40, redScore.TeleopPoints)
+	assert.Equal(t, 60, redScore.EndgamePoints)
+	assert.Equal(t, 10, blueScore.AutoPoints)
+	assert.Equal(t, 30, blueScore.TeleopPoints)
+	assert.Equal(t, 50, blueScore.EndgamePoints)
 	ws.Write("commitResults", nil)
 	readWebsocketMultiple(t, ws, 3) // reload, realtimeScore, setAllianceStationDisplay
 	assert.Equal(t, field.PreMatch, web.arena.MatchState)
@@ -340,7 +398,7 @@ func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	assert.True(t, ok)
 	_, ok = messages["eventStatus"]
 	assert.True(t, ok)
-	web.arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec) * time.Second)
+	web.arena.MatchStartTime = time.Now().Add(-time.Duration(web.arena.MatchTiming.WarmupDurationSec) * time.Second)
 	web.arena.Update()
 	messages = readWebsocketMultiple(t, ws, 2)
 	statusReceived, matchTime := getStatusMatchTime(t, messages)
@@ -365,13 +423,13 @@ func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	assert.Equal(t, 2, matchTime.MatchTimeSec)
 
 	// Check across a match state boundary.
-	web.arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec+
-		game.MatchTiming.AutoDurationSec) * time.Second)
+	web.arena.MatchStartTime = time.Now().Add(-time.Duration(web.arena.MatchTiming.WarmupDurationSec+
+		web.arena.MatchTiming.AutoDurationSec) * time.Second)
 	web.arena.Update()
 	statusReceived, matchTime = readWebsocketStatusMatchTime(t, ws)
 	assert.Equal(t, true, statusReceived)
 	assert.Equal(t, field.PausePeriod, matchTime.MatchState)
-	assert.Equal(t, game.MatchTiming.WarmupDurationSec+game.MatchTiming.AutoDurationSec, matchTime.MatchTimeSec)
+	assert.Equal(t, web.arena.MatchTiming.WarmupDurationSec+web.arena.MatchTiming.AutoDurationSec, matchTime.MatchTimeSec)
 }
 
 // Handles the status and matchTime messages arriving in either order.
@@ -126,8 +126,10 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 
 	if isCurrent {
 		// If editing the current match, just save it back to memory.
-		*web.arena.RedScore = *matchResult.RedScore
-		*web.arena.BlueScore = *matchResult.BlueScore
+		if err = web.arena.SetScores(*matchResult.RedScore, *matchResult.BlueScore); err != nil {
+			handleWebErr(w, err)
+			return
+		}
 
 		http.Redirect(w, r, "/match_play", 303)
 	} else {
@@ -194,7 +196,7 @@ func (web *Web) buildMatchReviewList(matchType string) ([]MatchReviewListItem, e
 			matchReviewList[i].RedScore = matchResult.RedScoreSummary().Score
 			matchReviewList[i].BlueScore = matchResult.BlueScoreSummary().Score
 		}
-		switch match.Status {
+		switch match.OfficialStatus() {
 		case game.RedWonMatch:
 			matchReviewList[i].ColorClass = "danger"
 			matchReviewList[i].IsComplete = true
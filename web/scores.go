@@ -71,23 +71,23 @@ type jsonScore struct {
 }
 
 func (web *Web) getScoresHandler(w http.ResponseWriter, r *http.Request) {
+	redScore, blueScore := web.arena.Scores()
 	json.NewEncoder(w).Encode(jsonScore{
 		Red: jsonAllianceScore{
-			Auto:    web.arena.RedScore.AutoPoints,
-			Teleop:  web.arena.RedScore.TeleopPoints,
-			Endgame: web.arena.RedScore.EndgamePoints,
+			Auto:    redScore.AutoPoints,
+			Teleop:  redScore.TeleopPoints,
+			Endgame: redScore.EndgamePoints,
 		},
 		Blue: jsonAllianceScore{
-			Auto:    web.arena.BlueScore.AutoPoints,
-			Teleop:  web.arena.BlueScore.TeleopPoints,
-			Endgame: web.arena.BlueScore.EndgamePoints,
+			Auto:    blueScore.AutoPoints,
+			Teleop:  blueScore.TeleopPoints,
+			Endgame: blueScore.EndgamePoints,
 		},
 	})
 }
 
 func (web *Web) setScoresHandler(w http.ResponseWriter, r *http.Request) {
-	if web.arena.MatchState == field.PreMatch || web.arena.MatchState == field.TimeoutActive ||
-		web.arena.MatchState == field.PostTimeout {
+	if !web.arena.MatchInProgress() && web.arena.MatchState != field.PostMatch {
 		http.Error(w, "Score cannot be updated in this match state", http.StatusBadRequest)
 		return
 	}
@@ -100,16 +100,18 @@ func (web *Web) setScoresHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	json.Unmarshal(reqBody, &scores)
 
+	redScore := game.Score{
+		AutoPoints: scores.Red.Auto, TeleopPoints: scores.Red.Teleop, EndgamePoints: scores.Red.Endgame,
+	}
+	blueScore := game.Score{
+		AutoPoints: scores.Blue.Auto, TeleopPoints: scores.Blue.Teleop, EndgamePoints: scores.Blue.Endgame,
+	}
 	if r.Method == "PUT" {
-		web.arena.RedScore = new(game.Score)
-		web.arena.BlueScore = new(game.Score)
+		err = web.arena.SetScores(redScore, blueScore)
+	} else {
+		err = web.arena.AddScores(redScore, blueScore)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 	}
-
-	web.arena.RedScore.AutoPoints += scores.Red.Auto
-	web.arena.RedScore.TeleopPoints += scores.Red.Teleop
-	web.arena.RedScore.EndgamePoints += scores.Red.Endgame
-	web.arena.BlueScore.AutoPoints += scores.Blue.Auto
-	web.arena.BlueScore.TeleopPoints += scores.Blue.Teleop
-	web.arena.BlueScore.EndgamePoints += scores.Blue.Endgame
-	web.arena.RealtimeScoreNotifier.Notify()
 }
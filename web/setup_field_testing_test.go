@@ -4,7 +4,6 @@
 package web
 
 import (
-	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +15,7 @@ func TestSetupFieldTesting(t *testing.T) {
 
 	recorder := web.getHttpResponse("/setup/field_testing")
 	assert.Equal(t, 200, recorder.Code)
-	for _, sound := range game.MatchSounds {
+	for _, sound := range web.arena.MatchSounds {
 		assert.Contains(t, recorder.Body.String(), sound.Name)
 	}
 }
@@ -14,10 +14,6 @@ import (
 	"time"
 )
 
-// Global vars to hold schedules that are in the process of being generated.
-var cachedMatches = make(map[string][]model.Match)
-var cachedTeamFirstMatches = make(map[string]map[int]string)
-
 // Shows the schedule editing page.
 func (web *Web) scheduleGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -86,7 +82,7 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		web.renderSchedule(w, r, fmt.Sprintf("Error generating schedule: %s.", err.Error()))
 		return
 	}
-	cachedMatches[matchType] = matches
+	web.cachedMatches[matchType] = matches
 
 	// Determine each team's first match.
 	teamFirstMatches := make(map[int]string)
@@ -104,7 +100,7 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		checkTeam(match.Blue2)
 		checkTeam(match.Blue3)
 	}
-	cachedTeamFirstMatches[matchType] = teamFirstMatches
+	web.cachedTeamFirstMatches[matchType] = teamFirstMatches
 
 	http.Redirect(w, r, "/setup/schedule?matchType="+matchType, 303)
 }
@@ -149,7 +145,7 @@ func (web *Web) scheduleSavePostHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	for _, match := range cachedMatches[matchType] {
+	for _, match := range web.cachedMatches[matchType] {
 		err = web.arena.Database.CreateMatch(&match)
 		if err != nil {
 			handleWebErr(w, err)
@@ -207,8 +203,8 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 		Matches          []model.Match
 		TeamFirstMatches map[int]string
 		ErrorMessage     string
-	}{web.arena.EventSettings, matchType, scheduleBlocks, len(teams), cachedMatches[matchType],
-		cachedTeamFirstMatches[matchType], errorMessage}
+	}{web.arena.EventSettings, matchType, scheduleBlocks, len(teams), web.cachedMatches[matchType],
+		web.cachedTeamFirstMatches[matchType], errorMessage}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
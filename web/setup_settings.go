@@ -8,6 +8,7 @@ package web
 import (
 	"fmt"
 	"github.com/Team254/cheesy-arena-lite/model"
+	"github.com/Team254/cheesy-arena-lite/tournament"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -17,6 +18,12 @@ import (
 	"time"
 )
 
+// Limits on retrying driver station connections, so that a team that is genuinely absent still fails promptly.
+const (
+	maxDsConnectRetries = 8
+	maxDsConnectWaitMs  = 10000
+)
+
 // Shows the event settings editing page.
 func (web *Web) settingsGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -81,6 +88,66 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.PauseDurationSec, _ = strconv.Atoi(r.PostFormValue("pauseDurationSec"))
 	eventSettings.TeleopDurationSec, _ = strconv.Atoi(r.PostFormValue("teleopDurationSec"))
 	eventSettings.WarningRemainingDurationSec, _ = strconv.Atoi(r.PostFormValue("warningRemainingDurationSec"))
+	eventSettings.AutoEstopLatchesThroughMatch = r.PostFormValue("autoEstopLatchesThroughMatch") == "on"
+	eventSettings.MinLinkStableSec, _ = strconv.Atoi(r.PostFormValue("minLinkStableSec"))
+	eventSettings.MinRobotsToStartMatch, _ = strconv.Atoi(r.PostFormValue("minRobotsToStartMatch"))
+	eventSettings.AutoBypassAfterSec, _ = strconv.Atoi(r.PostFormValue("autoBypassAfterSec"))
+	eventSettings.RequireRobotCodeToStart = r.PostFormValue("requireRobotCodeToStart") == "on"
+	eventSettings.RequireReplayToRestart = r.PostFormValue("requireReplayToRestart") == "on"
+	eventSettings.RequireFieldResetConfirm = r.PostFormValue("requireFieldResetConfirm") == "on"
+	eventSettings.DsPacketSpacingMs, _ = strconv.Atoi(r.PostFormValue("dsPacketSpacingMs"))
+	eventSettings.DsConnectRetries, _ = strconv.Atoi(r.PostFormValue("dsConnectRetries"))
+	eventSettings.DsConnectBackoffMs, _ = strconv.Atoi(r.PostFormValue("dsConnectBackoffMs"))
+	eventSettings.BatteryEmaFactor, _ = strconv.ParseFloat(r.PostFormValue("batteryEmaFactor"), 64)
+	eventSettings.EnforceEventRoster = r.PostFormValue("enforceEventRoster") == "on"
+	eventSettings.EventRoster = nil
+	for _, teamNumberString := range strings.Fields(r.PostFormValue("eventRoster")) {
+		teamNumber, err := strconv.Atoi(teamNumberString)
+		if err != nil || teamNumber <= 0 {
+			web.renderSettings(w, r, fmt.Sprintf("Invalid team number '%s' in the event roster.", teamNumberString))
+			return
+		}
+		eventSettings.EventRoster = append(eventSettings.EventRoster, teamNumber)
+	}
+	eventSettings.EnableDelayMs, _ = strconv.Atoi(r.PostFormValue("enableDelayMs"))
+	eventSettings.LoopOverrunEstopThresholdMs, _ = strconv.Atoi(r.PostFormValue("loopOverrunEstopThresholdMs"))
+	eventSettings.LoopOverrunEstopWindowMs, _ = strconv.Atoi(r.PostFormValue("loopOverrunEstopWindowMs"))
+	// Checked before it is applied, since the arena acts on the settings straight away and a zero delay would abort the
+	// match on the first cycle.
+	autoAbortOnTotalLoss := r.PostFormValue("autoAbortOnTotalLoss") == "on"
+	totalLossAbortAfterMs, _ := strconv.Atoi(r.PostFormValue("totalLossAbortAfterMs"))
+	if autoAbortOnTotalLoss && totalLossAbortAfterMs <= 0 {
+		web.renderSettings(w, r, "The time before aborting a match with all robots unlinked must be positive.")
+		return
+	}
+	eventSettings.AutoAbortOnTotalLoss = autoAbortOnTotalLoss
+	eventSettings.TotalLossAbortAfterMs = totalLossAbortAfterMs
+	previousRankingMatchTypes := strings.Join(eventSettings.GetRankingMatchTypes(), ",")
+	eventSettings.RankingMatchTypes = r.PostForm["rankingMatchTypes"]
+	manualScoring := r.PostFormValue("manualScoring") == "on"
+	if manualScoring != eventSettings.ManualScoring && web.arena.MatchInProgress() {
+		web.renderSettings(w, r, "Cannot change the scoring mode while a match is in progress.")
+		return
+	}
+	eventSettings.ManualScoring = manualScoring
+
+	if eventSettings.WarmupDurationSec < 0 || eventSettings.AutoDurationSec < 0 || eventSettings.PauseDurationSec < 0 ||
+		eventSettings.TeleopDurationSec < 0 || eventSettings.WarningRemainingDurationSec < 0 {
+		web.renderSettings(w, r, "Match period durations cannot be negative.")
+		return
+	}
+
+	if eventSettings.DsConnectRetries < 0 || eventSettings.DsConnectRetries > maxDsConnectRetries ||
+		eventSettings.DsConnectBackoffMs < 0 || eventSettings.DsConnectMaxWaitMs() > maxDsConnectWaitMs {
+		web.renderSettings(w, r, fmt.Sprintf("Driver station connection retries must be between 0 and %d and must not "+
+			"wait more than %d ms in total.", maxDsConnectRetries, maxDsConnectWaitMs))
+		return
+	}
+
+	if eventSettings.EnforceEventRoster && len(eventSettings.EventRoster) == 0 {
+		web.renderSettings(w, r, "The event roster must list at least one team in order to be enforced.")
+		return
+	}
 
 	if eventSettings.Ap2TeamChannel != 0 && eventSettings.Ap2TeamChannel == eventSettings.ApTeamChannel {
 		web.renderSettings(w, r, "Cannot use same channel for both access points.")
@@ -100,6 +167,15 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Recalculate the rankings if the match types which count toward them have changed.
+	rankingMatchTypes := web.arena.EventSettings.GetRankingMatchTypes()
+	if strings.Join(rankingMatchTypes, ",") != previousRankingMatchTypes {
+		if _, err = tournament.CalculateRankings(web.arena.Database, rankingMatchTypes, false); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
 	if eventSettings.AdminPassword != previousAdminPassword {
 		// Delete any existing user sessions to force a logout.
 		if err := web.arena.Database.TruncateUserSessions(); err != nil {
@@ -181,11 +257,12 @@ func (web *Web) restoreDbHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
-	web.arena.Database, err = model.OpenDatabase(web.arena.Database.Path)
+	database, err := model.OpenDatabase(web.arena.Database.Path)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
+	web.arena.SetDatabase(database)
 	err = web.arena.LoadSettings()
 	if err != nil {
 		handleWebErr(w, err)
@@ -229,7 +306,7 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.cachedRankedTeams = []*RankedTeam{}
 
 	http.Redirect(w, r, "/setup/settings", 303)
 }
@@ -5,6 +5,7 @@ package web
 
 import (
 	"bytes"
+	"github.com/Team254/cheesy-arena-lite/field"
 	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/model"
 	"github.com/Team254/cheesy-arena-lite/tournament"
@@ -25,11 +26,14 @@ func TestSetupSettings(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Untitled Event")
 	assert.Contains(t, recorder.Body.String(), "8")
 	assert.NotContains(t, recorder.Body.String(), "tbaPublishingEnabled\" checked")
+	assert.NotContains(t, recorder.Body.String(), "autoEstopLatchesThroughMatch\" checked")
 
 	// Change the settings and check the response.
 	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&code=CC&elimType=single&numElimAlliances=16&"+
-		"tbaPublishingEnabled=on&tbaEventCode=2014cc&tbaSecretId=secretId&tbaSecret=tbasec")
+		"tbaPublishingEnabled=on&tbaEventCode=2014cc&tbaSecretId=secretId&tbaSecret=tbasec&"+
+		"autoEstopLatchesThroughMatch=on")
 	assert.Equal(t, 303, recorder.Code)
+	assert.True(t, web.arena.AstopLatchesThroughMatch)
 	recorder = web.getHttpResponse("/setup/settings")
 	assert.Contains(t, recorder.Body.String(), "Chezy Champs")
 	assert.Contains(t, recorder.Body.String(), "16")
@@ -37,6 +41,7 @@ func TestSetupSettings(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "2014cc")
 	assert.Contains(t, recorder.Body.String(), "secretId")
 	assert.Contains(t, recorder.Body.String(), "tbasec")
+	assert.Contains(t, recorder.Body.String(), "autoEstopLatchesThroughMatch\" checked")
 }
 
 func TestSetupSettingsDoubleElimination(t *testing.T) {
@@ -54,6 +59,41 @@ func TestSetupSettingsInvalidValues(t *testing.T) {
 	// Invalid number of alliances.
 	recorder := web.postHttpResponse("/setup/settings", "numAlliances=1")
 	assert.Contains(t, recorder.Body.String(), "must be between 2 and 16")
+
+	// Negative match period duration.
+	recorder = web.postHttpResponse("/setup/settings", "numElimAlliances=8&teleopDurationSec=-1")
+	assert.Contains(t, recorder.Body.String(), "cannot be negative")
+
+	// A zero-length teleop period is allowed for auto-only demos.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&autoDurationSec=15&"+
+		"teleopDurationSec=0")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 0, web.arena.EventSettings.TeleopDurationSec)
+
+	// The total loss abort needs a positive delay, since a zero delay would abort on the first cycle.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&autoAbortOnTotalLoss=on&"+
+		"totalLossAbortAfterMs=0")
+	assert.Contains(t, recorder.Body.String(), "must be positive")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&autoAbortOnTotalLoss=on&"+
+		"totalLossAbortAfterMs=3s")
+	assert.Contains(t, recorder.Body.String(), "must be positive")
+	assert.False(t, web.arena.EventSettings.AutoAbortOnTotalLoss)
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&autoAbortOnTotalLoss=on&"+
+		"totalLossAbortAfterMs=2500")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 2500, web.arena.EventSettings.TotalLossAbortAfterMs)
+
+	// Driver station connection retries must give up within a bounded time.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&dsConnectRetries=-1")
+	assert.Contains(t, recorder.Body.String(), "must be between 0 and 8")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&dsConnectRetries=8&"+
+		"dsConnectBackoffMs=100")
+	assert.Contains(t, recorder.Body.String(), "must not wait more than 10000 ms in total")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&dsConnectRetries=5&"+
+		"dsConnectBackoffMs=100")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 5, web.arena.EventSettings.DsConnectRetries)
+	assert.Equal(t, 3100, web.arena.EventSettings.DsConnectMaxWaitMs())
 }
 
 func TestSetupSettingsClearDb(t *testing.T) {
@@ -73,7 +113,7 @@ func TestSetupSettingsClearDb(t *testing.T) {
 	assert.Empty(t, matches)
 	rankings, _ := web.arena.Database.GetAllRankings()
 	assert.Empty(t, rankings)
-	tournament.CalculateRankings(web.arena.Database, false)
+	tournament.CalculateRankings(web.arena.Database, []string{"qualification"}, false)
 	assert.Empty(t, rankings)
 	alliances, _ := web.arena.Database.GetAllAlliances()
 	assert.Empty(t, alliances)
@@ -125,3 +165,83 @@ func (web *Web) postFileHttpResponse(path string, paramName string, file *bytes.
 	web.newHandler().ServeHTTP(recorder, req)
 	return recorder
 }
+
+func TestSetupSettingsRankingMatchTypes(t *testing.T) {
+	web := setupTestWeb(t)
+	match := model.Match{Type: "practice", DisplayName: "P1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	web.arena.Database.CreateMatch(&match)
+	web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(match.Id, 1))
+
+	recorder := web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), `value="qualification" checked`)
+	assert.NotContains(t, recorder.Body.String(), `value="practice" checked`)
+
+	// Counting practice matches should be saved and should bring them into the rankings straight away.
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8&"+
+		"rankingMatchTypes=qualification&rankingMatchTypes=practice&rankingMatchTypes=practice")
+	assert.Equal(t, 303, recorder.Code)
+	assert.True(t, web.arena.EventSettings.CountsTowardRankings("practice"))
+	assert.Equal(t, []string{"qualification", "practice"}, web.arena.EventSettings.GetRankingMatchTypes())
+	ranking, _ := web.arena.Database.GetRankingForTeam(1)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 1, ranking.Played)
+	}
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), `value="practice" checked`)
+
+	// Selecting no types falls back to qualification matches only.
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []string{"qualification"}, web.arena.EventSettings.GetRankingMatchTypes())
+	ranking, _ = web.arena.Database.GetRankingForTeam(1)
+	assert.Nil(t, ranking)
+}
+
+func TestSetupSettingsManualScoring(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8&"+
+		"manualScoring=on")
+	assert.Equal(t, 303, recorder.Code)
+	assert.True(t, web.arena.ManualScoring())
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), `name="manualScoring" checked`)
+
+	// The scoring mode can't be changed partway through a match.
+	web.arena.MatchState = field.TeleopPeriod
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8")
+	assert.Contains(t, recorder.Body.String(), "Cannot change the scoring mode while a match is in progress.")
+	assert.True(t, web.arena.ManualScoring())
+	web.arena.MatchState = field.PostMatch
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8")
+	assert.Equal(t, 303, recorder.Code)
+	assert.False(t, web.arena.ManualScoring())
+}
+
+func TestSetupSettingsEventRoster(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8&"+
+		"enforceEventRoster=on&eventRoster=")
+	assert.Contains(t, recorder.Body.String(), "must list at least one team")
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8&"+
+		"enforceEventRoster=on&eventRoster=254%0D%0Afrc1114")
+	assert.Contains(t, recorder.Body.String(), "Invalid team number 'frc1114' in the event roster.")
+
+	recorder = web.postHttpResponse("/setup/settings", "name=Chezy Champs&elimType=single&numElimAlliances=8&"+
+		"enforceEventRoster=on&eventRoster=254%0D%0A1114")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []int{254, 1114}, web.arena.EventSettings.EventRoster)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "254\n1114\n</textarea>")
+
+	// The saved roster should be the one enforced by the arena.
+	web.arena.Database.CreateTeam(&model.Team{Id: 1114})
+	web.arena.Database.CreateTeam(&model.Team{Id: 1678})
+	match := model.Match{Type: "qualification", Red1: 1114, Blue1: 1678}
+	web.arena.Database.CreateMatch(&match)
+	assert.NotNil(t, web.arena.LoadMatch(&match))
+	match.Blue1 = 254
+	assert.Nil(t, web.arena.LoadMatch(&match))
+}
@@ -25,10 +25,21 @@ const (
 type Web struct {
 	arena           *field.Arena
 	templateHelpers template.FuncMap
+
+	// Team rankings during the alliance selection.
+	cachedRankedTeams []*RankedTeam
+
+	// Schedules that are in the process of being generated, keyed by match type.
+	cachedMatches          map[string][]model.Match
+	cachedTeamFirstMatches map[string]map[int]string
 }
 
 func NewWeb(arena *field.Arena) *Web {
-	web := &Web{arena: arena}
+	web := &Web{
+		arena:                  arena,
+		cachedMatches:          make(map[string][]model.Match),
+		cachedTeamFirstMatches: make(map[string]map[int]string),
+	}
 
 	// Helper functions that can be used inside templates.
 	web.templateHelpers = template.FuncMap{
@@ -70,12 +81,14 @@ func NewWeb(arena *field.Arena) *Web {
 
 // Starts the webserver and blocks, waiting on requests. Does not return until the application exits.
 func (web *Web) ServeWebInterface(port int) {
-	http.Handle("/static/", http.StripPrefix("/static/", addNoCacheHeader(http.FileServer(http.Dir("static/")))))
-	http.Handle("/", web.newHandler())
+	// Use a dedicated mux rather than the global default one so that multiple instances can serve concurrently.
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/static/", http.StripPrefix("/static/", addNoCacheHeader(http.FileServer(http.Dir("static/")))))
+	serveMux.Handle("/", web.newHandler())
 	log.Printf("Serving HTTP requests on port %d", port)
 
 	// Start Server
-	http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+	http.ListenAndServe(fmt.Sprintf(":%d", port), serveMux)
 }
 
 // Serves the root page of Cheesy Arena.
@@ -110,6 +123,7 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/alliance_selection", web.allianceSelectionGetHandler).Methods("GET")
 	router.HandleFunc("/alliance_selection", web.allianceSelectionPostHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/finalize", web.allianceSelectionFinalizeHandler).Methods("POST")
+	router.HandleFunc("/alliance_selection/pick", web.allianceSelectionPickHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/publish", web.allianceSelectionPublishHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/reset", web.allianceSelectionResetHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/start", web.allianceSelectionStartHandler).Methods("POST")
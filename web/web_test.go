@@ -5,7 +5,6 @@ package web
 
 import (
 	"github.com/Team254/cheesy-arena-lite/field"
-	"github.com/Team254/cheesy-arena-lite/game"
 	"github.com/Team254/cheesy-arena-lite/websocket"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -100,8 +99,6 @@ func readWebsocketMultiple(t *testing.T, ws *websocket.Websocket, count int) map
 }
 
 func setupTestWeb(t *testing.T) *Web {
-	game.MatchTiming.WarmupDurationSec = 3
-	game.MatchTiming.PauseDurationSec = 2
 	arena := field.SetupTestArena(t, "web")
 	return NewWeb(arena)
 }